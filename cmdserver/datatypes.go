@@ -14,6 +14,8 @@
 
 package cmdserver
 
+import "context"
+
 // Input contains the command input data as received from
 // Diatheke.
 type Input struct {
@@ -55,8 +57,37 @@ type Output struct {
 	// won't need to set this field directly as it set by the
 	// server when an error is returned from the handler.
 	Error string `json:"error,omitempty"`
+
+	// The HTTP status code of the response, e.g. 422 for a
+	// command with invalid parameters. It is not sent to
+	// Diatheke in the JSON, and is ignored by GRPCServer. Zero
+	// means 200 (OK), or 500 (Internal Server Error) if the
+	// handler returns an error.
+	StatusCode int `json:"-"`
 }
 
 // Handler is a function that takes command input and sets
 // the command output that is expected by a Diatheke command.
 type Handler func(in Input, out *Output) error
+
+// HandlerContext is a Handler that also receives the context of
+// the Diatheke request, which is cancelled when the request is
+// aborted or the server's timeout expires.
+type HandlerContext func(ctx context.Context, in Input, out *Output) error
+
+// withContext returns the Handler as a HandlerContext that
+// ignores the context.
+func (h Handler) withContext() HandlerContext {
+	if h == nil {
+		return nil
+	}
+
+	return func(_ context.Context, in Input, out *Output) error {
+		return h(in, out)
+	}
+}
+
+// Middleware wraps a Handler with cross-cutting logic, such as
+// timing, logging or panic recovery, by returning a Handler
+// that calls the wrapped one.
+type Middleware func(Handler) Handler
@@ -4,5 +4,10 @@ go 1.16
 
 require (
 	github.com/cobaltspeech/log v0.1.11
-	github.com/google/go-cmp v0.5.1
+	github.com/golang/protobuf v1.5.2
+	github.com/google/go-cmp v0.5.5
+	golang.org/x/net v0.16.0 // indirect
+	google.golang.org/genproto v0.0.0-20210909211513-a8c4777a87af // indirect
+	google.golang.org/grpc v1.40.0
+	google.golang.org/protobuf v1.27.1
 )
@@ -0,0 +1,181 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/cobaltspeech/examples-go/cmdserver/cmdserverpb"
+	"github.com/cobaltspeech/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCServer handles Diatheke commands received with the
+// ExecuteCommand RPC of the cmdserverpb.CommandServer service,
+// as an alternative to the http transport of Server. Handlers
+// are registered and selected the same way as with Server, so
+// the same Handler functions work with both transports.
+type GRPCServer struct {
+	svr Server
+}
+
+// Verify that GRPCServer implements the service.
+var _ cmdserverpb.CommandServerServer = (*GRPCServer)(nil)
+
+// NewGRPCServer returns a new gRPC command server.
+func NewGRPCServer(logger log.Logger) *GRPCServer {
+	return &GRPCServer{svr: NewServer(logger)}
+}
+
+// SetCommand registers the provided Handler to be called for
+// the specified command ID. See Server.SetCommand for the
+// precedence of the handlers.
+func (gs *GRPCServer) SetCommand(cmdID string, h Handler) {
+	gs.svr.SetCommand(cmdID, h)
+}
+
+// SetCommandContext registers the provided HandlerContext to be
+// called for the specified command ID, with the context of the
+// RPC. See Server.SetCommandContext.
+func (gs *GRPCServer) SetCommandContext(cmdID string, h HandlerContext) {
+	gs.svr.SetCommandContext(cmdID, h)
+}
+
+// SetModel registers the provided Handler to be called for the
+// specified model ID. See Server.SetCommand for the precedence
+// of the handlers.
+func (gs *GRPCServer) SetModel(modelID string, h Handler) {
+	gs.svr.SetModel(modelID, h)
+}
+
+// SetModelCommand registers the provided Handler to be called
+// for the given model and command ID combination. See
+// Server.SetCommand for the precedence of the handlers.
+func (gs *GRPCServer) SetModelCommand(modelID, cmdID string, h Handler) {
+	gs.svr.SetModelCommand(modelID, cmdID, h)
+}
+
+// SetDefault registers the provided Handler to be called for
+// commands that have no other handler. Without a default
+// handler, the server responds to unknown commands with a
+// NotFound error.
+func (gs *GRPCServer) SetDefault(h Handler) {
+	gs.svr.SetDefault(h)
+}
+
+// SetTimeout sets the maximum duration of each command, as
+// Server.SetTimeout does.
+func (gs *GRPCServer) SetTimeout(d time.Duration) {
+	gs.svr.SetTimeout(d)
+}
+
+// Use adds middleware that wraps every command handler, as
+// Server.Use does.
+func (gs *GRPCServer) Use(mw ...Middleware) {
+	gs.svr.Use(mw...)
+}
+
+// ExecuteCommand implements the cmdserverpb.CommandServerServer
+// interface. It converts the request to an Input, forwards it to
+// the correct command Handler, and converts the Output to the
+// response. Errors returned by the handler are sent in the
+// response's error field, as the http transport does, and the
+// Output's StatusCode is ignored.
+func (gs *GRPCServer) ExecuteCommand(ctx context.Context,
+	req *cmdserverpb.ExecuteCommandRequest) (*cmdserverpb.ExecuteCommandResponse, error) {
+	input := Input{
+		ModelID:    req.GetModelId(),
+		CommandID:  req.GetCommandId(),
+		SessionID:  req.GetSessionId(),
+		Parameters: Params(req.GetInputParameters()),
+		Metadata:   req.GetMetadata(),
+	}
+
+	output, err := gs.svr.execute(ctx, input)
+	if errors.Is(err, errNoHandler) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	if err != nil {
+		output.Error = err.Error()
+	}
+
+	return &cmdserverpb.ExecuteCommandResponse{
+		CommandId:     output.CommandID,
+		OutParameters: output.Parameters,
+		Metadata:      output.Metadata,
+		Error:         output.Error,
+	}, nil
+}
+
+// Run starts the gRPC server and listens at the given address
+// (e.g., ":8072", "localhost:1515", "127.0.0.1:3535") until
+// either an error occurs or the interrupt signal is received.
+// Servers that need other options, such as TLS credentials, can
+// register the GRPCServer on their own grpc.Server with
+// cmdserverpb.RegisterCommandServerServer instead.
+func (gs *GRPCServer) Run(address string) error {
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	// Get the address in case ":0" was used as the port number,
+	// in which case a port was automatically selected.
+	lisAddr, ok := lis.Addr().(*net.TCPAddr)
+	if !ok {
+		return fmt.Errorf("unable to get listener network address")
+	}
+
+	gsvr := grpc.NewServer()
+	cmdserverpb.RegisterCommandServerServer(gsvr, gs)
+
+	// Serve in a different go routine so that we can still
+	// respond to the keyboard interrupt.
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- gsvr.Serve(lis)
+	}()
+	gs.svr.logger.Info(
+		"msg", "server started",
+		"grpcAddr", lisAddr.String(),
+	)
+
+	// Catch the interrupt signal to gracefully shutdown the server
+	const maxInterrupts = 10
+	interrupt := make(chan os.Signal, maxInterrupts)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case err = <-errCh:
+		return err
+
+	case <-interrupt:
+		gs.svr.logger.Info("msg", "shutting down grpc server...")
+		gsvr.GracefulStop()
+
+		return nil
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/cobaltspeech/examples-go/cmdserver/cmdserverpb"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+// newTestGRPCClient serves the server on an in-memory connection and
+// returns a client connected to it.
+func newTestGRPCClient(t *testing.T, gs *GRPCServer) cmdserverpb.CommandServerClient {
+	t.Helper()
+
+	const bufSize = 1024 * 1024
+
+	lis := bufconn.Listen(bufSize)
+	gsvr := grpc.NewServer()
+	cmdserverpb.RegisterCommandServerServer(gsvr, gs)
+
+	go func() {
+		if err := gsvr.Serve(lis); err != nil {
+			t.Errorf("gRPC server failed: %v", err)
+		}
+	}()
+
+	t.Cleanup(gsvr.Stop)
+
+	dial := func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(dial), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+
+	return cmdserverpb.NewCommandServerClient(conn)
+}
+
+func TestGRPCExecuteCommand(t *testing.T) {
+	t.Parallel()
+
+	gs := NewGRPCServer(nil)
+	gs.SetModel("m1", func(in Input, out *Output) error {
+		return errors.New("model handler called instead of the command handler")
+	})
+	gs.SetModelCommand("m1", "c1", func(in Input, out *Output) error {
+		if in.SessionID != "s1" || in.Parameters["a"] != "x" {
+			return errors.New("wrong input")
+		}
+
+		out.Parameters["b"] = "y"
+		out.Metadata = in.Metadata + "+c1"
+
+		return nil
+	})
+	gs.SetCommand("fail", func(in Input, out *Output) error {
+		return errors.New("command failed")
+	})
+
+	client := newTestGRPCClient(t, gs)
+
+	tests := []struct {
+		name string
+		req  *cmdserverpb.ExecuteCommandRequest
+		want *cmdserverpb.ExecuteCommandResponse
+	}{
+		{
+			name: "success",
+			req: &cmdserverpb.ExecuteCommandRequest{
+				ModelId:         "m1",
+				CommandId:       "c1",
+				SessionId:       "s1",
+				InputParameters: map[string]string{"a": "x"},
+				Metadata:        "meta",
+			},
+			want: &cmdserverpb.ExecuteCommandResponse{
+				CommandId:     "c1",
+				OutParameters: map[string]string{"b": "y"},
+				Metadata:      "meta+c1",
+			},
+		},
+		{
+			name: "handler error",
+			req:  &cmdserverpb.ExecuteCommandRequest{CommandId: "fail", Metadata: "meta"},
+			want: &cmdserverpb.ExecuteCommandResponse{
+				CommandId: "fail",
+				Metadata:  "meta",
+				Error:     "command failed",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := client.ExecuteCommand(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected response (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGRPCUnknownCmd(t *testing.T) {
+	t.Parallel()
+
+	gs := NewGRPCServer(nil)
+	gs.SetCommand("cmd1", func(in Input, out *Output) error { return nil })
+
+	client := newTestGRPCClient(t, gs)
+
+	_, err := client.ExecuteCommand(context.Background(),
+		&cmdserverpb.ExecuteCommandRequest{CommandId: "unknown"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("got error %v, want NotFound", err)
+	}
+
+	gs = NewGRPCServer(nil)
+	gs.SetDefault(func(in Input, out *Output) error { return nil })
+
+	client = newTestGRPCClient(t, gs)
+
+	if _, err := client.ExecuteCommand(context.Background(),
+		&cmdserverpb.ExecuteCommandRequest{CommandId: "unknown"}); err != nil {
+		t.Errorf("unexpected error with a default handler: %v", err)
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdserver
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Outcomes of a command, used as the "outcome" metric label.
+const (
+	outcomeOK       = "ok"
+	outcomeError    = "error"
+	outcomeNotFound = "not-found"
+)
+
+const metricsPath = "/metrics"
+
+// unknownCommand is the "cmd" label of not-found commands. Their IDs come
+// from the requests, so they are not used as labels: any client could
+// otherwise add series to the metrics without bound.
+const unknownCommand = "unknown"
+
+// latencyBuckets are the upper bounds, in seconds, of the handler
+// latency histogram buckets. They match the Prometheus client
+// library defaults.
+var latencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10} //nolint:gomnd // bucket bounds
+
+type commandOutcome struct {
+	cmdID   string
+	outcome string
+}
+
+type latencyHistogram struct {
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// metrics records command counts and handler latencies, and writes
+// them in the Prometheus text exposition format. It is written by
+// hand so the package does not depend on the Prometheus client.
+type metrics struct {
+	mu        sync.Mutex
+	commands  map[commandOutcome]uint64
+	latencies map[string]*latencyHistogram
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		commands:  make(map[commandOutcome]uint64),
+		latencies: make(map[string]*latencyHistogram),
+	}
+}
+
+// observe records a command with the given outcome. Not-found
+// commands are all recorded under the unknownCommand ID. The
+// latency is only recorded if a handler was run.
+func (m *metrics) observe(cmdID, outcome string, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if outcome == outcomeNotFound {
+		m.commands[commandOutcome{cmdID: unknownCommand, outcome: outcome}]++
+
+		return
+	}
+
+	m.commands[commandOutcome{cmdID: cmdID, outcome: outcome}]++
+
+	h, ok := m.latencies[cmdID]
+	if !ok {
+		h = &latencyHistogram{counts: make([]uint64, len(latencyBuckets))}
+		m.latencies[cmdID] = h
+	}
+
+	sec := latency.Seconds()
+	h.sum += sec
+	h.count++
+
+	for i, le := range latencyBuckets {
+		if sec <= le {
+			h.counts[i]++
+			break
+		}
+	}
+}
+
+// write writes the metrics in the Prometheus text format.
+func (m *metrics) write(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP cmdserver_commands_total Commands received, by command ID and outcome.\n")
+	b.WriteString("# TYPE cmdserver_commands_total counter\n")
+
+	keys := make([]commandOutcome, 0, len(m.commands))
+	for k := range m.commands {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].cmdID != keys[j].cmdID {
+			return keys[i].cmdID < keys[j].cmdID
+		}
+
+		return keys[i].outcome < keys[j].outcome
+	})
+
+	for _, k := range keys {
+		fmt.Fprintf(&b, "cmdserver_commands_total{cmd=\"%s\",outcome=\"%s\"} %d\n",
+			escapeLabel(k.cmdID), k.outcome, m.commands[k])
+	}
+
+	b.WriteString("# HELP cmdserver_handler_duration_seconds Command handler latency, by command ID.\n")
+	b.WriteString("# TYPE cmdserver_handler_duration_seconds histogram\n")
+
+	cmdIDs := make([]string, 0, len(m.latencies))
+	for id := range m.latencies {
+		cmdIDs = append(cmdIDs, id)
+	}
+
+	sort.Strings(cmdIDs)
+
+	for _, id := range cmdIDs {
+		h := m.latencies[id]
+		label := escapeLabel(id)
+
+		var cumulative uint64
+
+		for i, le := range latencyBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(&b, "cmdserver_handler_duration_seconds_bucket{cmd=\"%s\",le=\"%g\"} %d\n",
+				label, le, cumulative)
+		}
+
+		fmt.Fprintf(&b, "cmdserver_handler_duration_seconds_bucket{cmd=\"%s\",le=\"+Inf\"} %d\n", label, h.count)
+		fmt.Fprintf(&b, "cmdserver_handler_duration_seconds_sum{cmd=\"%s\"} %g\n", label, h.sum)
+		fmt.Fprintf(&b, "cmdserver_handler_duration_seconds_count{cmd=\"%s\"} %d\n", label, h.count)
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel escapes a label value for the Prometheus text format.
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+// EnableMetrics makes the server record the number of commands,
+// by command ID and outcome (ok, error or not-found), and the
+// latency of command handlers. Commands without a handler are
+// counted under the "unknown" command ID. The metrics are served in the
+// Prometheus text format for GET requests on the "/metrics" path.
+// It must be called before the server is run.
+func (svr *Server) EnableMetrics() {
+	if svr.metrics == nil {
+		svr.metrics = newMetrics()
+	}
+}
+
+// serveMetrics responds to requests for the metrics path if
+// metrics are enabled, returning false otherwise.
+func (svr *Server) serveMetrics(w http.ResponseWriter, r *http.Request) bool {
+	if svr.metrics == nil || r.URL.Path != metricsPath {
+		return false
+	}
+
+	if !allowGet(w, r) {
+		return true
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	if err := svr.metrics.write(w); err != nil {
+		svr.logger.Error(
+			"msg", "failed to write metrics",
+			"error", err,
+		)
+	}
+
+	return true
+}
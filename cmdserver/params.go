@@ -15,8 +15,10 @@
 package cmdserver
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"time"
 )
 
 // Params is an alias for a map[string]string that
@@ -25,9 +27,27 @@ import (
 // the go standard make function (e.g., `make(Params)`).
 // Note that the convenience functions are not safe
 // to use concurrently (just as it is not safe to
-// access a regular map in Go concurrently).
+// access a regular map in Go concurrently). Use Clone
+// to give read-only consumers their own snapshot.
 type Params map[string]string
 
+// Clone returns a copy of the parameters that does not share
+// the underlying map, so changes to the original (including
+// concurrent ones made after Clone returns) are not visible
+// in the copy. Cloning a nil Params returns nil.
+func (p Params) Clone() Params {
+	if p == nil {
+		return nil
+	}
+
+	c := make(Params, len(p))
+	for k, v := range p {
+		c[k] = v
+	}
+
+	return c
+}
+
 // AsString returns the parameter value for the given key
 // as a string. Returns an error if the key was not found.
 // Note that the underlying map may be used directly to
@@ -121,3 +141,71 @@ func (p Params) AsBool(key string) (bool, error) {
 func (p Params) SetBool(key string, val bool) {
 	p[key] = strconv.FormatBool(val)
 }
+
+// AsStringSlice returns the parameter value for the given key
+// as a slice of strings, decoded from a JSON array (e.g.,
+// `["a", "b, c"]`). Returns an error if the key was not found
+// or the value is not a valid JSON array of strings.
+func (p Params) AsStringSlice(key string) ([]string, error) {
+	val, err := p.AsString(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var vals []string
+	if err := json.Unmarshal([]byte(val), &vals); err != nil {
+		return nil, fmt.Errorf("invalid JSON string array for key %q: %w", key, err)
+	}
+
+	return vals, nil
+}
+
+// SetStringSlice encodes the given strings as a JSON array and
+// stores it in the parameter map. A nil slice is stored as an
+// empty array.
+func (p Params) SetStringSlice(key string, vals []string) {
+	if vals == nil {
+		vals = []string{}
+	}
+
+	// Encoding a slice of strings can't fail.
+	data, _ := json.Marshal(vals)
+	p[key] = string(data)
+}
+
+// AsDuration returns the parameter value for the given key
+// as a time.Duration, parsed with time.ParseDuration (e.g.,
+// "1h30m"). Returns an error if the key was not found or
+// there was a problem during conversion.
+func (p Params) AsDuration(key string) (time.Duration, error) {
+	if val, err := p.AsString(key); err != nil {
+		return 0, err
+	} else {
+		return time.ParseDuration(val)
+	}
+}
+
+// SetDuration converts the given duration to a string and
+// stores it in the parameter map, in the format accepted by
+// AsDuration.
+func (p Params) SetDuration(key string, val time.Duration) {
+	p[key] = val.String()
+}
+
+// AsTime returns the parameter value for the given key as a
+// time.Time, parsed with the given layout (e.g.,
+// time.RFC3339). Returns an error if the key was not found
+// or the value does not match the layout.
+func (p Params) AsTime(key, layout string) (time.Time, error) {
+	if val, err := p.AsString(key); err != nil {
+		return time.Time{}, err
+	} else {
+		return time.Parse(layout, val)
+	}
+}
+
+// SetTime formats the given time with the given layout and
+// stores it in the parameter map.
+func (p Params) SetTime(key string, val time.Time, layout string) {
+	p[key] = val.Format(layout)
+}
@@ -15,7 +15,10 @@
 package cmdserver
 
 import (
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -133,3 +136,148 @@ func TestParamsBool(t *testing.T) {
 		t.Errorf("incorrect bool - expected: %v, actual: %v", expectedBool, val)
 	}
 }
+
+func TestParamsStringSlice(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		name     string
+		vals     []string
+		expected string
+	}{
+		{"empty", []string{}, `[]`},
+		{"nil", nil, `[]`},
+		{"commas", []string{"a, b", "c", `"quoted"`}, `["a, b","c","\"quoted\""]`},
+	}
+
+	for _, test := range testList {
+		p := make(Params)
+		p.SetStringSlice(foo, test.vals)
+
+		if p[foo] != test.expected {
+			t.Errorf("%s: incorrect value - expected: %v, actual: %v", test.name, test.expected, p[foo])
+		}
+
+		val, err := p.AsStringSlice(foo)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		} else if len(val) != len(test.vals) || (len(val) > 0 && cmp.Diff(test.vals, val) != "") {
+			t.Errorf("%s: incorrect slice - expected: %q, actual: %q", test.name, test.vals, val)
+		}
+	}
+
+	p := Params{foo: "a,b", bar: `[1, 2]`}
+
+	for _, key := range []string{foo, bar, baz} {
+		if _, err := p.AsStringSlice(key); err == nil {
+			t.Errorf("expected an error for key %q", key)
+		}
+	}
+}
+
+func TestParamsDuration(t *testing.T) {
+	t.Parallel()
+
+	expected := "1h30m5s"
+	expectedDuration := time.Hour + 30*time.Minute + 5*time.Second
+
+	p := make(Params)
+	p.SetDuration(foo, expectedDuration)
+
+	if p[foo] != expected {
+		t.Errorf("incorrect value - expected: %v, actual: %v", expected, p[foo])
+	}
+
+	if val, err := p.AsDuration(foo); err != nil {
+		t.Error(err)
+	} else if val != expectedDuration {
+		t.Errorf("incorrect duration - expected: %v, actual: %v", expectedDuration, val)
+	}
+
+	p.SetString(bar, "ten minutes")
+
+	for _, key := range []string{bar, baz} {
+		if _, err := p.AsDuration(key); err == nil {
+			t.Errorf("expected an error for key %q", key)
+		}
+	}
+}
+
+func TestParamsTime(t *testing.T) {
+	t.Parallel()
+
+	expected := "2021-06-15T13:45:00Z"
+	expectedTime := time.Date(2021, time.June, 15, 13, 45, 0, 0, time.UTC)
+
+	p := make(Params)
+	p.SetTime(foo, expectedTime, time.RFC3339)
+
+	if p[foo] != expected {
+		t.Errorf("incorrect value - expected: %v, actual: %v", expected, p[foo])
+	}
+
+	if val, err := p.AsTime(foo, time.RFC3339); err != nil {
+		t.Error(err)
+	} else if !val.Equal(expectedTime) {
+		t.Errorf("incorrect time - expected: %v, actual: %v", expectedTime, val)
+	}
+
+	// The value doesn't match the layout.
+	if _, err := p.AsTime(foo, time.Kitchen); err == nil {
+		t.Error("expected an error for a mismatched layout")
+	}
+
+	if _, err := p.AsTime(baz, time.RFC3339); err == nil || err.Error() != `missing key "baz"` {
+		t.Errorf("expected a missing key error, got: %v", err)
+	}
+}
+
+func TestParamsClone(t *testing.T) {
+	t.Parallel()
+
+	p := make(Params)
+	p.SetString(foo, "fooey")
+	p.SetInt(bar, 42)
+
+	c := p.Clone()
+
+	if diff := cmp.Diff(p, c); diff != "" {
+		t.Errorf("clone differs from the original (-want +got):\n%s", diff)
+	}
+
+	// Mutate the original while reading the clone. The race
+	// detector reports a failure if the two share a map.
+	var wg sync.WaitGroup
+
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 100; i++ {
+			p.SetString(foo, strconv.Itoa(i))
+			p.SetInt(baz, i)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 100; i++ {
+			if val, err := c.AsString(foo); err != nil || val != "fooey" {
+				t.Errorf("clone changed - expected: fooey, actual: %q (err: %v)", val, err)
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+
+	if _, err := c.AsInt(baz); err == nil {
+		t.Error("expected a key added to the original to be missing from the clone")
+	}
+
+	if Params(nil).Clone() != nil {
+		t.Error("expected a nil clone of nil Params")
+	}
+}
@@ -16,12 +16,15 @@ package cmdserver
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -33,9 +36,17 @@ import (
 // URL, so the Diatheke model should be set accordingly.
 // Once received, commands are sent to Handlers added with
 // the SetHandler function.
+//
+// The server also answers GET requests for the health check
+// paths "/healthz" (liveness) and "/readyz" (readiness, see
+// SetReady), without decoding a command.
 type Server struct {
-	logger   log.Logger
-	registry handlerRegistry
+	logger     log.Logger
+	registry   handlerRegistry
+	middleware []Middleware
+	timeout    time.Duration
+	ready      int32 // accessed atomically, 1 if ready
+	metrics    *metrics
 }
 
 // NewServer returns a new command server.
@@ -47,49 +58,132 @@ func NewServer(logger log.Logger) Server {
 	return Server{
 		logger:   logger,
 		registry: newRegistry(),
+		ready:    1,
 	}
 }
 
+// SetReady sets whether the server reports that it is ready to
+// handle commands on the "/readyz" path. Servers are ready by
+// default; deployments that need to finish some setup before
+// receiving traffic can call SetReady(false) before Run and
+// SetReady(true) when done. It is safe to call while the
+// server is running.
+func (svr *Server) SetReady(ready bool) {
+	var v int32
+	if ready {
+		v = 1
+	}
+
+	atomic.StoreInt32(&svr.ready, v)
+}
+
 // SetCommand registers the provided Handler to be called for
 // the specified command ID.
 //
 // If there are multiple potential handlers for a command
-// registered (using SetCommand, SetModel, or SetModelCommand)
-// the server will attempt to use the most specific handler
-// available, with precedence shown below:
+// registered (using SetCommand, SetModel, SetModelCommand or
+// SetDefault) the server will attempt to use the most specific
+// handler available, with precedence shown below:
 //  1. Model+Command ID handler (most specific)
 //  2. Command ID handler
-//  3. Model ID handler (least specific)
+//  3. Model ID handler
+//  4. Default handler (least specific)
 func (svr *Server) SetCommand(cmdID string, h Handler) {
+	svr.registry.setCmd(cmdID, h.withContext())
+}
+
+// SetCommandContext registers the provided HandlerContext to be
+// called for the specified command ID, with the context of the
+// Diatheke request. It replaces any Handler registered with
+// SetCommand for the same command ID, and has the same
+// precedence.
+func (svr *Server) SetCommandContext(cmdID string, h HandlerContext) {
 	svr.registry.setCmd(cmdID, h)
 }
 
+// SetTimeout sets the maximum duration of each command. Once it
+// expires, the context given to HandlerContext functions is
+// cancelled. Zero, the default, means no timeout.
+func (svr *Server) SetTimeout(d time.Duration) {
+	svr.timeout = d
+}
+
 // SetModel registers the provided Handler to be called for
 // the specified model ID.
 //
 // If there are multiple potential handlers for a command
-// registered (using SetCommand, SetModel, or SetModelCommand)
-// the server will attempt to use the most specific handler
-// available, with precedence shown below:
+// registered (using SetCommand, SetModel, SetModelCommand or
+// SetDefault) the server will attempt to use the most specific
+// handler available, with precedence shown below:
 //  1. Model+Command ID handler (most specific)
 //  2. Command ID handler
-//  3. Model ID handler (least specific)
+//  3. Model ID handler
+//  4. Default handler (least specific)
 func (svr *Server) SetModel(modelID string, h Handler) {
-	svr.registry.setModel(modelID, h)
+	svr.registry.setModel(modelID, h.withContext())
 }
 
 // SetModelCommand registers the provided Handler to be called
 // for the given model and command ID combination.
 //
 // If there are multiple potential handlers for a command
-// registered (using SetCommand, SetModel, or SetModelCommand)
-// the server will attempt to use the most specific handler
-// available, with precedence shown below:
+// registered (using SetCommand, SetModel, SetModelCommand or
+// SetDefault) the server will attempt to use the most specific
+// handler available, with precedence shown below:
 //  1. Model+Command ID handler (most specific)
 //  2. Command ID handler
-//  3. Model ID handler (least specific)
+//  3. Model ID handler
+//  4. Default handler (least specific)
 func (svr *Server) SetModelCommand(modelID, cmdID string, h Handler) {
-	svr.registry.setModelCmd(modelID, cmdID, h)
+	svr.registry.setModelCmd(modelID, cmdID, h.withContext())
+}
+
+// SetDefault registers the provided Handler to be called for
+// commands that have no other handler, e.g. to log and
+// acknowledge unknown commands instead of failing the Diatheke
+// turn. Without a default handler, the server responds to
+// unknown commands with an HTTP 500 error.
+func (svr *Server) SetDefault(h Handler) {
+	svr.registry.setDefault(h.withContext())
+}
+
+// Use adds middleware that wraps every command handler. The
+// middleware runs in the order it was added, the first one
+// being the outermost, around the handler found for each
+// command.
+func (svr *Server) Use(mw ...Middleware) {
+	svr.middleware = append(svr.middleware, mw...)
+}
+
+// wrap returns the handler wrapped with the server's middleware.
+func (svr *Server) wrap(h Handler) Handler {
+	for i := len(svr.middleware) - 1; i >= 0; i-- {
+		h = svr.middleware[i](h)
+	}
+
+	return h
+}
+
+// ErrHandlerPanic is returned by handlers wrapped with the
+// Recover middleware when they panic. The server then responds
+// with an HTTP 500 error, with the error in the command output.
+var ErrHandlerPanic = errors.New("command handler panicked")
+
+// Recover returns a Middleware that recovers from panics in the
+// handler and returns an error wrapping ErrHandlerPanic instead,
+// so that a failing command doesn't crash the server.
+func Recover() Middleware {
+	return func(next Handler) Handler {
+		return func(in Input, out *Output) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("%w: %v", ErrHandlerPanic, r)
+				}
+			}()
+
+			return next(in, out)
+		}
+	}
 }
 
 const (
@@ -104,6 +198,27 @@ const (
 // (e.g., ":8072", "localhost:1515", "127.0.0.1:3535") until
 // either an error occurs or the interrupt signal is received.
 func (svr *Server) Run(address string) error {
+	return svr.run(address, nil)
+}
+
+// RunTLS is like Run, but serves https using the certificate
+// and key in the given PEM files. Connections must use TLS 1.2
+// or later.
+func (svr *Server) RunTLS(address, certFile, keyFile string) error {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return fmt.Errorf("unable to load TLS certificate and key: %w", err)
+	}
+
+	return svr.run(address, &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	})
+}
+
+// run implements Run and RunTLS, serving https if tlsConfig
+// is not nil.
+func (svr *Server) run(address string, tlsConfig *tls.Config) error {
 	// Create the tcp connection
 	lis, err := net.Listen("tcp", address)
 	if err != nil {
@@ -127,6 +242,7 @@ func (svr *Server) Run(address string) error {
 		ReadHeaderTimeout: defaultHTTPReadTimeout,
 		WriteTimeout:      defaultHTTPWriteTimeout,
 		IdleTimeout:       defaultHTTPIdleTimeout,
+		TLSConfig:         tlsConfig,
 	}
 
 	// Use an error channel to collect errors from the go
@@ -136,11 +252,17 @@ func (svr *Server) Run(address string) error {
 	// Listen in a different go routine so that we can still
 	// respond to the keyboard interrupt.
 	go func() {
-		errCh <- hsvr.Serve(lis)
+		if tlsConfig != nil {
+			// The certificate is already in the TLS config.
+			errCh <- hsvr.ServeTLS(lis, "", "")
+		} else {
+			errCh <- hsvr.Serve(lis)
+		}
 	}()
 	svr.logger.Info(
 		"msg", "server started",
 		"httpAddr", address,
+		"tls", tlsConfig != nil,
 	)
 
 	// Catch the interrupt signal to gracefully shutdown the server
@@ -164,10 +286,81 @@ func (svr *Server) Run(address string) error {
 	}
 }
 
+const (
+	healthPath = "/healthz"
+	readyPath  = "/readyz"
+)
+
+// serveHealth responds to requests for the health check paths,
+// returning false if the request is for another path.
+func (svr *Server) serveHealth(w http.ResponseWriter, r *http.Request) bool {
+	if r.URL.Path != healthPath && r.URL.Path != readyPath {
+		return false
+	}
+
+	if !allowGet(w, r) {
+		return true
+	}
+
+	if r.URL.Path == readyPath && atomic.LoadInt32(&svr.ready) == 0 {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+		return true
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ok")
+
+	return true
+}
+
+// allowGet responds with an error if the request is not a GET or
+// HEAD request, returning false in that case.
+func allowGet(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return true
+	}
+
+	w.Header().Set("Allow", "GET, HEAD")
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+	return false
+}
+
+// responseStatus returns the HTTP status for the command output
+// and the error returned by its handler.
+func (svr *Server) responseStatus(output Output, err error) int {
+	const minStatus, maxStatus = 100, 599
+
+	switch {
+	case output.StatusCode == 0 && err != nil:
+		return http.StatusInternalServerError
+
+	case output.StatusCode == 0:
+		return http.StatusOK
+
+	case output.StatusCode < minStatus || output.StatusCode > maxStatus:
+		svr.logger.Error(
+			"msg", "invalid command status code",
+			"cmd", output.CommandID,
+			"status", output.StatusCode,
+		)
+
+		return http.StatusInternalServerError
+
+	default:
+		return output.StatusCode
+	}
+}
+
 // ServeHTTP implements the http.Handler interface. It decodes
 // the command, forwards the data to the correct command Handler,
-// then encodes the result to send back to Diatheke.
+// then encodes the result to send back to Diatheke. Requests for
+// the health check paths are answered before any decoding.
 func (svr *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if svr.serveHealth(w, r) || svr.serveMetrics(w, r) {
+		return
+	}
+
 	var input Input
 
 	// Read the JSON request
@@ -177,21 +370,55 @@ func (svr *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	output, err := svr.execute(r.Context(), input)
+	if errors.Is(err, errNoHandler) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err != nil {
+		output.Error = err.Error()
+	}
+
+	status := svr.responseStatus(output, err)
+
+	// Send the command result
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(&output); err != nil {
+		svr.logger.Error(
+			"msg", "failed to write command response",
+			"error", err,
+		)
+	}
+}
+
+// errNoHandler is returned by execute when no handler is
+// registered for a command.
+var errNoHandler = errors.New("could not find handler")
+
+// execute runs the handler registered for the command with the
+// server's middleware and timeout, and returns its output. The
+// error is either errNoHandler, in which case the output is
+// empty, or the error returned by the handler. It does not
+// depend on the transport, so that other transports than http
+// can share the registry and handlers.
+func (svr *Server) execute(ctx context.Context, input Input) (Output, error) {
 	// Find the appropriate handler
 	handler, found := svr.registry.findHandler(input)
 	if !found || handler == nil {
-		http.Error(
-			w,
-			fmt.Sprintf("could not find handler for command %q", input.CommandID),
-			http.StatusInternalServerError,
-		)
-
 		svr.logger.Error(
 			"msg", "could not find command handler",
 			"cmd", input.CommandID,
 		)
 
-		return
+		if svr.metrics != nil {
+			svr.metrics.observe(input.CommandID, outcomeNotFound, 0)
+		}
+
+		return Output{}, fmt.Errorf("%w for command %q", errNoHandler, input.CommandID)
 	}
 
 	// Run the command
@@ -200,20 +427,58 @@ func (svr *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		Parameters: make(Params),
 		Metadata:   input.Metadata,
 	}
-	if err := handler(input, &output); err != nil {
-		output.Error = err.Error()
+
+	if svr.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, svr.timeout)
+		defer cancel()
 	}
 
-	// Send the command result
-	w.Header().Set("Content-Type", "application/json")
+	run := func(in Input, out *Output) error {
+		return handler(ctx, in, out)
+	}
 
-	encoder := json.NewEncoder(w)
-	if err := encoder.Encode(&output); err != nil {
+	start := time.Now()
+	err := svr.wrap(run)(input, &output)
+	latency := time.Since(start)
+
+	if svr.metrics != nil {
+		outcome := outcomeOK
+		if err != nil || output.Error != "" {
+			outcome = outcomeError
+		}
+
+		svr.metrics.observe(input.CommandID, outcome, latency)
+	}
+
+	// Parameters may hold sensitive data, so they are only logged
+	// at the Debug level.
+	svr.logger.Info(
+		"msg", "command handled",
+		"cmd", input.CommandID,
+		"model", input.ModelID,
+		"session", input.SessionID,
+		"latency", latency,
+		"failed", err != nil,
+	)
+	svr.logger.Debug(
+		"msg", "command parameters",
+		"cmd", input.CommandID,
+		"session", input.SessionID,
+		"inputParameters", input.Parameters,
+		"outParameters", output.Parameters,
+	)
+
+	if errors.Is(err, ErrHandlerPanic) {
 		svr.logger.Error(
-			"msg", "failed to write command response",
+			"msg", "command handler panicked",
+			"cmd", input.CommandID,
 			"error", err,
 		)
 	}
+
+	return output, err
 }
 
 type cmdModelPair struct {
@@ -222,28 +487,29 @@ type cmdModelPair struct {
 }
 
 type handlerRegistry struct {
-	cmdModelFuncs map[cmdModelPair]Handler
-	cmdFuncs      map[string]Handler
-	modelFuncs    map[string]Handler
+	cmdModelFuncs map[cmdModelPair]HandlerContext
+	cmdFuncs      map[string]HandlerContext
+	modelFuncs    map[string]HandlerContext
+	defaultFunc   HandlerContext
 }
 
 func newRegistry() handlerRegistry {
 	return handlerRegistry{
-		cmdModelFuncs: make(map[cmdModelPair]Handler),
-		cmdFuncs:      make(map[string]Handler),
-		modelFuncs:    make(map[string]Handler),
+		cmdModelFuncs: make(map[cmdModelPair]HandlerContext),
+		cmdFuncs:      make(map[string]HandlerContext),
+		modelFuncs:    make(map[string]HandlerContext),
 	}
 }
 
-func (hr *handlerRegistry) setCmd(cmdID string, h Handler) {
+func (hr *handlerRegistry) setCmd(cmdID string, h HandlerContext) {
 	hr.cmdFuncs[cmdID] = h
 }
 
-func (hr *handlerRegistry) setModel(modelID string, h Handler) {
+func (hr *handlerRegistry) setModel(modelID string, h HandlerContext) {
 	hr.modelFuncs[modelID] = h
 }
 
-func (hr *handlerRegistry) setModelCmd(modelID, cmdID string, h Handler) {
+func (hr *handlerRegistry) setModelCmd(modelID, cmdID string, h HandlerContext) {
 	pair := cmdModelPair{
 		modelID: modelID,
 		cmdID:   cmdID,
@@ -252,7 +518,11 @@ func (hr *handlerRegistry) setModelCmd(modelID, cmdID string, h Handler) {
 	hr.cmdModelFuncs[pair] = h
 }
 
-func (hr *handlerRegistry) findHandler(in Input) (Handler, bool) {
+func (hr *handlerRegistry) setDefault(h HandlerContext) {
+	hr.defaultFunc = h
+}
+
+func (hr *handlerRegistry) findHandler(in Input) (HandlerContext, bool) {
 	// Check our maps from specific to general.
 	pair := cmdModelPair{
 		cmdID:   in.CommandID,
@@ -270,6 +540,9 @@ func (hr *handlerRegistry) findHandler(in Input) (Handler, bool) {
 	}
 
 	handler, found = hr.modelFuncs[in.ModelID]
+	if found {
+		return handler, true
+	}
 
-	return handler, found
+	return hr.defaultFunc, hr.defaultFunc != nil
 }
@@ -16,11 +16,19 @@ package cmdserver
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -154,11 +162,11 @@ func TestBadRequest(t *testing.T) {
 	defer resp.Body.Close()
 }
 
-func TestHandlerRegistry(t *testing.T) {
-	t.Parallel()
-
+// newTestRegistry returns a registry with handlers that check they receive
+// the input with their target parameter.
+func newTestRegistry() handlerRegistry {
 	hr := newRegistry()
-	hr.setModelCmd("m1", "c1", func(in Input, out *Output) error {
+	hr.setModelCmd("m1", "c1", func(_ context.Context, in Input, out *Output) error {
 		if in.ModelID != "m1" || in.CommandID != "c1" || in.Parameters["target"] != "m1c1" {
 			return fmt.Errorf("wrong input sent to m1c1: %v", in)
 		}
@@ -166,7 +174,7 @@ func TestHandlerRegistry(t *testing.T) {
 		return nil
 	})
 
-	hr.setCmd("c1", func(in Input, out *Output) error {
+	hr.setCmd("c1", func(_ context.Context, in Input, out *Output) error {
 		if in.CommandID != "c1" || in.Parameters["target"] != "c1" {
 			return fmt.Errorf("wrong input sent to c1: %v", in)
 		}
@@ -174,7 +182,7 @@ func TestHandlerRegistry(t *testing.T) {
 		return nil
 	})
 
-	hr.setCmd("c2", func(in Input, out *Output) error {
+	hr.setCmd("c2", func(_ context.Context, in Input, out *Output) error {
 		if in.CommandID != "c2" || in.Parameters["target"] != "c2" {
 			return fmt.Errorf("wrong input sent to c2: %v", in)
 		}
@@ -182,7 +190,7 @@ func TestHandlerRegistry(t *testing.T) {
 		return nil
 	})
 
-	hr.setModel("m1", func(in Input, out *Output) error {
+	hr.setModel("m1", func(_ context.Context, in Input, out *Output) error {
 		if in.ModelID != "m1" || in.Parameters["target"] != "m1" {
 			return fmt.Errorf("wrong input sent to m1: %v", in)
 		}
@@ -190,7 +198,7 @@ func TestHandlerRegistry(t *testing.T) {
 		return nil
 	})
 
-	hr.setModel("m2", func(in Input, out *Output) error {
+	hr.setModel("m2", func(_ context.Context, in Input, out *Output) error {
 		if in.ModelID != "m2" || in.Parameters["target"] != "m2" {
 			return fmt.Errorf("wrong input sent to m2: %v", in)
 		}
@@ -198,28 +206,59 @@ func TestHandlerRegistry(t *testing.T) {
 		return nil
 	})
 
+	return hr
+}
+
+func TestHandlerRegistry(t *testing.T) {
+	t.Parallel()
+
+	withoutDefault := newTestRegistry()
+
+	withDefault := newTestRegistry()
+	withDefault.setDefault(func(_ context.Context, in Input, out *Output) error {
+		if in.Parameters["target"] != "default" {
+			return fmt.Errorf("wrong input sent to default: %v", in)
+		}
+
+		return nil
+	})
+
 	// Now that the registry is set up, run tests
 	testList := []struct {
-		modelID string
-		cmdID   string
-		target  string
-		found   bool
+		modelID     string
+		cmdID       string
+		target      string
+		found       bool
+		withDefault bool
 	}{
-		{"m1", "c1", "m1c1", true},
-		{"x", "c1", "c1", true},
-		{"x", "c2", "c2", true},
-		{"m1", "y", "m1", true},
-		{"m2", "y", "m2", true},
-		{"x", "x", "", false},
-		{"m1", "c2", "c2", true},
-		{"m2", "c1", "c1", true},
-		{"m2", "c2", "c2", true},
+		{"m1", "c1", "m1c1", true, false},
+		{"x", "c1", "c1", true, false},
+		{"x", "c2", "c2", true, false},
+		{"m1", "y", "m1", true, false},
+		{"m2", "y", "m2", true, false},
+		{"x", "x", "", false, false},
+		{"m1", "c2", "c2", true, false},
+		{"m2", "c1", "c1", true, false},
+		{"m2", "c2", "c2", true, false},
+
+		// The default handler is only used when there is no
+		// other handler.
+		{"m1", "c1", "m1c1", true, true},
+		{"x", "c1", "c1", true, true},
+		{"m1", "y", "m1", true, true},
+		{"x", "x", "default", true, true},
 	}
 
 	for i := range testList {
 		test := testList[i]
 		name := test.modelID + "-" + test.cmdID
 
+		hr := withoutDefault
+		if test.withDefault {
+			hr = withDefault
+			name += "-default"
+		}
+
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
@@ -241,7 +280,7 @@ func TestHandlerRegistry(t *testing.T) {
 				return
 			}
 
-			if err := handler(in, nil); err != nil {
+			if err := handler(context.Background(), in, nil); err != nil {
 				t.Error(err)
 			}
 		})
@@ -285,3 +324,492 @@ func (tc *testClient) send(in Input) (Output, error) {
 
 	return result, err
 }
+
+func TestSetDefault(t *testing.T) {
+	t.Parallel()
+
+	svr := NewServer(nil)
+	svr.SetDefault(func(in Input, out *Output) error {
+		out.Parameters.SetString("handled", in.CommandID)
+
+		return nil
+	})
+
+	tsvr := httptest.NewServer(&svr)
+	defer tsvr.Close()
+
+	client := newTestClient(tsvr)
+
+	out, err := client.send(Input{CommandID: "unknown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Output{CommandID: "unknown", Parameters: Params{"handled": "unknown"}}
+	if diff := cmp.Diff(expected, out); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(in Input, out *Output) error {
+				calls = append(calls, name+" before")
+				err := next(in, out)
+				calls = append(calls, name+" after")
+
+				return err
+			}
+		}
+	}
+
+	svr := NewServer(nil)
+	svr.Use(trace("first"), trace("second"))
+	svr.Use(trace("third"))
+	svr.SetCommand("cmd", func(Input, *Output) error {
+		calls = append(calls, "handler")
+
+		return nil
+	})
+
+	tsvr := httptest.NewServer(&svr)
+	defer tsvr.Close()
+
+	client := newTestClient(tsvr)
+	if _, err := client.send(Input{CommandID: "cmd"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"first before", "second before", "third before",
+		"handler",
+		"third after", "second after", "first after",
+	}
+
+	if diff := cmp.Diff(expected, calls); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	t.Parallel()
+
+	svr := NewServer(nil)
+	svr.Use(Recover())
+	svr.SetCommand("panic", func(Input, *Output) error {
+		panic("something went wrong")
+	})
+
+	tsvr := httptest.NewServer(&svr)
+	defer tsvr.Close()
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(Input{CommandID: "panic"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := tsvr.Client().Post(tsvr.URL, "application/json", &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("incorrect status - expected: %d, actual: %d", http.StatusInternalServerError, resp.StatusCode)
+	}
+
+	var out Output
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := ErrHandlerPanic.Error() + ": something went wrong"; out.Error != expected {
+		t.Errorf("incorrect error - expected: %q, actual: %q", expected, out.Error)
+	}
+}
+
+func TestSetCommandContext(t *testing.T) {
+	t.Parallel()
+
+	svr := NewServer(nil)
+	svr.SetTimeout(10 * time.Millisecond)
+
+	svr.SetCommandContext("slow", func(ctx context.Context, in Input, out *Output) error {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("expected the context to have the server's deadline")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return nil
+		}
+	})
+
+	// Handlers without a context keep working.
+	svr.SetCommand("fast", func(in Input, out *Output) error {
+		out.Parameters.SetString("done", "yes")
+
+		return nil
+	})
+
+	tsvr := httptest.NewServer(&svr)
+	defer tsvr.Close()
+
+	client := newTestClient(tsvr)
+
+	out, err := client.send(Input{CommandID: "slow"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out.Error != context.DeadlineExceeded.Error() {
+		t.Errorf("incorrect error - expected: %q, actual: %q", context.DeadlineExceeded.Error(), out.Error)
+	}
+
+	out, err = client.send(Input{CommandID: "fast"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out.Error != "" || out.Parameters["done"] != "yes" {
+		t.Errorf("unexpected output: %+v", out)
+	}
+}
+
+func TestRunTLSInvalidKeyPair(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	if err := os.WriteFile(certFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	svr := NewServer(nil)
+
+	for _, files := range [][2]string{
+		{certFile, keyFile},
+		{filepath.Join(dir, "missing.pem"), keyFile},
+	} {
+		// The key pair is loaded before listening, so the call
+		// returns right away.
+		if err := svr.RunTLS("127.0.0.1:0", files[0], files[1]); err == nil {
+			t.Errorf("expected an error for the key pair %v", files)
+		}
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	svr := NewServer(nil)
+	svr.SetDefault(func(Input, *Output) error {
+		t.Error("called handler for a health check")
+		return nil
+	})
+
+	tsvr := httptest.NewServer(&svr)
+	defer tsvr.Close()
+
+	client := tsvr.Client()
+
+	get := func(path string) int {
+		resp, err := client.Get(tsvr.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	testList := []struct {
+		name     string
+		ready    bool
+		path     string
+		expected int
+	}{
+		{"healthz", true, healthPath, http.StatusOK},
+		{"readyz", true, readyPath, http.StatusOK},
+		{"healthz not ready", false, healthPath, http.StatusOK},
+		{"readyz not ready", false, readyPath, http.StatusServiceUnavailable},
+	}
+
+	// The cases share the ready flag, so they run in order.
+	for _, test := range testList {
+		svr.SetReady(test.ready)
+
+		if actual := get(test.path); actual != test.expected {
+			t.Errorf("%s - incorrect status - expected: %d, actual: %d", test.name, test.expected, actual)
+		}
+	}
+
+	// A command posted to a health check path is not handled.
+	resp, err := client.Post(tsvr.URL+healthPath, "application/json", strings.NewReader(`{"id": "healthz"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("incorrect status for POST - expected: %d, actual: %d", http.StatusMethodNotAllowed, resp.StatusCode)
+	}
+}
+
+// testLogger records the key-value pairs logged at each level.
+type testLogger struct {
+	mu   sync.Mutex
+	logs map[string][][]interface{}
+}
+
+func newTestLogger() *testLogger {
+	return &testLogger{logs: make(map[string][][]interface{})}
+}
+
+func (l *testLogger) log(lvl string, keyvals []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.logs[lvl] = append(l.logs[lvl], keyvals)
+}
+
+func (l *testLogger) Error(keyvals ...interface{}) { l.log("error", keyvals) }
+func (l *testLogger) Info(keyvals ...interface{})  { l.log("info", keyvals) }
+func (l *testLogger) Debug(keyvals ...interface{}) { l.log("debug", keyvals) }
+func (l *testLogger) Trace(keyvals ...interface{}) { l.log("trace", keyvals) }
+
+// find returns the values of the first entry at the given level
+// with the given message, keyed by name.
+func (l *testLogger) find(lvl, msg string) map[string]interface{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, keyvals := range l.logs[lvl] {
+		m := make(map[string]interface{})
+		for i := 0; i+1 < len(keyvals); i += 2 {
+			m[fmt.Sprint(keyvals[i])] = keyvals[i+1]
+		}
+
+		if m["msg"] == msg {
+			return m
+		}
+	}
+
+	return nil
+}
+
+func TestCommandLogging(t *testing.T) {
+	t.Parallel()
+
+	logger := newTestLogger()
+	svr := NewServer(logger)
+	svr.SetCommand("foo", func(in Input, out *Output) error {
+		out.Parameters.SetString("secret", "out")
+		return nil
+	})
+
+	tsvr := httptest.NewServer(&svr)
+	defer tsvr.Close()
+
+	client := newTestClient(tsvr)
+
+	in := Input{
+		CommandID:  "foo",
+		ModelID:    "model",
+		SessionID:  "session",
+		Parameters: Params{"secret": "in"},
+	}
+
+	if _, err := client.send(in); err != nil {
+		t.Fatal(err)
+	}
+
+	info := logger.find("info", "command handled")
+	if info == nil {
+		t.Fatal("command was not logged at the Info level")
+	}
+
+	for key, expected := range map[string]string{"cmd": "foo", "model": "model", "session": "session"} {
+		if info[key] != expected {
+			t.Errorf("incorrect %s - expected: %q, actual: %v", key, expected, info[key])
+		}
+	}
+
+	if _, ok := info["latency"].(time.Duration); !ok {
+		t.Errorf("expected a latency duration, got: %v", info["latency"])
+	}
+
+	for _, v := range info {
+		if strings.Contains(fmt.Sprint(v), "secret") {
+			t.Errorf("parameters were logged at the Info level: %v", info)
+		}
+	}
+
+	debug := logger.find("debug", "command parameters")
+	if debug == nil {
+		t.Fatal("parameters were not logged at the Debug level")
+	}
+
+	if diff := cmp.Diff(in.Parameters, debug["inputParameters"]); diff != "" {
+		t.Errorf("incorrect input parameters (-want +got):\n%s", diff)
+	}
+}
+
+func TestExecute(t *testing.T) {
+	t.Parallel()
+
+	svr := NewServer(nil)
+	svr.SetCommand("foo", func(in Input, out *Output) error {
+		out.Parameters.SetString("handled", in.CommandID)
+		return nil
+	})
+
+	// Commands can be run without going through http.
+	out, err := svr.execute(context.Background(), Input{CommandID: "foo", Metadata: "meta"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Output{CommandID: "foo", Parameters: Params{"handled": "foo"}, Metadata: "meta"}
+	if diff := cmp.Diff(expected, out); diff != "" {
+		t.Error(diff)
+	}
+
+	if _, err := svr.execute(context.Background(), Input{CommandID: "bar"}); !errors.Is(err, errNoHandler) {
+		t.Errorf("incorrect error - expected: %v, actual: %v", errNoHandler, err)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	svr := NewServer(nil)
+	svr.SetCommand("ok", func(Input, *Output) error { return nil })
+	svr.SetCommand("fail", func(Input, *Output) error { return fmt.Errorf("failed") })
+	svr.EnableMetrics()
+
+	tsvr := httptest.NewServer(&svr)
+	defer tsvr.Close()
+
+	client := newTestClient(tsvr)
+
+	for _, id := range []string{"ok", "ok", "fail"} {
+		if _, err := client.send(Input{CommandID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The response to an unknown command isn't JSON. Unknown IDs are
+	// counted together, under a fixed label.
+	for _, id := range []string{`unknown"id`, "other-id"} {
+		_, _ = client.send(Input{CommandID: id})
+	}
+
+	resp, err := client.client.Get(tsvr.URL + metricsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := string(body)
+
+	for _, expected := range []string{
+		`cmdserver_commands_total{cmd="fail",outcome="error"} 1`,
+		`cmdserver_commands_total{cmd="ok",outcome="ok"} 2`,
+		`cmdserver_commands_total{cmd="unknown",outcome="not-found"} 2`,
+		`cmdserver_handler_duration_seconds_bucket{cmd="ok",le="+Inf"} 2`,
+		`cmdserver_handler_duration_seconds_count{cmd="fail"} 1`,
+	} {
+		if !strings.Contains(actual, expected+"\n") {
+			t.Errorf("missing metric %s in:\n%s", expected, actual)
+		}
+	}
+
+	if strings.Contains(actual, `duration_seconds_count{cmd="unknown`) {
+		t.Error("expected no latency for a command without a handler")
+	}
+
+	if strings.Contains(actual, `unknown\"id`) || strings.Contains(actual, "other-id") {
+		t.Errorf("unexpected label for a command without a handler in:\n%s", actual)
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		name     string
+		handler  Handler
+		expected int
+		errText  string
+	}{
+		{"default", func(Input, *Output) error { return nil }, http.StatusOK, ""},
+		{"error", func(Input, *Output) error { return fmt.Errorf("oops") }, http.StatusInternalServerError, "oops"},
+		{"custom", func(_ Input, out *Output) error {
+			out.StatusCode = http.StatusUnprocessableEntity
+			out.Error = "invalid parameters"
+
+			return nil
+		}, http.StatusUnprocessableEntity, "invalid parameters"},
+		{"custom with error", func(_ Input, out *Output) error {
+			out.StatusCode = http.StatusServiceUnavailable
+			return fmt.Errorf("busy")
+		}, http.StatusServiceUnavailable, "busy"},
+		{"invalid", func(_ Input, out *Output) error {
+			out.StatusCode = 42
+			return nil
+		}, http.StatusInternalServerError, ""},
+	}
+
+	for i := range testList {
+		test := testList[i]
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			svr := NewServer(nil)
+			svr.SetCommand("foo", test.handler)
+
+			tsvr := httptest.NewServer(&svr)
+			defer tsvr.Close()
+
+			resp, err := tsvr.Client().Post(tsvr.URL, "application/json", strings.NewReader(`{"id": "foo"}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer resp.Body.Close()
+
+			if resp.StatusCode != test.expected {
+				t.Errorf("incorrect status - expected: %d, actual: %d", test.expected, resp.StatusCode)
+			}
+
+			var out Output
+			if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+				t.Fatal(err)
+			}
+
+			if out.Error != test.errText {
+				t.Errorf("incorrect error - expected: %q, actual: %q", test.errText, out.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// idleTimer cancels a context once it has not been reset for a given
+// duration. It is used to free a worker whose stream has stopped making
+// progress. A nil idleTimer never expires.
+type idleTimer struct {
+	timer   *time.Timer
+	timeout time.Duration
+	fired   int32
+}
+
+// withIdleTimeout returns a copy of ctx that is cancelled once the
+// returned timer has not been reset for the given timeout. A timeout of
+// zero means no timeout, in which case the timer is nil.
+func withIdleTimeout(ctx context.Context, timeout time.Duration) (context.Context, *idleTimer, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	if timeout <= 0 {
+		return ctx, nil, cancel
+	}
+
+	t := &idleTimer{timeout: timeout}
+	t.timer = time.AfterFunc(timeout, func() {
+		atomic.StoreInt32(&t.fired, 1)
+		cancel()
+	})
+
+	return ctx, t, func() {
+		t.timer.Stop()
+		cancel()
+	}
+}
+
+// reset restarts the timeout, unless the timer already expired.
+func (t *idleTimer) reset() {
+	if t != nil && !t.expired() {
+		t.timer.Reset(t.timeout)
+	}
+}
+
+// expired returns whether the timer cancelled the context.
+func (t *idleTimer) expired() bool {
+	return t != nil && atomic.LoadInt32(&t.fired) == 1
+}
+
+// idleReader resets the timer each time audio is read.
+type idleReader struct {
+	r     io.Reader
+	timer *idleTimer
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.timer.reset()
+	}
+
+	return n, err
+}
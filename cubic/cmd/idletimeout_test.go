@@ -0,0 +1,95 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+// slowReader returns one byte after each delay.
+type slowReader struct {
+	delay time.Duration
+}
+
+func (r slowReader) Read(p []byte) (int, error) {
+	time.Sleep(r.delay)
+
+	return copy(p, "x"), nil
+}
+
+func TestIdleTimeoutExpires(t *testing.T) {
+	t.Parallel()
+
+	ctx, timer, cancel := withIdleTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	r := &idleReader{r: slowReader{delay: 200 * time.Millisecond}, timer: timer}
+	if _, err := r.Read(make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+	default:
+		t.Fatal("context was not cancelled by the slow reader")
+	}
+
+	if !timer.expired() {
+		t.Error("timer did not report expiring")
+	}
+}
+
+func TestIdleTimeoutReset(t *testing.T) {
+	t.Parallel()
+
+	ctx, timer, cancel := withIdleTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	// Reading regularly keeps the context alive well past the timeout.
+	r := &idleReader{r: slowReader{delay: 10 * time.Millisecond}, timer: timer}
+	for i := 0; i < 50; i++ {
+		if _, err := r.Read(make([]byte, 1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if ctx.Err() != nil || timer.expired() {
+		t.Errorf("context cancelled while reading: %v", ctx.Err())
+	}
+}
+
+func TestIdleTimeoutDisabled(t *testing.T) {
+	t.Parallel()
+
+	ctx, timer, cancel := withIdleTimeout(context.Background(), 0)
+	defer cancel()
+
+	if timer != nil {
+		t.Fatal("expected no timer for a zero timeout")
+	}
+
+	r := &idleReader{r: slowReader{delay: 10 * time.Millisecond}, timer: timer}
+	if _, err := ioutil.ReadAll(io.LimitReader(r, 3)); err != nil {
+		t.Fatal(err)
+	}
+
+	if ctx.Err() != nil || timer.expired() {
+		t.Errorf("context cancelled without a timeout: %v", ctx.Err())
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Status values of a fileResult.
+const (
+	statusOK    = "ok"
+	statusError = "error"
+)
+
+// fileResult is the outcome of transcribing a single file, as listed in
+// the manifest.
+type fileResult struct {
+	AudioPath   string  `json:"audio_path"`
+	OutputPath  string  `json:"output_path"`
+	Status      string  `json:"status"`
+	DurationSec float64 `json:"duration_sec"` // time spent transcribing the file
+	Error       string  `json:"error,omitempty"`
+}
+
+// newFileResult returns the result for input, given how long it took and
+// the error it failed with, if any.
+func newFileResult(input fileRef, elapsed time.Duration, err error) fileResult {
+	r := fileResult{
+		AudioPath:   input.audioPath,
+		OutputPath:  input.outputPath,
+		Status:      statusOK,
+		DurationSec: elapsed.Seconds(),
+	}
+
+	if err != nil {
+		r.Status = statusError
+		r.Error = err.Error()
+	}
+
+	return r
+}
+
+// collectResults drains the results channel until it is closed and returns
+// the results sorted by audio path.
+func collectResults(results <-chan fileResult) []fileResult {
+	list := make([]fileResult, 0)
+	for r := range results {
+		list = append(list, r)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].AudioPath < list[j].AudioPath
+	})
+
+	return list
+}
+
+// writeManifestFile writes the results to path, as CSV if it ends in .csv
+// and as JSON otherwise.
+func writeManifestFile(path string, results []fileResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create manifest: %w", err)
+	}
+
+	format := "json"
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		format = "csv"
+	}
+
+	if err := writeManifest(f, results, format); err != nil {
+		f.Close()
+
+		return err
+	}
+
+	return f.Close()
+}
+
+// writeManifest writes the results to w in the given format, either "json"
+// or "csv".
+func writeManifest(w io.Writer, results []fileResult, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(results)
+	case "csv":
+		cw := csv.NewWriter(w)
+
+		if err := cw.Write([]string{"audio_path", "output_path", "status", "duration_sec", "error"}); err != nil {
+			return err
+		}
+
+		for _, r := range results {
+			if err := cw.Write([]string{
+				r.AudioPath,
+				r.OutputPath,
+				r.Status,
+				strconv.FormatFloat(r.DurationSec, 'f', 3, 64), //nolint:gomnd // millisecond precision
+				r.Error,
+			}); err != nil {
+				return err
+			}
+		}
+
+		cw.Flush()
+
+		return cw.Error()
+	default:
+		return fmt.Errorf("unsupported manifest format %q", format)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cobaltspeech/examples-go/cubic/internal/config"
+	"github.com/cobaltspeech/log"
+)
+
+// testResults returns the results of a batch with one missing audio file
+// and one successfully transcribed file.
+func testResults(t *testing.T) []fileResult {
+	t.Helper()
+
+	dir := t.TempDir()
+	missing := fileRef{
+		audioPath:  filepath.Join(dir, "missing.wav"),
+		outputPath: filepath.Join(dir, "missing.wav.txt"),
+	}
+	done := fileRef{
+		audioPath:  filepath.Join(dir, "a.wav"),
+		outputPath: filepath.Join(dir, "a.wav.txt"),
+	}
+
+	results := make(chan fileResult, 2)
+
+	err := transcribeFile(missing, 0, config.Config{}, nil, log.NewDiscardLogger())
+	if err == nil {
+		t.Fatal("expected an error for a missing audio file")
+	}
+
+	results <- newFileResult(missing, time.Second, err)
+	results <- newFileResult(done, 1500*time.Millisecond, nil)
+	close(results)
+
+	return collectResults(results)
+}
+
+func TestManifestJSON(t *testing.T) {
+	t.Parallel()
+
+	results := testResults(t)
+	path := filepath.Join(t.TempDir(), "manifest.json")
+
+	if err := writeManifestFile(path, results); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual []fileResult
+	if err := json.Unmarshal(data, &actual); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(actual) != 2 {
+		t.Fatalf("incorrect number of entries - expected: 2, actual: %d", len(actual))
+	}
+
+	// Entries are sorted by audio path.
+	if ok := actual[0]; filepath.Base(ok.AudioPath) != "a.wav" || ok.Status != statusOK ||
+		ok.Error != "" || ok.DurationSec != 1.5 {
+		t.Errorf("unexpected success entry: %+v", ok)
+	}
+
+	if failed := actual[1]; filepath.Base(failed.AudioPath) != "missing.wav" || failed.Status != statusError ||
+		failed.Error == "" || failed.OutputPath == "" {
+		t.Errorf("unexpected failure entry: %+v", failed)
+	}
+}
+
+func TestManifestCSV(t *testing.T) {
+	t.Parallel()
+
+	results := testResults(t)
+	path := filepath.Join(t.TempDir(), "manifest.csv")
+
+	if err := writeManifestFile(path, results); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("incorrect number of rows - expected: 3, actual: %d", len(records))
+	}
+
+	if row := records[1]; row[2] != statusOK || row[3] != "1.500" || row[4] != "" {
+		t.Errorf("unexpected success row: %v", row)
+	}
+
+	if row := records[2]; row[2] != statusError || row[4] == "" {
+		t.Errorf("unexpected failure row: %v", row)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cobaltspeech/examples-go/cubic/internal/config"
+	"github.com/cobaltspeech/sdk-cubic/grpc/go-cubic/cubicpb"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// transcriptWriter writes the transcript of a file in one of the output
+// formats.
+type transcriptWriter interface {
+	// write writes the final results, sorted by start time, along with
+	// the responses they were received in.
+	write(responses []*cubicpb.RecognitionResponse, results []*cubicpb.RecognitionResult) error
+
+	io.Closer
+}
+
+// newTranscriptWriter returns the transcriptWriter for the given output
+// format, writing to w.
+func newTranscriptWriter(w io.WriteCloser, format string, prefix bool) (transcriptWriter, error) {
+	switch format {
+	case config.OutputText:
+		return &textWriter{w, prefix}, nil
+	case config.OutputJSON:
+		return &jsonWriter{w}, nil
+	case config.OutputCSV:
+		return &csvWriter{w}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output format %q", format)
+	}
+}
+
+// outputPathForFormat replaces the .txt extension of the output path with
+// the extension of the output format, if it is not text.
+func outputPathForFormat(path, format string) string {
+	if format == config.OutputText || filepath.Ext(path) != ".txt" {
+		return path
+	}
+
+	return strings.TrimSuffix(path, ".txt") + "." + format
+}
+
+// textWriter writes the transcript of each result on its own line,
+// optionally prefixed with its channel and start time.
+type textWriter struct {
+	io.WriteCloser
+	prefix bool
+}
+
+func (w *textWriter) write(_ []*cubicpb.RecognitionResponse, results []*cubicpb.RecognitionResult) error {
+	for _, r := range results {
+		prefix := ""
+		if w.prefix {
+			prefix = fmt.Sprintf("[Channel %d - %s] ", r.AudioChannel, formatDuration(r.Alternatives[0].GetStartTime()))
+		}
+
+		if _, err := fmt.Fprintf(w, "%s%s\n", prefix, r.Alternatives[0].Transcript); err != nil {
+			return fmt.Errorf("couldn't append transcript: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// jsonWriter writes the full responses as a JSON array.
+type jsonWriter struct {
+	io.WriteCloser
+}
+
+func (w *jsonWriter) write(responses []*cubicpb.RecognitionResponse, _ []*cubicpb.RecognitionResult) error {
+	list := make([]json.RawMessage, 0, len(responses))
+
+	for _, resp := range responses {
+		data, err := protojson.Marshal(resp)
+		if err != nil {
+			return fmt.Errorf("couldn't encode response: %w", err)
+		}
+
+		list = append(list, data)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(list)
+}
+
+// csvWriter writes a row with the channel, start and end times (in
+// seconds), confidence and transcript of each result.
+type csvWriter struct {
+	io.WriteCloser
+}
+
+func (w *csvWriter) write(_ []*cubicpb.RecognitionResponse, results []*cubicpb.RecognitionResult) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"channel", "startTime", "endTime", "confidence", "transcript"}); err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		alt := r.Alternatives[0]
+		start := formatDuration(alt.GetStartTime())
+		end := start + formatDuration(alt.GetDuration())
+
+		if err := cw.Write([]string{
+			strconv.FormatUint(uint64(r.AudioChannel), 10),   //nolint:gomnd // decimal
+			strconv.FormatFloat(start.Seconds(), 'f', 3, 64), //nolint:gomnd // millisecond precision
+			strconv.FormatFloat(end.Seconds(), 'f', 3, 64),   //nolint:gomnd // millisecond precision
+			strconv.FormatFloat(alt.Confidence, 'f', 3, 64),  //nolint:gomnd // three decimals
+			alt.Transcript,
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
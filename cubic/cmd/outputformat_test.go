@@ -0,0 +1,150 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/cobaltspeech/examples-go/cubic/internal/config"
+	"github.com/cobaltspeech/sdk-cubic/grpc/go-cubic/cubicpb"
+
+	pbduration "google.golang.org/protobuf/types/known/durationpb"
+)
+
+// bufferCloser is a bytes.Buffer with a no-op Close method.
+type bufferCloser struct {
+	bytes.Buffer
+}
+
+func (b *bufferCloser) Close() error { return nil }
+
+// testTranscript returns two responses with one final result each, on
+// different channels.
+func testTranscript() ([]*cubicpb.RecognitionResponse, []*cubicpb.RecognitionResult) {
+	results := []*cubicpb.RecognitionResult{
+		{
+			AudioChannel: 0,
+			Alternatives: []*cubicpb.RecognitionAlternative{{
+				Transcript: "hello world",
+				Confidence: 0.92,
+				StartTime:  &pbduration.Duration{Seconds: 1, Nanos: 500000000},
+				Duration:   &pbduration.Duration{Seconds: 2},
+			}},
+		},
+		{
+			AudioChannel: 1,
+			Alternatives: []*cubicpb.RecognitionAlternative{{
+				Transcript: "hi, there",
+				Confidence: 0.5,
+				StartTime:  &pbduration.Duration{Seconds: 4},
+				Duration:   &pbduration.Duration{Nanos: 250000000},
+			}},
+		},
+	}
+
+	responses := []*cubicpb.RecognitionResponse{
+		{Results: results[:1]},
+		{Results: results[1:]},
+	}
+
+	return responses, results
+}
+
+func TestTranscriptWriters(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		format   string
+		prefix   bool
+		expected string
+	}{
+		{config.OutputText, false, "hello world\nhi, there\n"},
+		{config.OutputText, true, "[Channel 0 - 1.5s] hello world\n[Channel 1 - 4s] hi, there\n"},
+		{config.OutputCSV, false, "channel,startTime,endTime,confidence,transcript\n" +
+			"0,1.500,3.500,0.920,hello world\n" +
+			"1,4.000,4.250,0.500,\"hi, there\"\n"},
+	}
+
+	for _, test := range testList {
+		var buf bufferCloser
+
+		w, err := newTranscriptWriter(&buf, test.format, test.prefix)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := w.write(testTranscript()); err != nil {
+			t.Fatal(err)
+		}
+
+		if buf.String() != test.expected {
+			t.Errorf("%s (prefix: %t) - expected:\n%s\nactual:\n%s", test.format, test.prefix, test.expected, buf.String())
+		}
+	}
+}
+
+func TestTranscriptWriterJSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bufferCloser
+
+	w, err := newTranscriptWriter(&buf, config.OutputJSON, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.write(testTranscript()); err != nil {
+		t.Fatal(err)
+	}
+
+	// There is one element for each response.
+	var list []json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &list); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, buf.String())
+	}
+
+	if len(list) != 2 || !bytes.Contains(list[1], []byte("hi, there")) {
+		t.Errorf("incorrect JSON output:\n%s", buf.String())
+	}
+}
+
+func TestOutputPathForFormat(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		path, format, expected string
+	}{
+		{"out/a.wav.txt", config.OutputText, "out/a.wav.txt"},
+		{"out/a.wav.txt", config.OutputJSON, "out/a.wav.json"},
+		{"out/a.wav.txt", config.OutputCSV, "out/a.wav.csv"},
+		{"out/a_en-us.json", config.OutputCSV, "out/a_en-us.json"},
+	}
+
+	for _, test := range testList {
+		if actual := outputPathForFormat(test.path, test.format); actual != test.expected {
+			t.Errorf("(%s, %s) - expected: %s, actual: %s", test.path, test.format, test.expected, actual)
+		}
+	}
+}
+
+func TestTranscriptWriterUnsupported(t *testing.T) {
+	t.Parallel()
+
+	if _, err := newTranscriptWriter(&bufferCloser{}, "xml", false); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
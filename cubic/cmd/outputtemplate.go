@@ -0,0 +1,102 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// defaultOutputTemplate names transcripts after the full audio file name, with
+// the extension .txt appended.
+const defaultOutputTemplate = "{name}{ext}.txt"
+
+// templatePlaceholders are the placeholders supported in an output template.
+var templatePlaceholders = []string{"{name}", "{ext}", "{model}", "{reldir}"}
+
+// outputTemplate computes the path of a transcript file, relative to the output
+// directory, from the path of an audio file relative to the input directory.
+//
+// The following placeholders are supported:
+//
+//	{name}   the audio file name without its extension
+//	{ext}    the audio file extension, including the leading dot
+//	{model}  the model ID from the config file
+//	{reldir} the directory of the audio file relative to the input directory
+type outputTemplate struct {
+	tmpl string
+}
+
+// parseOutputTemplate validates the given template string.
+func parseOutputTemplate(s string) (outputTemplate, error) {
+	if s == "" {
+		return outputTemplate{}, fmt.Errorf("output template must not be empty")
+	}
+
+	if filepath.IsAbs(s) {
+		return outputTemplate{}, fmt.Errorf("output template %q must be a relative path", s)
+	}
+
+	// Remove all known placeholders; any braces left over are invalid.
+	rest := s
+	for _, p := range templatePlaceholders {
+		rest = strings.ReplaceAll(rest, p, "")
+	}
+
+	if strings.ContainsAny(rest, "{}") {
+		return outputTemplate{}, fmt.Errorf("output template %q has an unknown placeholder (supported: %s)",
+			s, strings.Join(templatePlaceholders, ", "))
+	}
+
+	if !strings.Contains(s, "{name}") {
+		return outputTemplate{}, fmt.Errorf("output template %q must contain {name}", s)
+	}
+
+	for _, elem := range strings.Split(filepath.ToSlash(rest), "/") {
+		if elem == ".." {
+			return outputTemplate{}, fmt.Errorf("output template %q must not refer to a parent directory", s)
+		}
+	}
+
+	return outputTemplate{tmpl: s}, nil
+}
+
+// withRelDir returns a template that places transcripts in the same
+// subdirectory of the output directory as the audio file in the input
+// directory. The template is returned unchanged if it already uses {reldir}.
+func (t outputTemplate) withRelDir() outputTemplate {
+	if strings.Contains(t.tmpl, "{reldir}") {
+		return t
+	}
+
+	return outputTemplate{tmpl: "{reldir}/" + t.tmpl}
+}
+
+// path returns the transcript path for the audio file at relPath, which is
+// relative to the input directory.
+func (t outputTemplate) path(relPath, model string) string {
+	base := filepath.Base(relPath)
+	ext := filepath.Ext(base)
+
+	r := strings.NewReplacer(
+		"{name}", strings.TrimSuffix(base, ext),
+		"{ext}", ext,
+		"{model}", model,
+		"{reldir}", filepath.Dir(relPath),
+	)
+
+	return filepath.Clean(filepath.FromSlash(r.Replace(t.tmpl)))
+}
@@ -0,0 +1,250 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/cobaltspeech/examples-go/cubic/internal/config"
+	"github.com/cobaltspeech/examples-go/pkg/textenc"
+)
+
+func TestParseOutputTemplate(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		tmpl  string
+		valid bool
+	}{
+		{defaultOutputTemplate, true},
+		{"{reldir}/{name}_{model}.json", true},
+		{"transcripts/{name}.txt", true},
+		{"", false},
+		{"{name}_{speaker}.txt", false},
+		{"{name.txt", false},
+		{"out.txt", false},
+		{"../{name}.txt", false},
+		{"/tmp/{name}.txt", false},
+	}
+
+	for _, test := range testList {
+		_, err := parseOutputTemplate(test.tmpl)
+		if valid := err == nil; valid != test.valid {
+			t.Errorf("template %q: expected valid=%t, got error: %v", test.tmpl, test.valid, err)
+		}
+	}
+}
+
+func TestLoadFilesOutputTemplate(t *testing.T) {
+	t.Parallel()
+
+	inputDir := t.TempDir()
+	for _, p := range []string{"a.wav", "sub/b.wav", "sub/deeper/c.wav", "sub/ignored.mp3"} {
+		writeTestFile(t, filepath.Join(inputDir, p))
+	}
+
+	testList := []struct {
+		tmpl     string
+		expected map[string]string
+	}{
+		{
+			defaultOutputTemplate,
+			map[string]string{
+				"a.wav":            "a.wav.txt",
+				"sub/b.wav":        "b.wav.txt",
+				"sub/deeper/c.wav": "c.wav.txt",
+			},
+		},
+		{
+			"{reldir}/{name}_{model}.json",
+			map[string]string{
+				"a.wav":            "a_en-us.json",
+				"sub/b.wav":        "sub/b_en-us.json",
+				"sub/deeper/c.wav": "sub/deeper/c_en-us.json",
+			},
+		},
+	}
+
+	for _, test := range testList {
+		tmpl, err := parseOutputTemplate(test.tmpl)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		outputDir := t.TempDir()
+
+		files, err := loadFiles(inputDir, outputDir, []string{".wav"}, nil, tmpl, "en-us")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkOutputPaths(t, files, inputDir, outputDir, test.expected)
+	}
+}
+
+// writeTestFile creates an empty file at path, along with its parent directories.
+func writeTestFile(t *testing.T, path string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkOutputPaths verifies that files maps each input path (relative to
+// inputDir) to the expected output path (relative to outputDir).
+func checkOutputPaths(t *testing.T, files []fileRef, inputDir, outputDir string, expected map[string]string) {
+	t.Helper()
+
+	if len(files) != len(expected) {
+		t.Fatalf("incorrect number of files - expected: %d, actual: %d", len(expected), len(files))
+	}
+
+	for _, f := range files {
+		in, err := filepath.Rel(inputDir, f.audioPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out, err := filepath.Rel(outputDir, f.outputPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := expected[filepath.ToSlash(in)]; filepath.ToSlash(out) != want {
+			t.Errorf("incorrect output path for %s - expected: %s, actual: %s", in, want, out)
+		}
+	}
+}
+
+func TestGetOutputWriterCreatesDirs(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "sub", "deeper", "c.wav.txt")
+
+	w, err := getOutputWriter(path, config.OutputText, false, textenc.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGetOutputWriterEncoding(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "c.wav.txt")
+
+	w, err := getOutputWriter(path, config.OutputText, false, textenc.Options{BOM: true, CRLF: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.write(testTranscript()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "\xEF\xBB\xBFhello world\r\nhi, there\r\n"; string(data) != expected {
+		t.Errorf("incorrect output - expected: %q, actual: %q", expected, data)
+	}
+}
+
+func TestLoadFilesPreserveDirs(t *testing.T) {
+	t.Parallel()
+
+	inputDir := t.TempDir()
+	for _, p := range []string{"a.wav", "x/a.wav", "y/a.wav", "y/z/a.wav"} {
+		writeTestFile(t, filepath.Join(inputDir, p))
+	}
+
+	tmpl, err := parseOutputTemplate(defaultOutputTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outputDir := t.TempDir()
+
+	files, err := loadFiles(inputDir, outputDir, []string{".wav"}, nil, tmpl.withRelDir(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkOutputPaths(t, files, inputDir, outputDir, map[string]string{
+		"a.wav":     "a.wav.txt",
+		"x/a.wav":   "x/a.wav.txt",
+		"y/a.wav":   "y/a.wav.txt",
+		"y/z/a.wav": "y/z/a.wav.txt",
+	})
+
+	seen := make(map[string]bool)
+
+	for _, f := range files {
+		if seen[f.outputPath] {
+			t.Errorf("output path %s used by more than one input file", f.outputPath)
+		}
+
+		seen[f.outputPath] = true
+	}
+}
+
+func TestLoadFilesExtensionsExclude(t *testing.T) {
+	t.Parallel()
+
+	inputDir := t.TempDir()
+	for _, p := range []string{"a.wav", "b.flac", "c.mp3", "backup/a.wav", "x/backup.wav", "x/d.flac"} {
+		writeTestFile(t, filepath.Join(inputDir, p))
+	}
+
+	tmpl, err := parseOutputTemplate(defaultOutputTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outputDir := t.TempDir()
+	exclude := regexp.MustCompile(`^backup/|/backup\.wav$`)
+
+	files, err := loadFiles(inputDir, outputDir, []string{".wav", ".flac"}, exclude.MatchString, tmpl.withRelDir(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkOutputPaths(t, files, inputDir, outputDir, map[string]string{
+		"a.wav":    "a.wav.txt",
+		"b.flac":   "b.flac.txt",
+		"x/d.flac": "x/d.flac.txt",
+	})
+}
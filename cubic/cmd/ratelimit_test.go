@@ -0,0 +1,91 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	const (
+		rps     = 50
+		workers = 4
+		starts  = 10
+	)
+
+	l := newRateLimiter(rps)
+
+	var (
+		mu    sync.Mutex
+		times []time.Time
+		wg    sync.WaitGroup
+	)
+
+	begin := time.Now()
+
+	wg.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < starts/workers+1; j++ {
+				l.wait()
+
+				mu.Lock()
+				times = append(times, time.Now())
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// The first start is immediate, and each of the following ones must wait
+	// for a new token.
+	minElapsed := time.Duration(len(times)-1) * time.Second / rps
+	if elapsed := time.Since(begin); elapsed < minElapsed {
+		t.Errorf("starts not spaced out - expected at least %v, took %v", minElapsed, elapsed)
+	}
+
+	for i, ti := range times {
+		if earliest := begin.Add(time.Duration(i) * time.Second / rps); ti.Before(earliest) {
+			t.Errorf("start %d happened too early: %v before %v", i, earliest.Sub(ti), earliest)
+		}
+	}
+}
+
+func TestRateLimiterUnlimited(t *testing.T) {
+	t.Parallel()
+
+	l := newRateLimiter(0)
+	if l != nil {
+		t.Fatalf("expected no limiter, got %+v", l)
+	}
+
+	begin := time.Now()
+
+	for i := 0; i < 1000; i++ {
+		l.wait()
+	}
+
+	if elapsed := time.Since(begin); elapsed > time.Second {
+		t.Errorf("unlimited limiter took %v", elapsed)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// batchSummary totals the results of a batch.
+type batchSummary struct {
+	Files     int
+	Succeeded int
+	Failed    []fileResult
+	TotalTime time.Duration // sum of the time spent on each file
+
+	// Fastest and Slowest are the successfully transcribed files that
+	// took the least and the most time, if there are any.
+	Fastest, Slowest *fileResult
+}
+
+// summarize returns the summary of the given results.
+func summarize(results []fileResult) batchSummary {
+	s := batchSummary{Files: len(results)}
+
+	for i := range results {
+		r := &results[i]
+		s.TotalTime += secondsToDuration(r.DurationSec)
+
+		if r.Status != statusOK {
+			s.Failed = append(s.Failed, *r)
+			continue
+		}
+
+		s.Succeeded++
+
+		if s.Fastest == nil || r.DurationSec < s.Fastest.DurationSec {
+			s.Fastest = r
+		}
+
+		if s.Slowest == nil || r.DurationSec > s.Slowest.DurationSec {
+			s.Slowest = r
+		}
+	}
+
+	return s
+}
+
+// print writes the summary to w as a table, followed by the list of the
+// files that failed, if any.
+func (s batchSummary) print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) //nolint:gomnd // padding between columns
+
+	fmt.Fprintf(tw, "Files:\t%d\n", s.Files)
+	fmt.Fprintf(tw, "Succeeded:\t%d\n", s.Succeeded)
+	fmt.Fprintf(tw, "Failed:\t%d\n", len(s.Failed))
+	fmt.Fprintf(tw, "Total time:\t%v\n", s.TotalTime.Round(time.Millisecond))
+
+	if s.Fastest != nil {
+		fmt.Fprintf(tw, "Fastest:\t%s\t%v\n", s.Fastest.AudioPath, durationOf(*s.Fastest))
+		fmt.Fprintf(tw, "Slowest:\t%s\t%v\n", s.Slowest.AudioPath, durationOf(*s.Slowest))
+	}
+
+	if len(s.Failed) > 0 {
+		fmt.Fprintf(tw, "\nFailed files:\n")
+
+		for _, r := range s.Failed {
+			fmt.Fprintf(tw, "  %s\t%s\n", r.AudioPath, r.Error)
+		}
+	}
+
+	return tw.Flush()
+}
+
+// durationOf returns the time spent on the file, rounded for display.
+func durationOf(r fileResult) time.Duration {
+	return secondsToDuration(r.DurationSec).Round(time.Millisecond)
+}
+
+// secondsToDuration converts a number of seconds to a time.Duration.
+func secondsToDuration(sec float64) time.Duration {
+	return time.Duration(sec * float64(time.Second))
+}
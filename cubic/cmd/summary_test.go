@@ -0,0 +1,76 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	results := []fileResult{
+		{AudioPath: "a.wav", Status: statusOK, DurationSec: 1.5},
+		{AudioPath: "b.wav", Status: statusError, DurationSec: 0.1, Error: "server error"},
+		{AudioPath: "c.wav", Status: statusOK, DurationSec: 0.5},
+		{AudioPath: "d.wav", Status: statusOK, DurationSec: 3},
+	}
+
+	s := summarize(results)
+
+	if s.Files != 4 || s.Succeeded != 3 || len(s.Failed) != 1 || s.Failed[0].AudioPath != "b.wav" {
+		t.Errorf("incorrect totals: %+v", s)
+	}
+
+	// Failed files are not considered for the fastest file.
+	if s.Fastest == nil || s.Fastest.AudioPath != "c.wav" || s.Slowest == nil || s.Slowest.AudioPath != "d.wav" {
+		t.Errorf("incorrect fastest/slowest files: %v, %v", s.Fastest, s.Slowest)
+	}
+
+	if s.TotalTime.Seconds() != 5.1 {
+		t.Errorf("incorrect total time: %v", s.TotalTime)
+	}
+
+	var buf bytes.Buffer
+	if err := s.print(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{"Failed:      1", "Fastest:     c.wav  500ms", "Slowest:     d.wav  3s", "b.wav  server error"} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("summary does not contain %q:\n%s", expected, buf.String())
+		}
+	}
+}
+
+func TestSummarizeEmpty(t *testing.T) {
+	t.Parallel()
+
+	s := summarize(nil)
+	if s.Files != 0 || s.Fastest != nil || s.Slowest != nil {
+		t.Errorf("incorrect summary: %+v", s)
+	}
+
+	var buf bytes.Buffer
+	if err := s.print(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(buf.String(), "Fastest") {
+		t.Errorf("unexpected fastest file in an empty summary:\n%s", buf.String())
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/cobaltspeech/examples-go/cubic/internal/config"
+	"github.com/cobaltspeech/examples-go/pkg/textenc"
 	"github.com/cobaltspeech/log"
 	"github.com/cobaltspeech/log/pkg/level"
 	cubic "github.com/cobaltspeech/sdk-cubic/grpc/go-cubic"
@@ -43,8 +44,11 @@ type fileRef struct {
 var longMsg = `
 This command is used for transcribing audio files.
 It will iterate through the specified directory of audio files and write the transcript
-back either to the same directory or --output directory.  The file name for the transcript
-will be the same name as the input audio file, with the extension .txt.
+back either to the same directory or --output directory.  By default, the file name for the
+transcript will be the same name as the input audio file, with the extension .txt. Use
+--output-template to customize it with the placeholders {name}, {ext}, {model} and {reldir}.
+Use --preserve-dirs to mirror the input directory structure under the output directory, so that
+files with the same name in different subdirectories do not overwrite each other.
 
 If the server supports transcoding, the file extension (wav, flac, mp3, vox, raw (PCM16SLE)) 
 will be used to determine which codec to use.  Use WAV or FLAC for best results.
@@ -57,6 +61,16 @@ func main() {
 	configFile := flag.String("config", "", "path to config file")
 	inputDir := flag.String("input", "", "path to folder containing audio files")
 	outputDir := flag.String("output", "", "optional path to folder to which transcript files will be written")
+	outputTmpl := flag.String("output-template", defaultOutputTemplate,
+		"template for transcript file paths, relative to the output folder; supports {name}, {ext}, {model} and {reldir}")
+	dumpConfig := flag.String("dump-config", "", "print the effective configuration as toml or json and exit")
+	maxRPS := flag.Float64("max-rps", 0,
+		"optional maximum number of transcription requests started per second, across all workers (0 means no limit)")
+	preserveDirs := flag.Bool("preserve-dirs", false,
+		"mirror the input folder structure under the output folder instead of writing all transcripts to it directly")
+	manifestOut := flag.String("manifest-out", "",
+		"optional path to which a manifest of every input file, its output path, status, duration and error is written "+
+			"(CSV if the path ends in .csv, JSON otherwise)")
 	flag.Usage = func() {
 		fmt.Println(longMsg)
 		fmt.Println("Flags:")
@@ -71,27 +85,36 @@ func main() {
 		return
 	}
 
-	cfg, err := config.ReadConfigFile(*configFile)
+	tmpl, err := parseOutputTemplate(*outputTmpl)
 	if err != nil {
-		fmt.Printf("Error in config file %s: %v\n", *configFile, err)
+		fmt.Printf("Invalid -output-template: %v\n", err)
 
 		return
 	}
 
-	// By default, Error and Info messages are logged
-	if cfg.Verbose {
-		logger.SetFilterLevel(level.Error | level.Info | level.Debug)
+	if *preserveDirs {
+		tmpl = tmpl.withRelDir()
 	}
 
-	cubicConfig, err := config.CreateCubicConfig(cfg)
+	cfg, err := config.ReadConfigFile(*configFile)
 	if err != nil {
 		fmt.Printf("Error in config file %s: %v\n", *configFile, err)
 
 		return
 	}
 
-	cfg.CubicConfig = cubicConfig
-	logger.Info("CubicConfig", cfg.CubicConfig)
+	if *dumpConfig != "" {
+		if err := config.Dump(os.Stdout, cfg, *dumpConfig); err != nil {
+			fmt.Printf("Error dumping config: %v\n", err)
+		}
+
+		return
+	}
+
+	// By default, Error and Info messages are logged
+	if cfg.Verbose {
+		logger.SetFilterLevel(level.Error | level.Info | level.Debug)
+	}
 
 	// Set up a cubicsvr client
 	client, err := createClient(cfg)
@@ -103,14 +126,50 @@ func main() {
 
 	defer client.Close()
 
+	// Select the model by name, if the config file doesn't give its ID.
+	if cfg.Server.ModelID == "" && cfg.Server.ModelName != "" {
+		models, err := client.ListModels(context.Background())
+		if err != nil {
+			logger.Error("msg", "Error listing models", "err", simplifyGrpcErrors(cfg, err))
+
+			return
+		}
+
+		if err := cfg.Server.ResolveModelID(models.Models); err != nil {
+			logger.Error("msg", "Error selecting model", "err", err)
+
+			return
+		}
+
+		logger.Info("msg", "Selected model", "name", cfg.Server.ModelName, "id", cfg.Server.ModelID)
+	}
+
+	cfg.CubicConfigs = make(map[string]*cubicpb.RecognitionConfig, len(cfg.Extensions))
+
+	for _, ext := range cfg.Extensions {
+		cubicConfig, err := config.CreateCubicConfig(cfg, ext)
+		if err != nil {
+			fmt.Printf("Error in config file %s: %v\n", *configFile, err)
+
+			return
+		}
+
+		cfg.CubicConfigs[ext] = cubicConfig
+		logger.Info("extension", ext, "CubicConfig", cubicConfig)
+	}
+
 	// Load the files and place them in a channel
-	files, err := loadFiles(*inputDir, *outputDir, cfg.Extension)
+	files, err := loadFiles(*inputDir, *outputDir, cfg.Extensions, cfg.Excluded, tmpl, cfg.Server.ModelID)
 	if err != nil {
 		logger.Error("msg", "Error loading files", "err", err)
 
 		return
 	}
 
+	for i := range files {
+		files[i].outputPath = outputPathForFormat(files[i].outputPath, cfg.OutputFormat)
+	}
+
 	var numWorkers int
 
 	fileCount := len(files)
@@ -124,6 +183,7 @@ func main() {
 
 	// Setup channel for communicating between the various goroutines
 	fileChannel := make(chan fileRef, numWorkers)
+	results := make(chan fileResult, fileCount)
 
 	// Start multiple goroutines.  The first pushes to the fileChannel, and the rest
 	// each pull from the fileChannel and send requests to cubic server.
@@ -134,11 +194,40 @@ func main() {
 
 	logger.Debug("msg", "Starting workers.", "numWorkers", numWorkers)
 
+	limiter := newRateLimiter(*maxRPS)
+
 	for i := 0; i < numWorkers; i++ {
-		go transcribeFiles(i, cfg, wg, client, fileChannel, logger)
+		go transcribeFiles(i, cfg, wg, client, limiter, fileChannel, results, logger)
 	}
 
 	wg.Wait() // Wait for all workers to finish
+	close(results)
+
+	fileResults := collectResults(results)
+
+	if *manifestOut != "" {
+		if err := writeManifestFile(*manifestOut, fileResults); err != nil {
+			logger.Error("msg", "Error writing manifest", "err", err)
+
+			return
+		}
+
+		logger.Info("msg", "Wrote manifest", "path", *manifestOut)
+	}
+
+	summary := summarize(fileResults)
+
+	fmt.Printf("\nSummary\n")
+
+	if err := summary.print(os.Stdout); err != nil {
+		logger.Error("msg", "Error printing summary", "err", err)
+	}
+
+	if len(summary.Failed) > 0 {
+		// os.Exit skips the deferred calls.
+		client.Close()
+		os.Exit(1)
+	}
 }
 
 // createClient instantiates the Client from the Cubic SDK to communicate with the server
@@ -162,15 +251,33 @@ func createClient(cfg config.Config) (*cubic.Client, error) {
 	return client, nil
 }
 
-// getOutputWriter returns a file writer for the given path
-func getOutputWriter(outputPath string) (io.WriteCloser, error) {
+// getOutputWriter returns a writer of transcripts in the given format and
+// text encoding to the given path, creating any missing parent directories.
+func getOutputWriter(outputPath, format string, prefix bool, enc textenc.Options) (transcriptWriter, error) {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil { //nolint:gomnd // standard directory permissions
+		return nil, fmt.Errorf("Failed to create output directory: %w", err)
+	}
+
 	// Create the file
 	file, err := os.Create(outputPath)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create output file: %w", err)
 	}
 
-	return file, nil
+	w, err := newTranscriptWriter(encodedFile{textenc.NewWriter(file, enc), file}, format, prefix)
+	if err != nil {
+		file.Close()
+
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// encodedFile writes to a file through a text encoding writer.
+type encodedFile struct {
+	io.Writer
+	io.Closer
 }
 
 // checkDir validates that the specified directory path exists and is a directory
@@ -185,8 +292,12 @@ func checkDir(dir, desc string) error {
 	return nil
 }
 
-// loadFiles walks through all the files in inputDir that end in extension and adds them to a list for processing
-func loadFiles(inputDir, outputDir, extension string) ([]fileRef, error) {
+// loadFiles walks through all the files in inputDir that end in one of the extensions and adds them to a list for
+// processing. Files and directories for which excluded returns true, given their slash-separated path relative to
+// inputDir (with a trailing slash for directories), are skipped; excluded may be nil. The output path of each file
+// is computed from tmpl.
+func loadFiles(inputDir, outputDir string, extensions []string, excluded func(relPath string) bool,
+	tmpl outputTemplate, model string) ([]fileRef, error) {
 	if err := checkDir(inputDir, "input"); err != nil {
 		return nil, err
 	}
@@ -199,20 +310,35 @@ func loadFiles(inputDir, outputDir, extension string) ([]fileRef, error) {
 
 	files := make([]fileRef, 0)
 	err := filepath.Walk(inputDir, func(path string, info os.FileInfo, err error) error {
-		// files, outputDir, and extension are available as closures
+		// files, outputDir, and extensions are available as closures
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(inputDir, path)
 		if err != nil {
 			return err
 		}
 
-		if !info.Mode().IsRegular() || info.IsDir() || filepath.Ext(path) != extension {
+		if info.IsDir() {
+			if relPath != "." && excluded != nil && excluded(filepath.ToSlash(relPath)+"/") {
+				return filepath.SkipDir
+			}
+
 			return nil
 		}
 
-		outputPath := filepath.Join(outputDir, filepath.Base(path))
+		if !info.Mode().IsRegular() || !hasExtension(path, extensions) {
+			return nil
+		}
+
+		if excluded != nil && excluded(filepath.ToSlash(relPath)) {
+			return nil
+		}
 
 		files = append(files, fileRef{
 			audioPath:  path,
-			outputPath: outputPath + ".txt",
+			outputPath: filepath.Join(outputDir, tmpl.path(relPath, model)),
 		})
 
 		return nil
@@ -225,6 +351,18 @@ func loadFiles(inputDir, outputDir, extension string) ([]fileRef, error) {
 	return files, nil
 }
 
+// hasExtension returns whether path ends in one of the extensions.
+func hasExtension(path string, extensions []string) bool {
+	ext := filepath.Ext(path)
+	for _, e := range extensions {
+		if ext == e {
+			return true
+		}
+	}
+
+	return false
+}
+
 // feedInputFiles iterates through a list of files and pushes the reference into a fileChannel.
 func feedInputFiles(fileChannel chan<- fileRef, files []fileRef, wg *sync.WaitGroup, logger log.Logger) {
 	for _, f := range files {
@@ -237,32 +375,39 @@ func feedInputFiles(fileChannel chan<- fileRef, files []fileRef, wg *sync.WaitGr
 }
 
 // transcribeFiles pulls references from the file channel and sends them for transcription
-// until the channel is empty
+// until the channel is empty. The limiter is used to throttle the start of each transcription.
+// The result of each file is sent to the results channel.
 func transcribeFiles(workerID int, cfg config.Config, wg *sync.WaitGroup, client *cubic.Client,
-	fileChannel <-chan fileRef, logger log.Logger) {
+	limiter *rateLimiter, fileChannel <-chan fileRef, results chan<- fileResult, logger log.Logger) {
 	logger.Debug("Worker starting", workerID)
 
 	for input := range fileChannel {
-		transcribeFile(input, workerID, cfg, client, logger)
+		limiter.wait()
+
+		start := time.Now()
+		err := transcribeFile(input, workerID, cfg, client, logger)
+		results <- newFileResult(input, time.Since(start), err)
 	}
 
 	wg.Done()
 }
 
 // transcribeFile streams the contents of a single audio file to the Cubic server and writes
-// the transcript to the output file
-func transcribeFile(input fileRef, workerID int, cfg config.Config, client *cubic.Client, logger log.Logger) {
+// the transcript to the output file. It returns the first error encountered, if any.
+func transcribeFile(input fileRef, workerID int, cfg config.Config, client *cubic.Client, logger log.Logger) error {
 	audio, err := os.Open(input.audioPath)
 	if err != nil {
 		logger.Error("file", input.audioPath, "err", err, "message", "Couldn't open audio file")
-		return
+		return err
 	}
 
 	defer audio.Close()
 
-	w, err := getOutputWriter(input.outputPath)
+	w, err := getOutputWriter(input.outputPath, cfg.OutputFormat, cfg.Prefix,
+		textenc.Options{BOM: cfg.BOM, CRLF: cfg.CRLF})
 	if err != nil {
 		logger.Error("file", input.outputPath, "err", err, "message", "Couldn't open output file writer")
+		return err
 	}
 
 	defer w.Close()
@@ -270,13 +415,27 @@ func transcribeFile(input fileRef, workerID int, cfg config.Config, client *cubi
 	// Counter for segments
 	segmentID := 0
 
-	var lines []*cubicpb.RecognitionResult
+	// Cancel the stream if it stops making progress, i.e., if neither
+	// audio is sent nor results are received for the idle timeout, so
+	// that a hung server does not block the worker forever.
+	ctx, idle, cancel := withIdleTimeout(context.Background(), time.Duration(cfg.Server.IdleTimeout)*time.Second)
+	defer cancel()
+
+	var (
+		responses []*cubicpb.RecognitionResponse
+		lines     []*cubicpb.RecognitionResult
+	)
+
 	// Send the Streaming Recognize config
-	err = client.StreamingRecognize(context.Background(),
-		cfg.CubicConfig,
-		audio, // The audio file to send
+	err = client.StreamingRecognize(ctx,
+		cfg.CubicConfigs[filepath.Ext(input.audioPath)],
+		&idleReader{r: audio, timer: idle}, // The audio file to send
 		func(response *cubicpb.RecognitionResponse) { // The callback for results
+			idle.reset()
 			logger.Debug("workerID", workerID, "file", input.audioPath, "segmentID", segmentID)
+
+			final := false
+
 			for _, r := range response.Results {
 				// Note: The Results object includes a lot of detail about the ASR output.
 				// For simplicity, this example just uses a few of the available properties.
@@ -284,13 +443,23 @@ func transcribeFile(input fileRef, workerID int, cfg config.Config, client *cubi
 				// for a description of what other information is available.
 				if !r.IsPartial && len(r.Alternatives) > 0 {
 					lines = append(lines, r)
+					final = true
 				}
 			}
+
+			// Keep the responses with final results for the JSON output.
+			if final {
+				responses = append(responses, response)
+			}
 			segmentID++
 		})
 
-	if err != nil {
-		logger.Error("file", input.audioPath, "err", simplifyGrpcErrors(cfg, err))
+	if idle.expired() {
+		err = fmt.Errorf("stream cancelled after %ds without progress", cfg.Server.IdleTimeout)
+		logger.Error("file", input.audioPath, "err", err)
+	} else if err != nil {
+		err = simplifyGrpcErrors(cfg, err)
+		logger.Error("file", input.audioPath, "err", err)
 	}
 
 	if len(cfg.Channels) > 1 {
@@ -304,23 +473,16 @@ func transcribeFile(input fileRef, workerID int, cfg config.Config, client *cubi
 		})
 	}
 
-	// Display the results
-	for _, r := range lines {
-		prefix := ""
-		if cfg.Prefix {
-			prefix = fmt.Sprintf("[Channel %d - %s] ", r.AudioChannel, formatDuration(r.Alternatives[0].GetStartTime()))
-		}
+	// Write the results in the output format
+	if innerErr := w.write(responses, lines); innerErr != nil {
+		logger.Error("file", input.audioPath, "err", innerErr, "msg", "Couldn't write transcript")
 
-		_, innerErr := fmt.Fprintf(w, "%s%s", prefix, r.Alternatives[0].Transcript)
-		if innerErr != nil {
-			logger.Error("file", input.audioPath, "err", innerErr, "msg", "Couldn't append transcript")
-		}
-
-		_, innerErr = fmt.Fprintln(w, "")
-		if innerErr != nil {
-			logger.Error("file", input.audioPath, "err", innerErr, "msg", "Couldn't append newline")
+		if err == nil {
+			err = innerErr
 		}
 	}
+
+	return err
 }
 
 // formatDuration converts a pbduration.Duration to a time.Duration
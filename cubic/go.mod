@@ -4,8 +4,11 @@ go 1.13
 
 require (
 	github.com/BurntSushi/toml v0.3.1
+	github.com/cobaltspeech/examples-go/pkg v0.0.0
 	github.com/cobaltspeech/log v0.1.6
 	github.com/cobaltspeech/sdk-cubic/grpc/go-cubic v1.5.0
 	github.com/golang/protobuf v1.4.2 // indirect
 	google.golang.org/protobuf v1.23.0
 )
+
+replace github.com/cobaltspeech/examples-go/pkg => ../pkg
@@ -16,8 +16,10 @@ package config
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
+	"github.com/cobaltspeech/examples-go/pkg/envconfig"
 	"github.com/cobaltspeech/sdk-cubic/grpc/go-cubic/cubicpb"
 
 	"github.com/BurntSushi/toml"
@@ -30,6 +32,12 @@ type ServerConfig struct {
 	Insecure    bool
 	ModelID     string
 	IdleTimeout int64
+
+	// ModelName selects the model by name instead of by ID. It is
+	// resolved with ResolveModelID once the client is created, and is
+	// ignored if ModelID is set.
+	ModelName string
+
 	GRPCTimeout int
 }
 
@@ -41,8 +49,46 @@ type Config struct {
 	Server      ServerConfig
 	LogFilePath string
 	Verbose     bool
-	Extension   string
-	CubicConfig *cubicpb.RecognitionConfig
+
+	// Extensions lists the extensions of the files to transcribe. The
+	// older single Extension setting is added to them if it is set.
+	Extensions []string
+	Extension  string
+
+	// Exclude is a regular expression matched against the path of each
+	// file and directory relative to the input directory, with slashes as
+	// separators and a trailing slash for directories (e.g., "^backup/").
+	// Matching files, and everything under matching directories, are
+	// skipped.
+	Exclude string
+
+	// OutputFormat is the format of the transcript files: OutputText
+	// (the default), OutputJSON or OutputCSV.
+	OutputFormat string
+
+	// BOM starts each transcript file with a UTF-8 byte order mark, and
+	// CRLF ends its lines with "\r\n" instead of "\n".
+	BOM  bool
+	CRLF bool
+
+	// CubicConfigs holds the recognition config for each extension.
+	CubicConfigs map[string]*cubicpb.RecognitionConfig `toml:"-" json:"-"`
+
+	exclude *regexp.Regexp
+}
+
+// Output formats of the transcript files.
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+	OutputCSV  = "csv"
+)
+
+// Excluded returns whether the given path, relative to the input
+// directory, matches the Exclude pattern. The path of a directory should
+// end with a slash.
+func (cfg Config) Excluded(relPath string) bool {
+	return cfg.exclude != nil && cfg.exclude.MatchString(relPath)
 }
 
 // ReadConfigFile attempts to load the given config file
@@ -54,6 +100,10 @@ func ReadConfigFile(filename string) (Config, error) {
 		return config, err
 	}
 
+	if err := applyEnvOverrides(&config.Server); err != nil {
+		return config, err
+	}
+
 	if config.Server.Address == "" {
 		return config, fmt.Errorf("missing server address")
 	}
@@ -62,6 +112,28 @@ func ReadConfigFile(filename string) (Config, error) {
 		return config, fmt.Errorf("NumWorkers must be greater than 0")
 	}
 
+	if config.Extension != "" && !contains(config.Extensions, config.Extension) {
+		config.Extensions = append(config.Extensions, config.Extension)
+	}
+
+	if len(config.Extensions) == 0 {
+		return config, fmt.Errorf("at least one file extension is required (Extensions)")
+	}
+
+	if config.Exclude != "" {
+		if config.exclude, err = regexp.Compile(config.Exclude); err != nil {
+			return config, fmt.Errorf("invalid Exclude pattern: %w", err)
+		}
+	}
+
+	switch config.OutputFormat {
+	case "":
+		config.OutputFormat = OutputText
+	case OutputText, OutputJSON, OutputCSV:
+	default:
+		return config, fmt.Errorf("unsupported OutputFormat %q (expected text, json or csv)", config.OutputFormat)
+	}
+
 	if config.Server.GRPCTimeout < 1 {
 		// If timeout not specified, set to default
 		config.Server.GRPCTimeout = 2
@@ -70,16 +142,40 @@ func ReadConfigFile(filename string) (Config, error) {
 	return config, nil
 }
 
-// CreateCubicConfig checks the value of cfg.Extension and populates
-// the RecognitionConfig struct if there was no error.
+// applyEnvOverrides replaces the Cubic server settings with the
+// COBALT_SERVER_ADDRESS, COBALT_MODEL_ID and COBALT_INSECURE environment
+// variables, if they are set. The environment takes precedence over the
+// config file.
+func applyEnvOverrides(cfg *ServerConfig) error {
+	return envconfig.Server{
+		Address:  &cfg.Address,
+		ModelID:  &cfg.ModelID,
+		Insecure: &cfg.Insecure,
+	}.Apply()
+}
+
+// contains returns whether list contains s.
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CreateCubicConfig checks the given file extension and populates
+// the RecognitionConfig struct, with the audio encoding of that
+// extension, if there was no error.
 // Note: there are many more options available to control the
 // Cubic output. This example illustrates a simple case.  Please see
 // https://cobaltspeech.github.io/sdk-cubic/protobuf/autogen-doc-cubic-proto/#message-recognitionconfig
 // for description of other available options.
-func CreateCubicConfig(cfg Config) (*cubicpb.RecognitionConfig, error) {
+func CreateCubicConfig(cfg Config, extension string) (*cubicpb.RecognitionConfig, error) {
 	var audioEncoding cubicpb.RecognitionConfig_Encoding
 
-	ext := strings.ToLower(cfg.Extension)
+	ext := strings.ToLower(extension)
 
 	switch ext {
 	case ".wav":
@@ -0,0 +1,27 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"io"
+
+	"github.com/cobaltspeech/examples-go/pkg/appconfig"
+)
+
+// Dump writes cfg to w as "toml" or "json". The generated recognition config
+// is not part of the dump since it is derived from the other settings.
+func Dump(w io.Writer, cfg Config, format string) error {
+	return appconfig.Dump(w, cfg, format)
+}
@@ -0,0 +1,71 @@
+// Copyright (2020 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cobaltspeech/examples-go/pkg/envconfig"
+)
+
+// writeTestConfig writes a valid config file and returns its path.
+func writeTestConfig(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	contents := `NumWorkers = 1
+Extensions = [".wav"]
+
+[Server]
+Address = "localhost:2727"
+ModelID = "1"
+Insecure = true
+`
+
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestReadConfigFileEnvOverrides(t *testing.T) {
+	path := writeTestConfig(t)
+
+	t.Setenv(envconfig.ServerAddress, "cubic.example.com:443")
+	t.Setenv(envconfig.ModelID, "2")
+	t.Setenv(envconfig.Insecure, "false")
+
+	cfg, err := ReadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Server.Address != "cubic.example.com:443" || cfg.Server.ModelID != "2" || cfg.Server.Insecure {
+		t.Errorf("settings not overridden: %+v", cfg.Server)
+	}
+}
+
+func TestReadConfigFileEnvInvalid(t *testing.T) {
+	path := writeTestConfig(t)
+
+	t.Setenv(envconfig.Insecure, "maybe")
+
+	if _, err := ReadConfigFile(path); err == nil {
+		t.Error("expected an error for an invalid COBALT_INSECURE value")
+	}
+}
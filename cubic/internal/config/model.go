@@ -0,0 +1,40 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"github.com/cobaltspeech/examples-go/pkg/appconfig"
+	"github.com/cobaltspeech/sdk-cubic/grpc/go-cubic/cubicpb"
+)
+
+// ResolveModelID sets ModelID to the ID of the model named ModelName,
+// found in the given list of models (e.g., from ListModels). It does
+// nothing if ModelID is already set, or if ModelName is not set. See
+// appconfig.ResolveModelID for how the name is matched.
+func (cfg *ServerConfig) ResolveModelID(models []*cubicpb.Model) error {
+	list := make([]appconfig.Model, len(models))
+	for i, mdl := range models {
+		list[i] = appconfig.Model{ID: mdl.Id, Name: mdl.Name}
+	}
+
+	id, err := appconfig.ResolveModelID(cfg.ModelID, cfg.ModelName, list)
+	if err != nil {
+		return err
+	}
+
+	cfg.ModelID = id
+
+	return nil
+}
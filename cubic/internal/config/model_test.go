@@ -0,0 +1,46 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/cobaltspeech/sdk-cubic/grpc/go-cubic/cubicpb"
+)
+
+// TestResolveModelID checks the adapter to appconfig.ResolveModelID, which
+// has the tests of the name matching.
+func TestResolveModelID(t *testing.T) {
+	t.Parallel()
+
+	models := []*cubicpb.Model{
+		{Id: "en-us-8-close", Name: "English (US) 8kHz"},
+		{Id: "en-us-16-far", Name: "English (US) 16kHz"},
+	}
+
+	cfg := ServerConfig{ModelName: "english (us) 16khz"}
+	if err := cfg.ResolveModelID(models); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.ModelID != "en-us-16-far" {
+		t.Errorf("incorrect model ID - expected: %q, actual: %q", "en-us-16-far", cfg.ModelID)
+	}
+
+	cfg = ServerConfig{ModelName: "Spanish"}
+	if err := cfg.ResolveModelID(models); err == nil || cfg.ModelID != "" {
+		t.Errorf("expected an error and no model ID, got: %v, %q", err, cfg.ModelID)
+	}
+}
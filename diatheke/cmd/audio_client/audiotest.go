@@ -0,0 +1,130 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cobaltspeech/examples-go/pkg/audio"
+)
+
+const (
+	// Peak level (dBFS) below which the recording is considered silent,
+	// which usually means the wrong input device or a muted mic.
+	silencePeakDBFS = -60.0
+
+	// RMS level (dBFS) below which the recording is considered too quiet
+	// for reliable recognition.
+	quietRMSDBFS = -50.0
+
+	// Peak level (dBFS) at or above which the recording is considered
+	// clipped.
+	clippedPeakDBFS = -0.1
+)
+
+// runAudioTest implements the audio-test subcommand, which records a few
+// seconds of audio with the configured recorder, reports its levels and
+// optionally plays it back with the configured player.
+func runAudioTest(args []string) error {
+	fs := flag.NewFlagSet("audio-test", flag.ExitOnError)
+	duration := fs.Duration("duration", 3*time.Second, "How long to record")
+	sampleRate := fs.Int("sample-rate", 16000, "Sample rate of the recorded audio (must match the recorder config)")
+	playback := fs.Bool("playback", true, "Play back the recorded audio")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	source := audio.NewSourceFromConfig(appCfg.Recording)
+	if err := source.Start(); err != nil {
+		return fmt.Errorf("error starting recorder: %w", err)
+	}
+
+	fmt.Printf("Recording for %v, please speak normally...\n", *duration)
+
+	size := int64(duration.Seconds()*float64(*sampleRate)) * 2 //nolint:gomnd // 16-bit samples
+	meter := audio.NewLevelMeter(io.LimitReader(source, size))
+	recorded, err := io.ReadAll(meter)
+
+	if recErr := source.Stop(); recErr != nil {
+		return recErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("error recording audio: %w", err)
+	}
+
+	fmt.Printf("  Samples: %d\n", meter.Samples())
+	fmt.Printf("  RMS level: %.1f dBFS\n", meter.RMSDBFS())
+	fmt.Printf("  Peak level: %.1f dBFS\n", meter.PeakDBFS())
+
+	levelErr := checkLevels(meter.Samples(), meter.RMSDBFS(), meter.PeakDBFS())
+	if levelErr != nil {
+		fmt.Printf("FAIL: %v\n", levelErr)
+	} else {
+		fmt.Printf("PASS: the recorder is capturing audio\n")
+	}
+
+	if *playback && len(recorded) > 0 {
+		fmt.Printf("Playing back the recording...\n")
+
+		if err := playAudio(recorded); err != nil {
+			return fmt.Errorf("error playing back audio: %w", err)
+		}
+	}
+
+	return levelErr
+}
+
+// checkLevels returns an error with troubleshooting guidance if the
+// measured levels indicate a problem with the recording.
+func checkLevels(samples int, rmsDBFS, peakDBFS float64) error {
+	switch {
+	case samples == 0:
+		return errors.New("no audio was recorded; check that the Recording " +
+			"application writes raw audio to stdout")
+	case peakDBFS < silencePeakDBFS:
+		return errors.New("the recording is silent; check that the right input " +
+			"device is selected and that the mic is not muted")
+	case peakDBFS >= clippedPeakDBFS:
+		return errors.New("the recording is clipped; lower the input level " +
+			"or move away from the mic")
+	case rmsDBFS < quietRMSDBFS:
+		return errors.New("the recording is very quiet; raise the input level " +
+			"or move closer to the mic")
+	default:
+		return nil
+	}
+}
+
+// playAudio plays the given audio with the configured player.
+func playAudio(data []byte) error {
+	player := audio.NewPlayer(appCfg.Playback)
+	if err := player.Start(); err != nil {
+		return err
+	}
+
+	if err := player.PushAudio(data); err != nil {
+		_ = player.Stop()
+
+		return err
+	}
+
+	return player.Stop()
+}
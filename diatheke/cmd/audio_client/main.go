@@ -16,51 +16,173 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"strings"
+	"time"
 
-	"github.com/cobaltspeech/examples-go/diatheke/internal/audio"
 	"github.com/cobaltspeech/examples-go/diatheke/internal/config"
+	"github.com/cobaltspeech/examples-go/diatheke/internal/diathekeclient"
+	"github.com/cobaltspeech/examples-go/diatheke/internal/interrupt"
+	"github.com/cobaltspeech/examples-go/diatheke/internal/logging"
+	"github.com/cobaltspeech/examples-go/diatheke/internal/transcript"
+	"github.com/cobaltspeech/examples-go/pkg/audio"
+	"github.com/cobaltspeech/examples-go/pkg/confidence"
 	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2"
 	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2/diathekepb"
 )
 
 const defaultBuffSize = 8192
 
+// Sample rate of recorded audio when the config file doesn't set it.
+const defaultSampleRate = 16000
+
+// Percentage of clipped samples in the recorded audio above which a
+// warning about the input level is printed.
+const clipWarningPercent = 1.0
+
 // Contains application settings as defined in the config file.
 var appCfg config.Config
 
+// Logs diagnostic messages to stderr. Set by main from the -verbose flag.
+var logger = logging.New(false)
+
+// Log of the dialog, written if the -transcript flag is set (nil otherwise).
+var transcriptLog *transcript.Writer
+
+// Target level (dBFS) used to normalize recorded audio. Zero disables
+// normalization.
+var normalizeDBFS float64
+
+// Format used to display the confidence of transcriptions, one of the
+// pkg/confidence formats.
+var confidenceFormat string
+
+// Duration of silence after speech that ends a recording. Zero disables
+// ending recordings on silence.
+var endSilence time.Duration
+
+// Cutoff frequency (Hz) of the high-pass filter applied to recorded audio.
+// Zero disables the filter.
+var highPassHz int
+
+// Whether recorded audio is stereo, and mixed down to mono before it is
+// sent.
+var downmix bool
+
+// Whether recorded audio is resampled from the recording SampleRate to
+// the model's ASR sample rate.
+var resample bool
+
+// ASR sample rate of the selected model, zero if it is unknown.
+var asrSampleRate int
+
+// Audio files to use for the user inputs instead of recording.
+var script audioScript
+
 func main() {
 	// Read the config file
 	configFile := flag.String("config", "config.toml", "Path to the config file")
+	dumpConfig := flag.String("dump-config", "", "Print the effective configuration as toml or json and exit")
+	transcriptPath := flag.String("transcript", "",
+		"Append a log of the dialog to this file, as timestamped JSON lines")
+	flag.Float64Var(&normalizeDBFS, "normalize", 0,
+		"Normalize recorded audio toward the given level in dBFS (e.g., -20). Zero disables normalization.")
+	flag.StringVar(&confidenceFormat, "confidence-format", confidence.Raw,
+		"How transcription confidence is displayed: none, raw (e.g., (0.92)) or percent (e.g., (92%))")
+	flag.DurationVar(&endSilence, "end-silence", 0,
+		"Stop recording after this much silence following speech (e.g., 1s). Assumes 16 kHz audio. Zero disables it.")
+	flag.IntVar(&highPassHz, "highpass", 0,
+		"Apply a high-pass filter with this cutoff in Hz (e.g., 80) to recorded audio, removing DC offset and hum. "+
+			"Zero disables it.")
+	flag.BoolVar(&downmix, "downmix", false, "Mix stereo recorded audio down to mono before sending it")
+	flag.BoolVar(&resample, "resample", false,
+		"Resample recorded audio from the recording SampleRate in the config file to the model's ASR sample rate")
+	flag.Var(&script, "audio-file",
+		"Use this audio file for the next user input instead of recording. Repeat for each input; the dialog ends when they are all used.")
+	verbose := flag.Bool("verbose", false, "Log debug messages")
 	flag.Parse()
 
+	logger = logging.New(*verbose)
+
+	if err := confidence.CheckFormat(confidenceFormat); err != nil {
+		log.Fatalf("invalid -confidence-format: %v", err)
+	}
+
+	if highPassHz < 0 {
+		log.Fatalf("invalid -highpass %d, must not be negative", highPassHz)
+	}
+
 	if err := loadConfig(*configFile); err != nil {
 		log.Fatalf("error reading config file: %v", err)
 	}
 
+	if *dumpConfig != "" {
+		if err := config.Dump(os.Stdout, appCfg, *dumpConfig); err != nil {
+			log.Fatalf("error dumping config: %v", err)
+		}
+
+		return
+	}
+
+	if flag.Arg(0) == "audio-test" {
+		if err := runAudioTest(flag.Args()[1:]); err != nil {
+			log.Fatalf("audio test failed: %v", err)
+		}
+
+		return
+	}
+
+	if *transcriptPath != "" {
+		var err error
+
+		if transcriptLog, err = transcript.Open(*transcriptPath, logger); err != nil {
+			log.Fatalf("error opening transcript: %v\n", err)
+		}
+
+		defer transcriptLog.Close()
+	}
+
 	// Create a new client
-	opts := make([]diatheke.Option, 0)
-	if appCfg.Server.Insecure {
-		// NOTE: Secure connections are recommended for production
-		opts = append(opts, diatheke.WithInsecure())
+	opts := appCfg.Server.DiathekeOptions()
+
+	// Session updates are retried, with a new connection, if the server
+	// becomes unavailable.
+	dial := func() (diathekeclient.Client, error) {
+		return diatheke.NewClient(appCfg.Server.Address, opts...)
 	}
 
-	client, err := diatheke.NewClient(appCfg.Server.Address, opts...)
+	client, err := diathekeclient.NewRetry(dial, appCfg.Server.RetryAttempts, diathekeclient.DefaultRetryDelay, logger)
 	if err != nil {
 		log.Fatalf("error creating client: %v\n", err)
 	}
 
 	defer client.Close()
 
-	if err := runDiatheke(client); err != nil {
+	// Ctrl-C ends the dialog, rather than the program, so that the
+	// session is deleted before exiting.
+	ctx, stop := interrupt.Context(context.Background())
+	defer stop()
+
+	if err := runDiatheke(ctx, client); err != nil {
 		log.Fatalf("error running Diatheke client: %v\n", err)
 	}
 }
 
-func runDiatheke(client *diatheke.Client) error {
+// runDiatheke runs a dialog until it ends or ctx is cancelled, then
+// deletes the session.
+//
+// Cancelling ctx cancels the ASR, TTS and transcribe streams, which ends
+// the recording or playback in progress: the stream errors out, and the
+// recorder or player is then stopped as it is at the end of any input or
+// reply, killing the external application if it is still running. When
+// the user presses Ctrl-C in a terminal, the applications receive the
+// interrupt as well and usually exit first.
+func runDiatheke(ctx context.Context, client diathekeclient.Client) error {
 	bctx := context.Background()
 
 	// Print the server version info
@@ -91,6 +213,26 @@ func runDiatheke(client *diatheke.Client) error {
 		fmt.Printf("    TTS Sample Rate: %v\n\n", mdl.TtsSampleRate)
 	}
 
+	// Select the model by name, if the config file doesn't give its ID.
+	if err := appCfg.Server.ResolveModelID(modelList.Models); err != nil {
+		return err
+	}
+
+	// Warn about audio settings that don't match the selected model.
+	// A missing model is reported by CreateSession. The recording rate
+	// is expected to differ from the model's when resampling.
+	if asrRate, ttsRate, err := appCfg.SampleRates(modelList.Models); err == nil {
+		asrSampleRate = int(asrRate)
+
+		if resample {
+			asrRate = 0
+		}
+
+		for _, warning := range appCfg.CheckSampleRates(asrRate, ttsRate) {
+			fmt.Printf("Warning: %s\n\n", warning)
+		}
+	}
+
 	// Create a session using the model specified in the config file.
 	session, err := client.CreateSession(bctx, appCfg.Server.ModelID)
 	if err != nil {
@@ -99,19 +241,26 @@ func runDiatheke(client *diatheke.Client) error {
 
 	// Begin processing actions
 	for {
-		session, err = processActions(client, session)
-		if err != nil {
-			fmt.Printf("error processing actions: %v\n", err)
+		next, err := processActions(ctx, client, session)
+		if ctx.Err() != nil {
+			logger.Info("msg", "dialog interrupted")
+
+			break
+		} else if err != nil {
+			logger.Error("msg", "error processing actions", "err", err)
 
 			break
-		} else if session == nil {
-			fmt.Printf("got nil session back")
+		} else if next == nil {
+			logger.Info("msg", "dialog ended")
 
 			break
 		}
+
+		session = next
 	}
 
-	// Clean up the session
+	// Clean up the session. This uses its own context, since ctx
+	// may have been cancelled.
 	if err = client.DeleteSession(bctx, session.Token); err != nil {
 		return fmt.Errorf("error deleting session: %w\n", err)
 	}
@@ -119,41 +268,65 @@ func runDiatheke(client *diatheke.Client) error {
 	return nil
 }
 
+// errNoActions is returned by processActions for an action list
+// without any action to execute.
+var errNoActions = errors.New("received an action list without any actions")
+
 // processActions executes the actions for the given session
-// and returns an updated session.
-func processActions(client *diatheke.Client, session *diathekepb.SessionOutput,
+// and returns an updated session. If the action list ends without
+// waiting for user input or executing a command, no further input
+// is expected and the dialog has ended, in which case a nil session
+// is returned without an error. An action list without any action,
+// which would otherwise silently end the dialog, is an error.
+func processActions(ctx context.Context, client diathekeclient.Client, session *diathekepb.SessionOutput,
 ) (*diathekepb.SessionOutput, error) {
+	// Set once a reply or transcribe action is executed.
+	handled := false
+
 	// Iterate through each action in the list and determine its type.
 	for _, action := range session.ActionList {
+		logger.Debug("msg", "processing action", "type", fmt.Sprintf("%T", action.Action))
+
 		if inputAction := action.GetInput(); inputAction != nil {
 			// The WaitForUserAction will involve a session update.
-			return waitForInput(client, session, inputAction)
+			return waitForInput(ctx, client, session, inputAction)
 		} else if reply := action.GetReply(); reply != nil {
 			// Replies do not require a session update.
-			if err := handleReply(client, reply); err != nil {
+			if err := handleReply(ctx, client, reply); err != nil {
 				return nil, err
 			}
+
+			handled = true
 		} else if cmd := action.GetCommand(); cmd != nil {
 			// The CommandAction will involve a session update.
-			return handleCommand(client, session, cmd)
+			return handleCommand(ctx, client, session, cmd)
 		} else if scribe := action.GetTranscribe(); scribe != nil {
 			// Transcribe actions do not require a session update.
-			if err := handleTranscribe(client, scribe); err != nil {
+			if err := handleTranscribe(ctx, client, scribe); err != nil {
 				return nil, err
 			}
+
+			handled = true
 		} else if action.Action != nil {
 			return nil, fmt.Errorf("received unknown action type %T", action.Action)
 		}
 	}
 
-	return nil, fmt.Errorf("action list ended without session update")
+	if !handled {
+		return nil, errNoActions
+	}
+
+	// The action list did not require a session update, which means
+	// the dialog has ended.
+	return nil, nil
 }
 
 // waitForInput creates an ASR stream and records audio from the user.
 // The audio is sent to Diatheke until an ASR result is returned, which
 // is used to return an updated session.
 func waitForInput(
-	client *diatheke.Client,
+	ctx context.Context,
+	client diathekeclient.Client,
 	session *diathekepb.SessionOutput,
 	inputAction *diathekepb.WaitForUserAction,
 ) (*diathekepb.SessionOutput, error) {
@@ -165,51 +338,123 @@ func waitForInput(
 		// reply immediately. If this flag is false, the app may wait
 		// as long as it wants before processing user input (such as
 		// waiting for a wake-word below).
-		fmt.Printf("(Immediate input required)")
+		logger.Debug("msg", "immediate input required")
 	}
 
 	if inputAction.RequiresWakeWord {
 		// This action requires the wake-word to be spoken before
 		// any other audio will be processed. Use a wake-word detector
 		// and wait for it to trigger.
-		fmt.Printf("(Wakeword required)")
+		logger.Debug("msg", "wake word required")
+	}
+
+	// Create something to handle recording audio
+	source, err := script.newSource(appCfg.Recording)
+	if errors.Is(err, errScriptDone) {
+		// The dialog ends with the script.
+		logger.Info("msg", "no more audio files")
+		return nil, nil
+	} else if err != nil {
+		return nil, err
 	}
 
 	// Create an ASR stream
-	stream, err := client.NewSessionASRStream(context.Background(), session.Token)
+	stream, err := client.NewSessionASRStream(ctx, session.Token)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create something to handle recording audio
-	recorder := audio.NewRecorder(appCfg.Recording)
-	if err = recorder.Start(); err != nil {
+	recorded, err := recordedAudio(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = source.Start(); err != nil {
 		return nil, err
 	}
 
 	fmt.Printf("Recording...\n")
 
 	// Record until we get a result
-	result, err := diatheke.ReadASRAudio(stream, recorder.Output(), defaultBuffSize)
+	result, err := diatheke.ReadASRAudio(stream, recorded, defaultBuffSize)
 
-	recorder.Stop()
+	// If the recording application crashed, the audio ended because of
+	// that rather than because the user stopped talking.
+	if recErr := source.Stop(); recErr != nil {
+		return nil, recErr
+	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	fmt.Printf("  ASRResult: %v\n\n", result)
+	logger.Info("msg", "ASR result", "text", result.Text, "confidence", result.Confidence)
+	transcriptLog.ASRResult(result)
 
 	// Update the session with the result
-	return client.ProcessASRResult(context.Background(), session.Token, result)
+	return client.ProcessASRResult(ctx, session.Token, result)
+}
+
+// recordedAudio returns the audio from the given source, mixed down to
+// mono, resampled and high-pass filtered if requested, then checked for
+// clipping, ended on silence and normalized if requested.
+func recordedAudio(source audio.AudioSource) (io.Reader, error) {
+	var r io.Reader = source
+	if downmix {
+		r = audio.DownmixStereo(r)
+	}
+
+	rate := appCfg.Recording.SampleRate
+	if rate == 0 {
+		rate = defaultSampleRate
+	}
+
+	if resample {
+		if asrSampleRate == 0 {
+			return nil, fmt.Errorf("unable to resample, the model's ASR sample rate is unknown")
+		}
+
+		var err error
+		if r, err = audio.NewResampler(r, rate, asrSampleRate); err != nil {
+			return nil, err
+		}
+
+		rate = asrSampleRate
+	}
+
+	if highPassHz > 0 {
+		var err error
+		if r, err = audio.NewHighPass(r, highPassHz, rate); err != nil {
+			return nil, err
+		}
+	}
+
+	if endSilence > 0 {
+		r = audio.NewVADReader(r, audio.VADConfig{SilenceDuration: endSilence})
+	}
+
+	r = audio.NewClipDetector(r, clipWarningPercent, warnClipping)
+
+	if normalizeDBFS == 0 {
+		return r, nil
+	}
+
+	return audio.NewNormalizer(r, normalizeDBFS), nil
+}
+
+// warnClipping prints a warning that the recorded audio is clipped.
+func warnClipping(percent float64) {
+	fmt.Printf("\nWarning: %.1f%% of the recorded audio is clipped, "+
+		"lower the input level for better accuracy.\n", percent)
 }
 
 // handleReply uses TTS to play back the reply as speech.
-func handleReply(client *diatheke.Client, reply *diathekepb.ReplyAction) error {
-	fmt.Printf("  Reply: %v\n\n", reply)
+func handleReply(ctx context.Context, client diathekeclient.Client, reply *diathekepb.ReplyAction) error {
+	logger.Info("msg", "reply", "text", reply.Text)
+	transcriptLog.Reply(reply)
 
 	// Create the TTS stream
-	stream, err := client.NewTTSStream(context.Background(), reply)
+	stream, err := client.NewTTSStream(ctx, reply)
 	if err != nil {
 		return err
 	}
@@ -222,26 +467,40 @@ func handleReply(client *diatheke.Client, reply *diathekepb.ReplyAction) error {
 		return err
 	}
 
-	// Play the entire reply uninterrupted
-	if err = diatheke.WriteTTSAudio(stream, player.Input()); err != nil {
-		return err
+	// Play the entire reply, unless the dialog is interrupted
+	err = diatheke.WriteTTSAudio(stream, player.Input())
+
+	// Stop the player, even if the reply did not finish
+	if stopErr := player.Stop(); err == nil {
+		err = stopErr
 	}
 
-	// Stop the player
-	return player.Stop()
+	return err
 }
 
 // handleTranscribe uses ASR to record a transcription from the user.
-func handleTranscribe(client *diatheke.Client, scribe *diathekepb.TranscribeAction) error {
+func handleTranscribe(ctx context.Context, client diathekeclient.Client, scribe *diathekepb.TranscribeAction) error {
+	// Create something to handle recording audio
+	source, err := script.newSource(appCfg.Recording)
+	if errors.Is(err, errScriptDone) {
+		logger.Info("msg", "no more audio files, skipping transcription")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
 	// Create the transcription stream
-	stream, err := client.NewTranscribeStream(context.Background(), scribe)
+	stream, err := client.NewTranscribeStream(ctx, scribe)
 	if err != nil {
 		return err
 	}
 
-	// Create something to handle recording audio
-	recorder := audio.NewRecorder(appCfg.Recording)
-	if err = recorder.Start(); err != nil {
+	recorded, err := recordedAudio(source)
+	if err != nil {
+		return err
+	}
+
+	if err = source.Start(); err != nil {
 		return err
 	}
 
@@ -253,7 +512,11 @@ func handleTranscribe(client *diatheke.Client, scribe *diathekepb.TranscribeActi
 		// Print the result on the same line (overwrite current
 		// contents). Note that this assumes that stdout is going
 		// to a terminal.
-		fmt.Printf("\r%s (confidence: %v)", result.Text, result.Confidence)
+		fmt.Printf("\r%s", result.Text)
+
+		if c := confidence.Format(result.Confidence, confidenceFormat); c != "" {
+			fmt.Printf(" %s", c)
+		}
 
 		if result.IsPartial {
 			return
@@ -267,32 +530,38 @@ func handleTranscribe(client *diatheke.Client, scribe *diathekepb.TranscribeActi
 		finalTranscription.WriteString(result.Text)
 	}
 
-	err = diatheke.ReadTranscribeAudio(stream, recorder.Output(), defaultBuffSize, handler)
+	err = diatheke.ReadTranscribeAudio(stream, recorded, defaultBuffSize, handler)
+
+	if recErr := source.Stop(); recErr != nil {
+		return recErr
+	}
+
 	if err != nil {
 		return err
 	}
 
 	fmt.Printf("  Transcription: %v\n\n", finalTranscription.String())
+	transcriptLog.Transcription(scribe, finalTranscription.String())
 
 	return nil
 }
 
 // handleCommand executes the specified command.
 func handleCommand(
-	client *diatheke.Client,
+	ctx context.Context,
+	client diathekeclient.Client,
 	session *diathekepb.SessionOutput,
 	cmd *diathekepb.CommandAction,
 ) (*diathekepb.SessionOutput, error) {
-	fmt.Printf("  Command:\n")
-	fmt.Printf("    ID: %v\n", cmd.Id)
-	fmt.Printf("    Input params: %v\n\n", cmd.InputParameters)
+	logger.Info("msg", "command", "id", cmd.Id, "inputParams", cmd.InputParameters)
+	transcriptLog.Command(cmd)
 
 	// Update the session with the command result
 	result := diathekepb.CommandResult{
 		Id: cmd.Id,
 	}
 
-	session, err := client.ProcessCommandResult(context.Background(), session.Token, &result)
+	session, err := client.ProcessCommandResult(ctx, session.Token, &result)
 	if err != nil {
 		err = fmt.Errorf("ProcessCommandResult error: %w", err)
 	}
@@ -311,16 +580,16 @@ func loadConfig(filepath string) error {
 
 	// Verify that we have the required fields for this demo.
 	// The following are required for this demo
-	if appCfg.Server.ModelID == "" {
-		return fmt.Errorf("missing Diatheke ModelID in the config file")
+	if appCfg.Server.ModelID == "" && appCfg.Server.ModelName == "" {
+		return fmt.Errorf("missing Diatheke ModelID or ModelName in the config file")
 	}
 
 	if appCfg.Playback.Application == "" {
 		return fmt.Errorf("missing Playback application in the config file")
 	}
 
-	if appCfg.Recording.Application == "" {
-		return fmt.Errorf("missing Recording application in the config file")
+	if len(script.files) == 0 && appCfg.Recording.Application == "" && appCfg.Recording.SourceFile == "" {
+		return fmt.Errorf("missing Recording application or source file in the config file")
 	}
 
 	return nil
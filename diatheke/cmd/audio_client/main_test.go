@@ -0,0 +1,142 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cobaltspeech/examples-go/diatheke/internal/diathekeclient"
+	"github.com/cobaltspeech/examples-go/pkg/audio"
+	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2/diathekepb"
+)
+
+func TestCheckLevels(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		name     string
+		samples  int
+		rms      float64
+		peak     float64
+		wantFail bool
+	}{
+		{"good", 48000, -25, -6, false},
+		{"empty", 0, math.Inf(-1), math.Inf(-1), true},
+		{"silent", 48000, math.Inf(-1), math.Inf(-1), true},
+		{"dead mic", 48000, -80, -70, true},
+		{"clipped", 48000, -10, 0, true},
+		{"quiet", 48000, -55, -30, true},
+	}
+
+	for _, test := range testList {
+		if err := checkLevels(test.samples, test.rms, test.peak); (err != nil) != test.wantFail {
+			t.Errorf("%s: unexpected result %v", test.name, err)
+		}
+	}
+}
+
+// TestScriptedDialog is not run in parallel since it sets the script.
+func TestScriptedDialog(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{filepath.Join(dir, "1.raw"), filepath.Join(dir, "2.raw")}
+
+	for _, path := range files {
+		if err := os.WriteFile(path, make([]byte, 320), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	script = audioScript{files: files}
+	defer func() { script = audioScript{} }()
+
+	waitForInput := &diathekepb.SessionOutput{
+		ActionList: []*diathekepb.ActionData{{Action: &diathekepb.ActionData_Input{Input: &diathekepb.WaitForUserAction{}}}},
+	}
+
+	client := &diathekeclient.Fake{
+		ASRResult: &diathekepb.ASRResult{Text: "hello"},
+		Sessions:  []*diathekepb.SessionOutput{waitForInput, waitForInput},
+	}
+
+	// Each input uses the next file, then the dialog ends.
+	session := waitForInput
+	for turn := 1; session != nil; turn++ {
+		var err error
+
+		if session, err = processActions(context.Background(), client, session); err != nil {
+			t.Fatalf("turn %d: %v", turn, err)
+		}
+
+		if turn > len(files)+1 {
+			t.Fatal("the dialog did not end with the script")
+		}
+	}
+
+	if len(client.ASRResults) != len(files) {
+		t.Errorf("incorrect number of ASR results - expected: %d, actual: %d", len(files), len(client.ASRResults))
+	}
+}
+
+func TestRecordedAudioProcessing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stereo.raw")
+
+	// 1600 stereo frames at 16 kHz.
+	if err := os.WriteFile(path, make([]byte, 1600*4), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	appCfg.Recording.SampleRate = 16000
+	downmix, resample, highPassHz = true, true, 80
+
+	defer func() {
+		appCfg.Recording.SampleRate = 0
+		downmix, resample, highPassHz, asrSampleRate = false, false, 0, 0
+	}()
+
+	source := audio.NewFileSource(path)
+
+	// The model's rate is needed to resample.
+	if _, err := recordedAudio(source); err == nil {
+		t.Error("expected an error without the model's ASR sample rate")
+	}
+
+	asrSampleRate = 8000
+
+	r, err := recordedAudio(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := source.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	defer source.Stop()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Mixed down to mono and resampled to 8 kHz: 800 16-bit samples.
+	if len(out) != 800*2 {
+		t.Errorf("incorrect output length - expected: %d, actual: %d", 800*2, len(out))
+	}
+}
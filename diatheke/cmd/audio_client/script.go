@@ -0,0 +1,66 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/cobaltspeech/examples-go/pkg/audio"
+)
+
+// errScriptDone is returned once all the audio files of a script are used.
+var errScriptDone = errors.New("no more audio files")
+
+// audioScript is the list of audio files given with -audio-file, used in
+// order, one for each user input, instead of recording, so that dialogs
+// can be tested automatically. Each file ends the utterance at its end,
+// since ReadASRAudio closes the stream when the audio reaches EOF, which
+// makes Diatheke return the ASR result.
+type audioScript struct {
+	files []string
+	next  int
+}
+
+// String implements the flag.Value interface.
+func (s *audioScript) String() string {
+	return strings.Join(s.files, ",")
+}
+
+// Set implements the flag.Value interface, adding a file to the script.
+func (s *audioScript) Set(path string) error {
+	s.files = append(s.files, path)
+	return nil
+}
+
+// newSource returns the audio source for the next user input: the next
+// file of the script, or the source set in the given config if there is
+// no script. Returns errScriptDone if all the files have been used.
+func (s *audioScript) newSource(cfg audio.Config) (audio.AudioSource, error) {
+	if len(s.files) == 0 {
+		return audio.NewSourceFromConfig(cfg), nil
+	}
+
+	if s.next >= len(s.files) {
+		return nil, errScriptDone
+	}
+
+	path := s.files[s.next]
+	s.next++
+
+	logger.Info("msg", "using audio file", "path", path)
+
+	return audio.NewFileSource(path), nil
+}
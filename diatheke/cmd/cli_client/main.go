@@ -17,12 +17,19 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/cobaltspeech/examples-go/diatheke/internal/config"
+	"github.com/cobaltspeech/examples-go/diatheke/internal/diathekeclient"
+	"github.com/cobaltspeech/examples-go/diatheke/internal/interrupt"
+	"github.com/cobaltspeech/examples-go/diatheke/internal/logging"
+	"github.com/cobaltspeech/examples-go/diatheke/internal/transcript"
 	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2"
 	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2/diathekepb"
 )
@@ -30,36 +37,81 @@ import (
 // Contains application settings as defined in the config file.
 var appCfg config.Config
 
+// Logs diagnostic messages to stderr. Set by main from the -verbose flag.
+var logger = logging.New(false)
+
+// Log of the dialog, written if the -transcript flag is set (nil otherwise).
+var transcriptLog *transcript.Writer
+
+// Receives the lines of user input, which are read in the background so
+// that waiting for input can be interrupted. Set by main.
+var lines <-chan string
+
 func main() {
 	// Read the config file
 	configFile := flag.String("config", "config.toml", "Path to the config file")
+	dumpConfig := flag.String("dump-config", "", "Print the effective configuration as toml or json and exit")
+	transcriptPath := flag.String("transcript", "",
+		"Append a log of the dialog to this file, as timestamped JSON lines")
+	verbose := flag.Bool("verbose", false, "Log debug messages")
 
 	flag.Parse()
 
+	logger = logging.New(*verbose)
+
 	if err := loadConfig(*configFile); err != nil {
 		log.Fatalf("error reading config file: %v", err)
 	}
 
+	if *dumpConfig != "" {
+		if err := config.Dump(os.Stdout, appCfg, *dumpConfig); err != nil {
+			log.Fatalf("error dumping config: %v", err)
+		}
+
+		return
+	}
+
+	if *transcriptPath != "" {
+		var err error
+
+		if transcriptLog, err = transcript.Open(*transcriptPath, logger); err != nil {
+			log.Fatalf("error opening transcript: %v\n", err)
+		}
+
+		defer transcriptLog.Close()
+	}
+
 	// Create a new client
-	opts := make([]diatheke.Option, 0)
-	if appCfg.Server.Insecure {
-		// NOTE: Secure connections are recommended for production
-		opts = append(opts, diatheke.WithInsecure())
+	opts := appCfg.Server.DiathekeOptions()
+
+	// Session updates are retried, with a new connection, if the server
+	// becomes unavailable.
+	dial := func() (diathekeclient.Client, error) {
+		return diatheke.NewClient(appCfg.Server.Address, opts...)
 	}
 
-	client, err := diatheke.NewClient(appCfg.Server.Address, opts...)
+	client, err := diathekeclient.NewRetry(dial, appCfg.Server.RetryAttempts, diathekeclient.DefaultRetryDelay, logger)
 	if err != nil {
 		log.Fatalf("error creating client: %v\n", err)
 	}
 
 	defer client.Close()
 
-	if err := runDiatheke(client); err != nil {
+	// Ctrl-C ends the dialog, rather than the program, so that the
+	// session is deleted before exiting.
+	ctx, stop := interrupt.Context(context.Background())
+	defer stop()
+
+	lines = readLines(os.Stdin)
+
+	if err := runDiatheke(ctx, client); err != nil {
 		log.Fatalf("error running Diatheke client: %v\n", err)
 	}
 }
 
-func runDiatheke(client *diatheke.Client) error {
+// runDiatheke runs a dialog until it ends or ctx is cancelled, then
+// deletes the session.
+func runDiatheke(ctx context.Context, client diathekeclient.Client) error {
 	bctx := context.Background()
 
 	// Print the server version info
@@ -90,6 +142,11 @@ func runDiatheke(client *diatheke.Client) error {
 		fmt.Printf("    TTS Sample Rate: %v\n\n", mdl.TtsSampleRate)
 	}
 
+	// Select the model by name, if the config file doesn't give its ID.
+	if err := appCfg.Server.ResolveModelID(modelList.Models); err != nil {
+		return err
+	}
+
 	// Create a session using the specified model ID.
 	session, err := client.CreateSession(bctx, appCfg.Server.ModelID)
 	if err != nil {
@@ -98,19 +155,26 @@ func runDiatheke(client *diatheke.Client) error {
 
 	// Begin processing actions
 	for {
-		session, err = processActions(client, session)
-		if err != nil {
-			fmt.Printf("error processing actions: %v\n", err)
+		next, err := processActions(ctx, client, session)
+		if ctx.Err() != nil {
+			logger.Info("msg", "dialog interrupted")
+
+			break
+		} else if err != nil {
+			logger.Error("msg", "error processing actions", "err", err)
 
 			break
-		} else if session == nil {
-			fmt.Printf("got nil session back")
+		} else if next == nil {
+			logger.Info("msg", "dialog ended")
 
 			break
 		}
+
+		session = next
 	}
 
-	// Clean up the session.
+	// Clean up the session. This uses its own context, since ctx
+	// may have been cancelled.
 	if err = client.DeleteSession(bctx, session.Token); err != nil {
 		return fmt.Errorf("error deleting session: %w\n", err)
 	}
@@ -118,48 +182,76 @@ func runDiatheke(client *diatheke.Client) error {
 	return nil
 }
 
+// errNoActions is returned by processActions for an action list
+// without any action to execute.
+var errNoActions = errors.New("received an action list without any actions")
+
 // processActions executes the actions for the given session
-// and returns an updated session.
-func processActions(client *diatheke.Client, session *diathekepb.SessionOutput,
+// and returns an updated session. If the action list ends without
+// waiting for user input or executing a command, no further input
+// is expected and the dialog has ended, in which case a nil session
+// is returned without an error. An action list without any action,
+// which would otherwise silently end the dialog, is an error.
+func processActions(ctx context.Context, client diathekeclient.Client, session *diathekepb.SessionOutput,
 ) (*diathekepb.SessionOutput, error) {
+	// Set once a reply or transcribe action is executed.
+	handled := false
+
 	// Iterate through each action in the list and determine its type.
 	for _, action := range session.ActionList {
+		logger.Debug("msg", "processing action", "type", fmt.Sprintf("%T", action.Action))
+
 		if inputAction := action.GetInput(); inputAction != nil {
 			// The WaitForUserAction will involve a session update.
-			return waitForInput(client, session)
+			return waitForInput(ctx, client, session)
 		} else if reply := action.GetReply(); reply != nil {
 			// Replies do not require a session update.
 			handleReply(reply)
+
+			handled = true
 		} else if cmd := action.GetCommand(); cmd != nil {
 			// The CommandAction will involve a session update.
-			return handleCommand(client, session, cmd)
+			return handleCommand(ctx, client, session, cmd)
 		} else if scribe := action.GetTranscribe(); scribe != nil {
 			// Transcribe actions do not require a session update.
-			handleTranscribe(scribe)
+			handleTranscribe(ctx, scribe)
+
+			handled = true
 		} else if action.Action != nil {
 			return nil, fmt.Errorf("received unknown action type %T", action.Action)
 		}
 	}
 
-	return nil, fmt.Errorf("action list ended without session update")
+	if !handled {
+		return nil, errNoActions
+	}
+
+	// The action list did not require a session update, which means
+	// the dialog has ended.
+	return nil, nil
 }
 
 // waitForInput prompts the user for text input, then updates the
 // session based on the user-supplied text.
 func waitForInput(
-	client *diatheke.Client,
+	ctx context.Context,
+	client diathekeclient.Client,
 	session *diathekepb.SessionOutput,
 ) (*diathekepb.SessionOutput, error) {
 	// Display a prompt
 	fmt.Printf("\n\nDiatheke> ")
 
 	// Wait for user input on stdin
-	scanner := bufio.NewScanner(os.Stdin)
-	scanner.Scan()
-	text := scanner.Text()
+	text, ok := readLine(ctx)
+	if !ok && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	logger.Debug("msg", "user input", "text", text)
+	transcriptLog.Text(text)
 
 	// Update the session with the text
-	session, err := client.ProcessText(context.Background(), session.Token, text)
+	session, err := client.ProcessText(ctx, session.Token, text)
 	if err != nil {
 		err = fmt.Errorf("ProcessText error: %w", err)
 	}
@@ -170,25 +262,26 @@ func waitForInput(
 // handleReply prints the given reply text to stdout.
 func handleReply(reply *diathekepb.ReplyAction) {
 	fmt.Printf("  Reply: %v\n", reply.Text)
+	transcriptLog.Reply(reply)
 }
 
 // handleCommand executes the task specified by the given command
 // and returns an updated session based on the command result.
 func handleCommand(
-	client *diatheke.Client,
+	ctx context.Context,
+	client diathekeclient.Client,
 	session *diathekepb.SessionOutput,
 	cmd *diathekepb.CommandAction,
 ) (*diathekepb.SessionOutput, error) {
-	fmt.Printf("  Command:\n")
-	fmt.Printf("    ID: %v\n", cmd.Id)
-	fmt.Printf("    Input params: %v\n\n", cmd.InputParameters)
+	logger.Info("msg", "command", "id", cmd.Id, "inputParams", cmd.InputParameters)
+	transcriptLog.Command(cmd)
 
 	// Update the session with the command result
 	result := diathekepb.CommandResult{
 		Id: cmd.Id,
 	}
 
-	session, err := client.ProcessCommandResult(context.Background(), session.Token, &result)
+	session, err := client.ProcessCommandResult(ctx, session.Token, &result)
 	if err != nil {
 		err = fmt.Errorf("ProcessCommandResult error: %w", err)
 	}
@@ -196,9 +289,62 @@ func handleCommand(
 	return session, err
 }
 
-// handleTranscribe uses ASR to record a transcription from the user.
-func handleTranscribe(scribe *diathekepb.TranscribeAction) {
-	fmt.Printf("  Transcribe: %+v\n", scribe)
+// handleTranscribe reads the transcription from stdin, one line at a
+// time until a blank line, the end of the input or an interrupt, in place
+// of the ASR results the audio client would receive, and prints it.
+func handleTranscribe(ctx context.Context, scribe *diathekepb.TranscribeAction) {
+	fmt.Printf("  Transcribe: %v (end with a blank line)\n", scribe.Id)
+
+	var lines []string
+
+	for {
+		fmt.Printf("\nTranscribe> ")
+
+		line, ok := readLine(ctx)
+		if !ok {
+			break
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+
+		lines = append(lines, line)
+	}
+
+	text := strings.Join(lines, " ")
+
+	fmt.Printf("  Transcription: %v\n\n", text)
+	transcriptLog.Transcription(scribe, text)
+}
+
+// readLines reads lines from r in the background, until the end of the
+// input. The returned channel is closed once there are no more lines.
+func readLines(r io.Reader) <-chan string {
+	ch := make(chan string)
+
+	go func() {
+		defer close(ch)
+
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			ch <- scanner.Text()
+		}
+	}()
+
+	return ch
+}
+
+// readLine waits for the next line of user input. It returns false at
+// the end of the input or if ctx is cancelled first.
+func readLine(ctx context.Context) (string, bool) {
+	select {
+	case line, ok := <-lines:
+		return line, ok
+	case <-ctx.Done():
+		return "", false
+	}
 }
 
 // loadConfig reads the specified config file at application startup.
@@ -212,8 +358,8 @@ func loadConfig(filepath string) error {
 
 	// Verify that we have the required fields for this demo.
 	// The following are required for this demo
-	if appCfg.Server.ModelID == "" {
-		return fmt.Errorf("missing Diatheke ModelID in the config file")
+	if appCfg.Server.ModelID == "" && appCfg.Server.ModelName == "" {
+		return fmt.Errorf("missing Diatheke ModelID or ModelName in the config file")
 	}
 
 	return nil
@@ -0,0 +1,185 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cobaltspeech/examples-go/diatheke/internal/diathekeclient"
+	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2/diathekepb"
+)
+
+func replyAction(text string) *diathekepb.ActionData {
+	return &diathekepb.ActionData{
+		Action: &diathekepb.ActionData_Reply{Reply: &diathekepb.ReplyAction{Text: text}},
+	}
+}
+
+func commandAction(id string) *diathekepb.ActionData {
+	return &diathekepb.ActionData{
+		Action: &diathekepb.ActionData_Command{Command: &diathekepb.CommandAction{Id: id}},
+	}
+}
+
+func newSession(actions ...*diathekepb.ActionData) *diathekepb.SessionOutput {
+	return &diathekepb.SessionOutput{Token: &diathekepb.TokenData{}, ActionList: actions}
+}
+
+func TestProcessActionsCommand(t *testing.T) {
+	t.Parallel()
+
+	next := newSession(replyAction("done"))
+	fake := &diathekeclient.Fake{Sessions: []*diathekepb.SessionOutput{next}}
+
+	actual, err := processActions(context.Background(), fake, newSession(replyAction("hello"), commandAction("lights_on")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual != next {
+		t.Errorf("incorrect session - expected: %v, actual: %v", next, actual)
+	}
+
+	if len(fake.CommandResults) != 1 || fake.CommandResults[0].Id != "lights_on" {
+		t.Errorf("incorrect command results: %v", fake.CommandResults)
+	}
+}
+
+func TestProcessActionsDialogEnded(t *testing.T) {
+	t.Parallel()
+
+	fake := &diathekeclient.Fake{}
+
+	actual, err := processActions(context.Background(), fake, newSession(replyAction("goodbye")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual != nil {
+		t.Errorf("expected the dialog to end, got session %v", actual)
+	}
+}
+
+func TestProcessActionsNoActions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		session *diathekepb.SessionOutput
+	}{
+		{name: "empty", session: newSession()},
+		{name: "nil actions", session: newSession(&diathekepb.ActionData{}, &diathekepb.ActionData{})},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := processActions(context.Background(), &diathekeclient.Fake{}, tt.session)
+			if !errors.Is(err, errNoActions) {
+				t.Errorf("incorrect error - expected: %v, actual: %v", errNoActions, err)
+			}
+
+			if actual != nil {
+				t.Errorf("unexpected session %v", actual)
+			}
+		})
+	}
+}
+
+func TestHandleCommandError(t *testing.T) {
+	t.Parallel()
+
+	errFake := errors.New("server error")
+	fake := &diathekeclient.Fake{Err: errFake}
+
+	_, err := handleCommand(context.Background(), fake, newSession(), &diathekepb.CommandAction{Id: "lights_on"})
+	if !errors.Is(err, errFake) {
+		t.Errorf("incorrect error - expected: %v, actual: %v", errFake, err)
+	}
+}
+
+func TestRunDiathekeDeletesSession(t *testing.T) {
+	t.Parallel()
+
+	session := newSession(replyAction("goodbye"))
+	fake := &diathekeclient.Fake{Sessions: []*diathekepb.SessionOutput{session}}
+
+	if err := runDiatheke(context.Background(), fake); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fake.DeletedTokens) != 1 || fake.DeletedTokens[0] != session.Token {
+		t.Errorf("session was not deleted: %v", fake.DeletedTokens)
+	}
+}
+
+func TestRunDiathekeInterrupted(t *testing.T) {
+	t.Parallel()
+
+	// The dialog is interrupted while waiting for user input, which
+	// must still delete the session.
+	session := newSession(&diathekepb.ActionData{
+		Action: &diathekepb.ActionData_Input{Input: &diathekepb.WaitForUserAction{}},
+	})
+	fake := &diathekeclient.Fake{Sessions: []*diathekepb.SessionOutput{session}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := runDiatheke(ctx, fake); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fake.Texts) != 0 {
+		t.Errorf("unexpected text after the interrupt: %v", fake.Texts)
+	}
+
+	if len(fake.DeletedTokens) != 1 || fake.DeletedTokens[0] != session.Token {
+		t.Errorf("session was not deleted: %v", fake.DeletedTokens)
+	}
+}
+
+func TestRunDiathekeNilSession(t *testing.T) {
+	t.Parallel()
+
+	// The server returns no session after the command, so processActions
+	// returns nil, nil, which must end the dialog without a panic and
+	// still delete the session.
+	session := newSession(commandAction("lights_on"))
+	fake := &diathekeclient.Fake{Sessions: []*diathekepb.SessionOutput{session, nil}}
+
+	next, err := processActions(context.Background(), &diathekeclient.Fake{
+		Sessions: []*diathekepb.SessionOutput{nil},
+	}, session)
+	if next != nil || err != nil {
+		t.Fatalf("expected nil, nil from processActions, got %v, %v", next, err)
+	}
+
+	if err := runDiatheke(context.Background(), fake); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fake.CommandResults) != 1 {
+		t.Errorf("incorrect command results: %v", fake.CommandResults)
+	}
+
+	if len(fake.DeletedTokens) != 1 || fake.DeletedTokens[0] != session.Token {
+		t.Errorf("session was not deleted: %v", fake.DeletedTokens)
+	}
+}
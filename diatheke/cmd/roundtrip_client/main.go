@@ -0,0 +1,244 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/cobaltspeech/examples-go/diatheke/internal/config"
+	"github.com/cobaltspeech/examples-go/diatheke/internal/diathekeclient"
+	"github.com/cobaltspeech/examples-go/diatheke/internal/logging"
+	"github.com/cobaltspeech/examples-go/pkg/audio"
+	"github.com/cobaltspeech/examples-go/pkg/wer"
+	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2"
+	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2/diathekepb"
+)
+
+const defaultBuffSize = 8192
+
+// Contains application settings as defined in the config file.
+var appCfg config.Config
+
+func main() {
+	configFile := flag.String("config", "config.toml", "Path to the config file")
+	dumpConfig := flag.String("dump-config", "", "Print the effective configuration as toml or json and exit")
+	lunaModel := flag.String("luna-model", "", "Luna (TTS) model used to synthesize the text")
+	cubicModel := flag.String("cubic-model", "", "Cubic (ASR) model used to transcribe the audio")
+	maxWER := flag.Float64("max-wer", 0, "Highest word error rate (0 to 1) that is reported as a pass")
+	verbose := flag.Bool("verbose", false, "Log debug messages")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(),
+			"Usage: %s [flags] [text...]\n\nEach argument is synthesized and transcribed. "+
+				"If there are none, each line of stdin is used.\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	logger := logging.New(*verbose)
+
+	if err := loadConfig(*configFile); err != nil {
+		log.Fatalf("error reading config file: %v", err)
+	}
+
+	if *dumpConfig != "" {
+		if err := config.Dump(os.Stdout, appCfg, *dumpConfig); err != nil {
+			log.Fatalf("error dumping config: %v", err)
+		}
+
+		return
+	}
+
+	texts := flag.Args()
+	if len(texts) == 0 {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				texts = append(texts, line)
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			log.Fatalf("error reading stdin: %v", err)
+		}
+	}
+
+	// Create a new client
+	opts := appCfg.Server.DiathekeOptions()
+
+	// Session updates are retried, with a new connection, if the server
+	// becomes unavailable.
+	dial := func() (diathekeclient.Client, error) {
+		return diatheke.NewClient(appCfg.Server.Address, opts...)
+	}
+
+	client, err := diathekeclient.NewRetry(dial, appCfg.Server.RetryAttempts, diathekeclient.DefaultRetryDelay, logger)
+	if err != nil {
+		log.Fatalf("error creating client: %v\n", err)
+	}
+
+	defer client.Close()
+
+	modelList, err := client.ListModels(context.Background())
+	if err != nil {
+		log.Fatalf("error getting model list: %v", err)
+	}
+
+	// Select the model by name, if the config file doesn't give its ID.
+	if err := appCfg.Server.ResolveModelID(modelList.Models); err != nil {
+		log.Fatalf("error selecting model: %v", err)
+	}
+
+	// The synthesized audio is resampled to the ASR sample rate if the
+	// model's rates differ.
+	asrRate, ttsRate, err := appCfg.SampleRates(modelList.Models)
+	if err != nil {
+		log.Fatalf("error getting sample rates: %v", err)
+	}
+
+	rates := sampleRates{tts: ttsRate, asr: asrRate}
+	failed := 0
+
+	for _, text := range texts {
+		result, err := roundTrip(context.Background(), client, text, *lunaModel, *cubicModel, rates)
+		if err != nil {
+			log.Fatalf("error running round trip: %v", err)
+		}
+
+		status := "PASS"
+		if result.WER > *maxWER {
+			status = "FAIL"
+			failed++
+		}
+
+		fmt.Printf("%s (WER %.1f%%)\n", status, result.WER*100) //nolint:gomnd // converting to a percentage
+		fmt.Printf("  Text:       %s\n", result.Text)
+		fmt.Printf("  Transcript: %s\n", result.Transcript)
+	}
+
+	if failed > 0 {
+		log.Fatalf("%d of %d round trips failed", failed, len(texts))
+	}
+}
+
+// roundTripResult is the outcome of a single round trip.
+type roundTripResult struct {
+	Text       string
+	Transcript string
+	WER        float64
+}
+
+// sampleRates are the TTS and ASR sample rates of the selected model.
+type sampleRates struct {
+	tts uint32
+	asr uint32
+}
+
+// roundTrip synthesizes the given text, transcribes the synthesized audio
+// and returns the word error rate of the transcript against the text. The
+// synthesized audio is resampled from the TTS to the ASR sample rate if they
+// differ, so that the transcript isn't degraded by a rate mismatch.
+func roundTrip(ctx context.Context, client diathekeclient.Client,
+	text, lunaModel, cubicModel string, rates sampleRates) (roundTripResult, error) {
+	result := roundTripResult{Text: text}
+
+	if rates.tts == 0 || rates.asr == 0 {
+		return result, fmt.Errorf("model %q does not report its TTS and ASR sample rates (%d and %d)",
+			appCfg.Server.ModelID, rates.tts, rates.asr)
+	}
+
+	ttsStream, err := client.NewTTSStream(ctx, &diathekepb.ReplyAction{
+		Text:      text,
+		LunaModel: lunaModel,
+	})
+	if err != nil {
+		return result, fmt.Errorf("error creating TTS stream: %w", err)
+	}
+
+	var synthesized bytes.Buffer
+	if err := diatheke.WriteTTSAudio(ttsStream, &synthesized); err != nil {
+		return result, fmt.Errorf("error synthesizing text: %w", err)
+	}
+
+	if synthesized.Len() == 0 {
+		return result, fmt.Errorf("TTS returned no audio for %q", text)
+	}
+
+	audioIn, err := audio.NewResampler(&synthesized, int(rates.tts), int(rates.asr))
+	if err != nil {
+		return result, fmt.Errorf("error resampling synthesized audio: %w", err)
+	}
+
+	asrStream, err := client.NewTranscribeStream(ctx, &diathekepb.TranscribeAction{
+		Id:              "roundtrip",
+		CubicModelId:    cubicModel,
+		DiathekeModelId: appCfg.Server.ModelID,
+	})
+	if err != nil {
+		return result, fmt.Errorf("error creating transcribe stream: %w", err)
+	}
+
+	var transcript []string
+
+	handler := func(r *diathekepb.TranscribeResult) {
+		if !r.IsPartial {
+			transcript = append(transcript, r.Text)
+		}
+	}
+
+	if err := diatheke.ReadTranscribeAudio(asrStream, audioIn, defaultBuffSize, handler); err != nil {
+		return result, fmt.Errorf("error transcribing audio: %w", err)
+	}
+
+	result.Transcript = strings.Join(transcript, " ")
+	result.WER = wer.Rate(normalizeWords(text), normalizeWords(result.Transcript))
+
+	return result, nil
+}
+
+// normalizeWords splits text into lower case words, ignoring punctuation,
+// so that TTS input and ASR output can be compared.
+func normalizeWords(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+
+	return words
+}
+
+// loadConfig reads the specified config file at application startup.
+func loadConfig(filepath string) error {
+	var err error
+
+	appCfg, err = config.ReadConfigFile(filepath)
+	if err != nil {
+		return err
+	}
+
+	if appCfg.Server.ModelID == "" && appCfg.Server.ModelName == "" {
+		return fmt.Errorf("missing Diatheke ModelID or ModelName in the config file")
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/cobaltspeech/examples-go/diatheke/internal/diathekeclient"
+	"github.com/cobaltspeech/examples-go/pkg/wer"
+	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2/diathekepb"
+)
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	client := &diathekeclient.Fake{
+		TTSAudio: [][]byte{{1, 2}, {3, 4}},
+		TranscribeResults: []*diathekepb.TranscribeResult{
+			{Text: "turn on", IsPartial: true},
+			{Text: "turn on the"},
+			{Text: "lights"},
+		},
+	}
+
+	result, err := roundTrip(context.Background(), client, "Turn on the lamp.", "luna", "cubic", sampleRates{16000, 16000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Transcript != "turn on the lights" {
+		t.Errorf("incorrect transcript: %q", result.Transcript)
+	}
+
+	if math.Abs(result.WER-0.25) > 1e-9 {
+		t.Errorf("incorrect WER - expected: 0.25, actual: %v", result.WER)
+	}
+
+	if len(client.Replies) != 1 || client.Replies[0].Text != "Turn on the lamp." || client.Replies[0].LunaModel != "luna" {
+		t.Errorf("unexpected TTS requests: %v", client.Replies)
+	}
+
+	if len(client.Transcribes) != 1 || client.Transcribes[0].CubicModelId != "cubic" {
+		t.Errorf("unexpected transcribe requests: %v", client.Transcribes)
+	}
+
+	// The audio is sent as is when the sample rates match.
+	if !bytes.Equal(client.TranscribeAudio, []byte{1, 2, 3, 4}) {
+		t.Errorf("incorrect transcribed audio: %v", client.TranscribeAudio)
+	}
+}
+
+func TestRoundTripResample(t *testing.T) {
+	t.Parallel()
+
+	// 8 samples of 16-bit audio at 32 kHz, transcribed at 16 kHz.
+	synthesized := make([]byte, 16)
+	for i := 0; i < len(synthesized); i += 2 {
+		binary.LittleEndian.PutUint16(synthesized[i:], uint16(i*100))
+	}
+
+	client := &diathekeclient.Fake{
+		TTSAudio:          [][]byte{synthesized},
+		TranscribeResults: []*diathekepb.TranscribeResult{{Text: "hello"}},
+	}
+
+	if _, err := roundTrip(context.Background(), client, "hello", "", "", sampleRates{tts: 32000, asr: 16000}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []uint16{0, 400, 800, 1200}
+	if len(client.TranscribeAudio) != 2*len(expected) {
+		t.Fatalf("incorrect transcribed audio length - expected: %d, actual: %d", 2*len(expected), len(client.TranscribeAudio))
+	}
+
+	for i, sample := range expected {
+		if actual := binary.LittleEndian.Uint16(client.TranscribeAudio[2*i:]); actual != sample {
+			t.Errorf("incorrect sample %d - expected: %d, actual: %d", i, sample, actual)
+		}
+	}
+}
+
+func TestRoundTripErrors(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		name   string
+		client *diathekeclient.Fake
+	}{
+		{"no audio", &diathekeclient.Fake{}},
+		{"client error", &diathekeclient.Fake{Err: errors.New("unavailable")}},
+	}
+
+	for _, test := range testList {
+		if _, err := roundTrip(context.Background(), test.client, "hello", "", "", sampleRates{16000, 16000}); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+
+	// The rates must be known to compare them.
+	client := &diathekeclient.Fake{TTSAudio: [][]byte{{1, 2}}}
+	if _, err := roundTrip(context.Background(), client, "hello", "", "", sampleRates{tts: 22050}); err == nil {
+		t.Error("missing ASR sample rate: expected an error")
+	}
+}
+
+func TestNormalizedWordErrorRate(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		ref, hyp string
+		expected float64
+	}{
+		{"hello world", "Hello, world!", 0},
+		{"hello world", "hello", 0.5},
+		{"hello world", "hello big world", 0.5},
+		{"hello world", "goodbye moon", 1},
+		{"", "", 0},
+		{"", "hello", 1},
+	}
+
+	for _, test := range testList {
+		actual := wer.Rate(normalizeWords(test.ref), normalizeWords(test.hyp))
+		if math.Abs(actual-test.expected) > 1e-9 {
+			t.Errorf("wer.Rate(%q, %q) - expected: %v, actual: %v", test.ref, test.hyp, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/cobaltspeech/examples-go/pkg/audio"
+	"github.com/cobaltspeech/sdk-cubic/grpc/go-cubic"
+	"github.com/cobaltspeech/sdk-cubic/grpc/go-cubic/cubicpb"
+)
+
+// bargeInDetector runs the wake-word recognizer on the recorded audio
+// while a reply is played, to detect the user speaking over it.
+type bargeInDetector struct {
+	client      *cubic.Client
+	cfg         *cubicpb.RecognitionConfig
+	reader      *audio.StoppableReader
+	bytesPerSec int
+}
+
+// listen runs the recognizer until ctx is cancelled. The first time
+// speech is detected, onSpeech is called (e.g., to stop the playback).
+// It returns whether speech was detected, and if so the byte offset
+// of the start of the speech, relative to the audio read since listen
+// was called.
+func (d *bargeInDetector) listen(ctx context.Context, onSpeech func()) (bool, int) {
+	var (
+		once     sync.Once
+		detected bool
+		offset   int
+	)
+
+	// The offsets are relative to the audio read from here on.
+	d.reader.Reset()
+
+	handler := func(resp *cubicpb.RecognitionResponse) {
+		if off, ok := bargeInOffset(resp, d.bytesPerSec); ok {
+			once.Do(func() {
+				detected, offset = true, off
+				onSpeech()
+			})
+		}
+	}
+
+	// The recognizer returns an error once ctx is cancelled, which is
+	// how it normally stops.
+	err := d.client.StreamingRecognize(ctx, d.cfg, d.reader, handler)
+	if err != nil && ctx.Err() == nil {
+		logger.Error("msg", "barge-in detection stopped", "err", err)
+	}
+
+	return detected, offset
+}
+
+// bargeInOffset returns the byte offset of the start of the first
+// result with a transcript in the response, partial results included
+// so that the reply is interrupted as early as possible. Since the
+// recognizer uses the wake-word model, this is either the wake word
+// or any speech, depending on the model.
+func bargeInOffset(resp *cubicpb.RecognitionResponse, bytesPerSec int) (int, bool) {
+	for _, result := range resp.Results {
+		if len(result.Alternatives) == 0 || strings.TrimSpace(result.Alternatives[0].Transcript) == "" {
+			continue
+		}
+
+		// Keep the offset on a 16-bit sample boundary.
+		sec := durationSeconds(result.Alternatives[0].StartTime)
+		offset := int(math.Round(sec * float64(bytesPerSec)))
+
+		return offset - offset%2, true //nolint:gomnd // 2 bytes per sample
+	}
+
+	return 0, false
+}
@@ -16,14 +16,18 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"math"
-	"strings"
+	"os"
 
-	"github.com/cobaltspeech/examples-go/diatheke/internal/audio"
 	"github.com/cobaltspeech/examples-go/diatheke/internal/config"
+	"github.com/cobaltspeech/examples-go/diatheke/internal/diathekeclient"
+	"github.com/cobaltspeech/examples-go/diatheke/internal/interrupt"
+	"github.com/cobaltspeech/examples-go/diatheke/internal/logging"
+	"github.com/cobaltspeech/examples-go/pkg/audio"
 	"github.com/cobaltspeech/sdk-cubic/grpc/go-cubic"
 	"github.com/cobaltspeech/sdk-cubic/grpc/go-cubic/cubicpb"
 	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2"
@@ -32,28 +36,52 @@ import (
 
 const defaultBuffSize = 8192
 
+// Percentage of clipped samples in the recorded audio above which a
+// warning about the input level is printed.
+const clipWarningPercent = 1.0
+
 // Contains application settings as defined in the config file.
 var appCfg config.Config
 
+// Target level (dBFS) used to normalize recorded audio. Zero disables
+// normalization.
+var normalizeDBFS float64
+
+// Whether the user can interrupt replies by speaking over them.
+var bargeIn bool
+
+// Logs diagnostic messages to stderr. Set by main from the -verbose flag.
+var logger = logging.New(false)
+
 func main() {
 	// Read the config file
 	configFile := flag.String("config", "config.toml", "Path to the config file")
+	dumpConfig := flag.String("dump-config", "", "Print the effective configuration as toml or json and exit")
+	flag.Float64Var(&normalizeDBFS, "normalize", 0,
+		"Normalize recorded audio toward the given level in dBFS (e.g., -20). Zero disables normalization.")
+	flag.BoolVar(&bargeIn, "barge-in", false,
+		"Stop replies when speech is detected. Use headphones so the reply itself isn't detected.")
+	verbose := flag.Bool("verbose", false, "Log debug messages")
 
 	flag.Parse()
 
+	logger = logging.New(*verbose)
+
 	if err := loadConfig(*configFile); err != nil {
 		log.Fatalf("error reading config file: %v", err)
 	}
 
-	// Create the Wake-word cubicsvr client. This client is an ASR model that is only focused on identifying
-	// the wake word in a long running recognizer, and unblocking once the wake word is detected.
-	wwOpts := make([]cubic.Option, 0)
-	if appCfg.Server.Insecure {
-		// NOTE: Secure connections are recommended for production
-		wwOpts = append(wwOpts, cubic.WithInsecure())
+	if *dumpConfig != "" {
+		if err := config.Dump(os.Stdout, appCfg, *dumpConfig); err != nil {
+			log.Fatalf("error dumping config: %v", err)
+		}
+
+		return
 	}
 
-	wwClient, err := cubic.NewClient(appCfg.WakeWordServer.Address, wwOpts...)
+	// Create the Wake-word cubicsvr client. This client is an ASR model that is only focused on identifying
+	// the wake word in a long running recognizer, and unblocking once the wake word is detected.
+	wwClient, err := cubic.NewClient(appCfg.WakeWordServer.Address, appCfg.WakeWordServer.CubicOptions()...)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -80,37 +108,63 @@ func main() {
 		log.Fatalf("Recorder Error!!!!")
 	}
 
+	defer recorder.Stop()
+
 	// Wrap the recorder in a "StoppableReader" that will allow the reader's Read() method to return EOF on
 	// the first Read() after abortFunc is called (to force an exit from the wake word cubicsvr after the
 	// wake word is recognized), but later Read() calls will be successful.  This StoppableReader will also
 	// allow audio to be re-wound so when the Diatheke server reads from the same stream it can start reading
 	// right at the start of the wake word.
+	recordedAudio := audio.NewClipDetector(recorder.Output(), clipWarningPercent, warnClipping)
+	if normalizeDBFS != 0 {
+		// The normalizer does not change the length of the audio, so
+		// offsets into the StoppableReader are still valid.
+		recordedAudio = audio.NewNormalizer(recordedAudio, normalizeDBFS)
+	}
+
 	wwBufferSize := int(float32(sampleRateBytes) * appCfg.WakeWordServer.AudioBufferSec)
-	stoppableReader := audio.NewStoppableReader(recorder.Output(), wwBufferSize)
+	stoppableReader := audio.NewStoppableReader(recordedAudio, wwBufferSize)
 
 	// Create a new diatheke client
-	opts := make([]diatheke.Option, 0)
-	if appCfg.Server.Insecure {
-		// NOTE: Secure connections are recommended for production
-		opts = append(opts, diatheke.WithInsecure())
+	opts := appCfg.Server.DiathekeOptions()
+
+	// Session updates are retried, with a new connection, if the server
+	// becomes unavailable.
+	dial := func() (diathekeclient.Client, error) {
+		return diatheke.NewClient(appCfg.Server.Address, opts...)
 	}
 
-	diathekeClient, err := diatheke.NewClient(appCfg.Server.Address, opts...)
+	diathekeClient, err := diathekeclient.NewRetry(dial, appCfg.Server.RetryAttempts, diathekeclient.DefaultRetryDelay, logger)
 	if err != nil {
 		log.Fatalf("error creating diathekeClient: %v\n", err)
 	}
 
 	defer diathekeClient.Close()
 
-	if err := runDiatheke(cfg, wwClient, diathekeClient, stoppableReader, sampleRateBytes); err != nil {
+	// Ctrl-C ends the dialog, rather than the program, so that the
+	// session is deleted before exiting.
+	ctx, stop := interrupt.Context(context.Background())
+	defer stop()
+
+	if err := runDiatheke(ctx, cfg, wwClient, diathekeClient, stoppableReader, sampleRateBytes); err != nil {
 		log.Fatalf("error running Diatheke client: %v\n", err)
 	}
 }
 
+// runDiatheke runs a dialog until it ends or ctx is cancelled, then
+// deletes the session.
+//
+// Cancelling ctx cancels the wake word, ASR and TTS streams. The player
+// is stopped as it is at the end of a reply, killing the external
+// application if it is still running, while the recorder keeps running
+// until main returns, since it is shared by all the inputs. When the
+// user presses Ctrl-C in a terminal, the applications receive the
+// interrupt as well and usually exit first.
 func runDiatheke(
+	ctx context.Context,
 	cfg *cubicpb.RecognitionConfig,
 	wwClient *cubic.Client,
-	diathekeClient *diatheke.Client,
+	diathekeClient diathekeclient.Client,
 	stoppableReader *audio.StoppableReader,
 	sampleRateBytes uint32,
 ) error {
@@ -122,11 +176,11 @@ func runDiatheke(
 		return fmt.Errorf("error getting server version: %w\n", err)
 	}
 
-	log.Printf("Server Versions\n")
-	log.Printf("  Diatheke: %v\n", ver.Diatheke)
-	log.Printf("  Chosun (NLU): %v\n", ver.Chosun)
-	log.Printf("  Cubic (ASR): %v\n", ver.Cubic)
-	log.Printf("  Luna (TTS): %v\n", ver.Luna)
+	fmt.Printf("Server Versions\n")
+	fmt.Printf("  Diatheke: %v\n", ver.Diatheke)
+	fmt.Printf("  Chosun (NLU): %v\n", ver.Chosun)
+	fmt.Printf("  Cubic (ASR): %v\n", ver.Cubic)
+	fmt.Printf("  Luna (TTS): %v\n", ver.Luna)
 
 	// Print the list of available models
 	modelList, err := diathekeClient.ListModels(bctx)
@@ -134,14 +188,27 @@ func runDiatheke(
 		return fmt.Errorf("error getting model list: %w\n", err)
 	}
 
-	log.Printf("Available Models:\n")
+	fmt.Printf("Available Models:\n")
 
 	for _, mdl := range modelList.Models {
-		log.Printf("  ID: %v\n", mdl.Id)
-		log.Printf("    Name: %v\n", mdl.Name)
-		log.Printf("    Language: %v\n", mdl.Language)
-		log.Printf("    ASR Sample Rate: %v\n", mdl.AsrSampleRate)
-		log.Printf("    TTS Sample Rate: %v\n\n", mdl.TtsSampleRate)
+		fmt.Printf("  ID: %v\n", mdl.Id)
+		fmt.Printf("    Name: %v\n", mdl.Name)
+		fmt.Printf("    Language: %v\n", mdl.Language)
+		fmt.Printf("    ASR Sample Rate: %v\n", mdl.AsrSampleRate)
+		fmt.Printf("    TTS Sample Rate: %v\n\n", mdl.TtsSampleRate)
+	}
+
+	// Select the model by name, if the config file doesn't give its ID.
+	if err := appCfg.Server.ResolveModelID(modelList.Models); err != nil {
+		return err
+	}
+
+	// Warn about audio settings that don't match the selected model.
+	// A missing model is reported by CreateSession.
+	if asrRate, ttsRate, err := appCfg.SampleRates(modelList.Models); err == nil {
+		for _, warning := range appCfg.CheckSampleRates(asrRate, ttsRate) {
+			fmt.Printf("Warning: %s\n\n", warning)
+		}
 	}
 
 	// Create a session using the model specified in the config file.
@@ -153,21 +220,28 @@ func runDiatheke(
 	// Begin processing actions
 	for {
 		// Run diatheke
-		session, err = processActions(wwClient, cfg, appCfg.WakeWordServer.WakePhrases,
+		next, err := processActions(ctx, wwClient, cfg, appCfg.WakeWordServer.WakePhrases,
 			appCfg.WakeWordServer.MinWakePhraseConfidence, int(sampleRateBytes),
 			diathekeClient, session, stoppableReader)
-		if err != nil {
-			fmt.Printf("error processing actions: %v\n", err)
+		if ctx.Err() != nil {
+			logger.Info("msg", "dialog interrupted")
+
+			break
+		} else if err != nil {
+			logger.Error("msg", "error processing actions", "err", err)
 
 			break
-		} else if session == nil {
-			fmt.Printf("got nil session back")
+		} else if next == nil {
+			logger.Info("msg", "dialog ended")
 
 			break
 		}
+
+		session = next
 	}
 
-	// Clean up the session
+	// Clean up the session. This uses its own context, since ctx
+	// may have been cancelled.
 	if err = diathekeClient.DeleteSession(bctx, session.Token); err != nil {
 		return fmt.Errorf("error deleting session: %w\n", err)
 	}
@@ -175,39 +249,70 @@ func runDiatheke(
 	return nil
 }
 
+// errNoActions is returned by processActions for an action list
+// without any action to execute.
+var errNoActions = errors.New("received an action list without any actions")
+
 // processActions executes the actions for the given session
-// and returns an updated session.
-func processActions(wwClient *cubic.Client, wwCfg *cubicpb.RecognitionConfig,
+// and returns an updated session. If the action list ends without
+// waiting for user input or executing a command, no further input
+// is expected and the dialog has ended, in which case a nil session
+// is returned without an error. An action list without any action,
+// which would otherwise silently end the dialog, is an error.
+func processActions(ctx context.Context, wwClient *cubic.Client, wwCfg *cubicpb.RecognitionConfig,
 	wwPhrases []string, wwMinConf float64, wwBytesPerSec int,
-	diathekeClient *diatheke.Client, session *diathekepb.SessionOutput,
+	diathekeClient diathekeclient.Client, session *diathekepb.SessionOutput,
 	reader *audio.StoppableReader) (*diathekepb.SessionOutput, error) {
+	var detector *bargeInDetector
+	if bargeIn {
+		detector = &bargeInDetector{client: wwClient, cfg: wwCfg, reader: reader, bytesPerSec: wwBytesPerSec}
+	}
+
+	// Set once the user interrupts a reply, after which the remaining
+	// replies are skipped.
+	interrupted := false
+
+	// Set once a reply or transcribe action is executed.
+	handled := false
+
 	// Iterate through each action in the list and determine its type.
 	for _, action := range session.ActionList {
+		logger.Debug("msg", "processing action", "type", fmt.Sprintf("%T", action.Action))
+
 		if inputAction := action.GetInput(); inputAction != nil {
 			// The WaitForUserAction will involve a session update.
-			log.Println(".....wait for input")
-
-			return waitForInput(reader, wwClient, wwCfg, wwPhrases, wwMinConf,
+			return waitForInput(ctx, reader, wwClient, wwCfg, wwPhrases, wwMinConf,
 				wwBytesPerSec, diathekeClient, session, inputAction)
 		} else if reply := action.GetReply(); reply != nil {
 			// Replies do not require a session update.
-			log.Println(".....GetReply")
+			handled = true
 
-			err := handleReply(diathekeClient, reply)
+			if interrupted {
+				logger.Debug("msg", "skipping reply", "text", reply.Text)
+				continue
+			}
+
+			var err error
+
+			interrupted, err = handleReply(ctx, diathekeClient, reply, detector)
 			if err != nil {
 				return nil, err
 			}
 		} else if cmd := action.GetCommand(); cmd != nil {
 			// The CommandAction will involve a session update.
-			log.Println(".....GetCommand")
-
-			return handleCommand(diathekeClient, session, cmd)
+			return handleCommand(ctx, diathekeClient, session, cmd)
 		} else if action.Action != nil {
 			return nil, fmt.Errorf("received unknown action type %T", action.Action)
 		}
 	}
 
-	return nil, fmt.Errorf("action list ended without session update")
+	if !handled {
+		return nil, errNoActions
+	}
+
+	// The action list did not require a session update, which means
+	// the dialog has ended.
+	return nil, nil
 }
 
 // waitForInput creates an ASR stream and records audio from the user.
@@ -216,13 +321,14 @@ func processActions(wwClient *cubic.Client, wwCfg *cubicpb.RecognitionConfig,
 //
 //nolint:funlen // no embedded sub functions for easier maintenance
 func waitForInput(
+	ctx context.Context,
 	reader *audio.StoppableReader,
 	wwClient *cubic.Client,
 	wwCfg *cubicpb.RecognitionConfig,
 	wwPhrases []string,
 	wwMinConf float64,
 	wwBytesPerSec int,
-	diathekeClient *diatheke.Client,
+	diathekeClient diathekeclient.Client,
 	session *diathekepb.SessionOutput,
 	inputAction *diathekepb.WaitForUserAction,
 ) (*diathekepb.SessionOutput, error) {
@@ -234,7 +340,7 @@ func waitForInput(
 		// reply immediately. If this flag is false, the app may wait
 		// as long as it wants before processing user input (such as
 		// waiting for a wake-word below).
-		log.Printf("(Immediate input required) ")
+		logger.Debug("msg", "immediate input required")
 	}
 
 	// Wait for a wake word if one is required.
@@ -244,60 +350,62 @@ func waitForInput(
 		// This action requires the wake-word to be spoken before
 		// any other audio will be processed. Use a wake-word detector
 		// and wait for it to trigger.
-		log.Printf("(Wakeword required) ")
+		logger.Debug("msg", "wake word required")
 
-		// Define a callback function to check if the wake word was present in the endpointed audio.
-		// This example expects the wake phrase only be one token in length (but multi-word wake
-		// phrasess could be supported with this handler if the wake word model used a multi-word
-		// token for the wake phrase)
-		var wakeWordStartTimeSec float64
+		// Define a callback function to check if a wake phrase was present in
+		// the endpointed audio. Phrases may span several words of the result.
+		var (
+			wakeWordFound        bool
+			wakeWordStartTimeSec float64
+		)
 
 		resultHandler := func(resp *cubicpb.RecognitionResponse) {
 			for _, result := range resp.Results {
-				if result.IsPartial {
+				// Stop the reader only once, since a second Stop() would
+				// also end the next stream reading from it.
+				if wakeWordFound || len(result.Alternatives) == 0 {
 					continue
 				}
 
-				transcript := result.Alternatives[0].Transcript
-				confidence := result.Alternatives[0].Confidence
+				if result.IsPartial {
+					// Keep the audio of this utterance, which may contain the wake
+					// word, buffered until its final result arrives, however long
+					// the utterance is.
+					start := result.Alternatives[0].StartTime
+					startSec := float64(start.GetSeconds()) +
+						float64(start.GetNanos())/1000000000.0 //nolint: gomnd // nano second is not a magic number
+					reader.ProtectOffset(int(startSec * float64(wwBytesPerSec)))
 
-				for _, wakePhrase := range wwPhrases {
-					if strings.HasSuffix(transcript, wakePhrase) && confidence >= wwMinConf {
-						// Find the index of the first word in the wake phrase in the alternatives list.
-						wakePhraseFirstWord := strings.Split(wakePhrase, " ")[0]
-						wakePhraseStartIndex := -1
+					continue
+				}
 
-						for i, wordInfo := range result.Alternatives[0].Words {
-							if wakePhraseFirstWord == wordInfo.Word {
-								wakePhraseStartIndex = i
+				m, found := findWakePhrase(result.Alternatives[0].Words, wwPhrases, wwMinConf)
+				if !found {
+					// No wake word in this utterance, so its audio is not needed.
+					reader.UnprotectOffset()
 
-								break
-							}
-						}
+					continue
+				}
 
-						wakeWordStartTimeSec = float64(result.Alternatives[wakePhraseStartIndex].StartTime.Seconds) +
-							float64(result.Alternatives[wakePhraseStartIndex].StartTime.Nanos)/1000000000.0 //nolint: gomnd // nano second is not a magic number
+				logger.Info("msg", "wake phrase detected", "phrase", m.phrase, "confidence", m.confidence)
 
-						reader.Stop()
+				wakeWordFound = true
+				wakeWordStartTimeSec = m.startSec
 
-						break
-					}
-				}
+				reader.Stop()
 			}
 		}
 
 		// Run the wake-word recognizer, this will block until a wake word is found.
 		// The time that the wake word stars will be set in "wakeWordStartTimeSec".
 		// The start time of the wake word will be set in "wakeWordStartTimeSec"
-		log.Println("Waiting for wake word...")
+		fmt.Printf("Waiting for wake word...\n")
 
-		err := wwClient.StreamingRecognize(context.Background(), wwCfg, reader, resultHandler)
+		err := wwClient.StreamingRecognize(ctx, wwCfg, reader, resultHandler)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
 
-		log.Println("Wake word found")
-
 		// Rewind the recorder to the start of the wake word.
 		// The start of the rewound stream is now considered to be time=0.0
 		if err = reader.Rewind(int(math.Round(wakeWordStartTimeSec*float64(wwBytesPerSec))), true); err != nil {
@@ -306,12 +414,12 @@ func waitForInput(
 	}
 
 	// Create an ASR stream
-	stream, err := diathekeClient.NewSessionASRStream(context.Background(), session.Token)
+	stream, err := diathekeClient.NewSessionASRStream(ctx, session.Token)
 	if err != nil {
 		return nil, err
 	}
 
-	log.Printf("Recording...\n")
+	fmt.Printf("Recording...\n")
 
 	// Record until we get a result
 	result, err := diatheke.ReadASRAudio(stream, reader, defaultBuffSize)
@@ -319,61 +427,110 @@ func waitForInput(
 		return nil, err
 	}
 
-	log.Printf("  ASRResult: %v\n\n", result)
+	logger.Info("msg", "ASR result", "text", result.Text, "confidence", result.Confidence)
 
 	// Reset the historical buffer in the reader since it is no longer needed.
-	// Also, reading from a StoppableBuffer that has been Rewound() twice without a
-	// Reset() call between the rewinds is not supported.
 	reader.Reset()
 
 	// Update the session with the result
-	return diathekeClient.ProcessASRResult(context.Background(), session.Token, result)
+	return diathekeClient.ProcessASRResult(ctx, session.Token, result)
+}
+
+// warnClipping prints a warning that the recorded audio is clipped.
+func warnClipping(percent float64) {
+	fmt.Printf("\nWarning: %.1f%% of the recorded audio is clipped, "+
+		"lower the input level for better accuracy.\n", percent)
 }
 
-// handleReply uses TTS to play back the reply as speech.
-func handleReply(client *diatheke.Client, reply *diathekepb.ReplyAction) error {
-	log.Printf("  TTS Reply: %v\n\n", reply)
+// handleReply uses TTS to play back the reply as speech. If the
+// detector is not nil, it listens for speech during the playback, in
+// which case the playback is stopped and the reader is rewound to the
+// start of the speech, so that it is processed as the next input.
+// Returns whether the reply was interrupted this way.
+func handleReply(ctx context.Context, client diathekeclient.Client, reply *diathekepb.ReplyAction,
+	detector *bargeInDetector) (bool, error) {
+	logger.Info("msg", "reply", "text", reply.Text)
+
+	// Cancelling playCtx stops both the TTS stream and the player.
+	playCtx, stopPlayback := context.WithCancel(ctx)
+	defer stopPlayback()
 
 	// Create the TTS stream
-	stream, err := client.NewTTSStream(context.Background(), reply)
+	stream, err := client.NewTTSStream(playCtx, reply)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// Create something to handle audio playback
 	player := audio.NewPlayer(appCfg.Playback)
 
 	// Start the player
-	if err = player.Start(); err != nil {
-		return err
+	if err = player.StartContext(playCtx); err != nil {
+		return false, err
+	}
+
+	// Listen for speech until the playback is complete.
+	type bargeInResult struct {
+		detected bool
+		offset   int
+	}
+
+	listenCtx, stopListening := context.WithCancel(ctx)
+	defer stopListening()
+
+	results := make(chan bargeInResult, 1)
+
+	if detector != nil {
+		go func() {
+			detected, offset := detector.listen(listenCtx, stopPlayback)
+			results <- bargeInResult{detected, offset}
+		}()
+	} else {
+		results <- bargeInResult{}
+	}
+
+	// Play the reply, which fails if it is interrupted.
+	writeErr := diatheke.WriteTTSAudio(stream, player.Input())
+
+	// Stop the player, which waits for the end of the playback
+	stopErr := player.Stop()
+
+	stopListening()
+
+	if res := <-results; res.detected {
+		logger.Info("msg", "reply interrupted")
+
+		// The next input starts with the speech that interrupted the reply.
+		if err := detector.reader.Rewind(res.offset, true); err != nil {
+			logger.Error("msg", "could not rewind to the start of the speech", "err", err)
+		}
+
+		return true, nil
 	}
 
-	// Play the entire reply uninterrupted
-	if err = diatheke.WriteTTSAudio(stream, player.Input()); err != nil {
-		log.Println("Error writing audio to TTS (skipping TTS)")
-		return nil
+	if writeErr != nil {
+		logger.Error("msg", "error writing audio to TTS, skipping the reply", "err", writeErr)
+		return false, nil
 	}
 
-	// Stop the player
-	return player.Stop()
+	return false, stopErr
 }
 
 // handleCommand executes the specified command.
 func handleCommand(
-	client *diatheke.Client,
+	ctx context.Context,
+	client diathekeclient.Client,
 	session *diathekepb.SessionOutput,
 	cmd *diathekepb.CommandAction,
 ) (*diathekepb.SessionOutput, error) {
-	log.Printf("  Command:\n")
-	log.Printf("    ID: %v\n", cmd.Id)
-	log.Printf("    Input params: %v\n\n", cmd.InputParameters)
+	logger.Info("msg", "command", "id", cmd.Id, "inputParams", cmd.InputParameters)
 
 	// Update the session with the command result
 	result := diathekepb.CommandResult{
 		Id: cmd.Id,
 	}
 
-	session, err := client.ProcessCommandResult(context.Background(), session.Token, &result)
+	session, err := client.ProcessCommandResult(ctx, session.Token, &result)
 	if err != nil {
 		err = fmt.Errorf("ProcessCommandResult error: %w", err)
 	}
@@ -392,16 +549,16 @@ func loadConfig(filepath string) error {
 
 	// Verify that we have the required fields for this demo.
 	// The following are required for this demo
-	if appCfg.Server.ModelID == "" {
-		return fmt.Errorf("missing Diatheke ModelID in the config file")
+	if appCfg.Server.ModelID == "" && appCfg.Server.ModelName == "" {
+		return fmt.Errorf("missing Diatheke ModelID or ModelName in the config file")
 	}
 
 	if appCfg.Playback.Application == "" {
 		return fmt.Errorf("missing Playback application in the config file")
 	}
 
-	if appCfg.Recording.Application == "" {
-		return fmt.Errorf("missing Recording application in the config file")
+	if appCfg.Recording.Application == "" && appCfg.Recording.SourceFile == "" {
+		return fmt.Errorf("missing Recording application or source file in the config file")
 	}
 
 	return nil
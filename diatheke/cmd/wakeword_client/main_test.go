@@ -0,0 +1,152 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/cobaltspeech/sdk-cubic/grpc/go-cubic/cubicpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestBargeInOffset(t *testing.T) {
+	t.Parallel()
+
+	const bytesPerSec = 16000
+
+	resp := func(transcript string, start *durationpb.Duration) *cubicpb.RecognitionResponse {
+		return &cubicpb.RecognitionResponse{
+			Results: []*cubicpb.RecognitionResult{{
+				IsPartial:    true,
+				Alternatives: []*cubicpb.RecognitionAlternative{{Transcript: transcript, StartTime: start}},
+			}},
+		}
+	}
+
+	testList := []struct {
+		name     string
+		resp     *cubicpb.RecognitionResponse
+		expected int
+		detected bool
+	}{
+		{"no results", &cubicpb.RecognitionResponse{}, 0, false},
+		{"empty transcript", resp(" ", durationpb.New(0)), 0, false},
+		{"speech", resp("OKCOBALT", &durationpb.Duration{Seconds: 1, Nanos: 500000000}), 24000, true},
+		{"odd offset", resp("stop", &durationpb.Duration{Nanos: 62500}), 0, true},
+		{"no start time", resp("stop", nil), 0, true},
+	}
+
+	for _, test := range testList {
+		offset, detected := bargeInOffset(test.resp, bytesPerSec)
+		if offset != test.expected || detected != test.detected {
+			t.Errorf("%s - expected: (%d, %t), actual: (%d, %t)", test.name, test.expected, test.detected, offset, detected)
+		}
+	}
+}
+
+// testWords returns word infos for the given words, with the given
+// confidences, starting one second apart.
+func testWords(words []string, confidences ...float64) []*cubicpb.WordInfo {
+	infos := make([]*cubicpb.WordInfo, len(words))
+	for i, w := range words {
+		infos[i] = &cubicpb.WordInfo{
+			Word:       w,
+			Confidence: confidences[i],
+			StartTime:  &durationpb.Duration{Seconds: int64(i), Nanos: 250000000},
+		}
+	}
+
+	return infos
+}
+
+func TestFindWakePhrase(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		name     string
+		words    []*cubicpb.WordInfo
+		phrases  []string
+		expected wakeMatch
+		found    bool
+	}{
+		{
+			"single token",
+			testWords([]string{"hey", "OKCOBALT"}, 0.5, 0.97),
+			[]string{"OKCOBALT"},
+			wakeMatch{"OKCOBALT", 1.25, 0.97}, true,
+		},
+		{
+			"multiple words",
+			testWords([]string{"um", "ok", "cobalt"}, 0.2, 0.96, 0.98),
+			[]string{"ok cobalt"},
+			wakeMatch{"ok cobalt", 1.25, 0.97}, true,
+		},
+		{
+			"casing and punctuation",
+			testWords([]string{"Okay,", "Cobalt!", "lights"}, 1, 0.9, 0.99),
+			[]string{"OKAY COBALT"},
+			wakeMatch{"OKAY COBALT", 0.25, 0.95}, true,
+		},
+		{
+			"words spell a single token phrase",
+			testWords([]string{"ok", "-", "cobalt"}, 0.96, 0, 0.96),
+			[]string{"OKCOBALT"},
+			wakeMatch{"OKCOBALT", 0.25, 0.64}, true,
+		},
+		{
+			"last occurrence",
+			testWords([]string{"ok", "cobalt", "ok", "cobalt"}, 0.99, 0.99, 0.97, 0.97),
+			[]string{"ok cobalt"},
+			wakeMatch{"ok cobalt", 2.25, 0.97}, true,
+		},
+		{
+			"earlier occurrence above the minimum",
+			testWords([]string{"ok", "cobalt", "ok", "cobalt"}, 0.99, 0.99, 0.5, 0.5),
+			[]string{"ok cobalt"},
+			wakeMatch{"ok cobalt", 0.25, 0.99}, true,
+		},
+		{
+			"second phrase",
+			testWords([]string{"ok", "google"}, 0.99, 0.99),
+			[]string{"OKCOBALT", "OKGOOGLE"},
+			wakeMatch{"OKGOOGLE", 0.25, 0.99}, true,
+		},
+		{
+			"partial word",
+			testWords([]string{"broken", "cobalt"}, 0.99, 0.99),
+			[]string{"ok cobalt"},
+			wakeMatch{}, false,
+		},
+		{
+			"low confidence",
+			testWords([]string{"ok", "cobalt"}, 0.99, 0.2),
+			[]string{"ok cobalt"},
+			wakeMatch{}, false,
+		},
+		{
+			"no words",
+			nil,
+			[]string{"OKCOBALT"},
+			wakeMatch{}, false,
+		},
+	}
+
+	for _, test := range testList {
+		m, found := findWakePhrase(test.words, test.phrases, 0.6)
+		if found != test.found || m != test.expected {
+			t.Errorf("%s - expected: (%+v, %t), actual: (%+v, %t)", test.name, test.expected, test.found, m, found)
+		}
+	}
+}
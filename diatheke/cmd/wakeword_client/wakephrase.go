@@ -0,0 +1,123 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/cobaltspeech/sdk-cubic/grpc/go-cubic/cubicpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// wakeMatch is a wake phrase found in the words of a result.
+type wakeMatch struct {
+	phrase     string
+	startSec   float64 // start time of the first word of the phrase
+	confidence float64 // average confidence of the words of the phrase
+}
+
+// findWakePhrase looks for the given wake phrases in the words, in
+// order, and returns the first one found with a confidence of at least
+// minConf. A phrase matches a contiguous sequence of words when they
+// spell the same letters and digits, so casing, punctuation and spacing
+// don't matter (e.g., "OKCOBALT" matches the words "ok" and "Cobalt,").
+// If a phrase is found more than once, the last occurrence is returned,
+// since the wake phrase is usually at the end of the result.
+func findWakePhrase(words []*cubicpb.WordInfo, phrases []string, minConf float64) (wakeMatch, bool) {
+	normalized := make([]string, len(words))
+	for i, w := range words {
+		normalized[i] = normalizeWakeText(w.Word)
+	}
+
+	for _, phrase := range phrases {
+		target := normalizeWakeText(phrase)
+		if target == "" {
+			continue
+		}
+
+		for end := len(words) - 1; end >= 0; end-- {
+			start, ok := matchWakeWords(normalized[:end+1], target)
+			if !ok {
+				continue
+			}
+
+			m := wakeMatch{
+				phrase:     phrase,
+				startSec:   durationSeconds(words[start].StartTime),
+				confidence: averageConfidence(words[start : end+1]),
+			}
+
+			if m.confidence >= minConf {
+				return m, true
+			}
+		}
+	}
+
+	return wakeMatch{}, false
+}
+
+// matchWakeWords returns the index of the first of the words ending
+// the list that together spell target.
+func matchWakeWords(words []string, target string) (int, bool) {
+	if words[len(words)-1] == "" {
+		return 0, false
+	}
+
+	rest := target
+
+	for i := len(words) - 1; i >= 0; i-- {
+		if !strings.HasSuffix(rest, words[i]) {
+			return 0, false
+		}
+
+		rest = strings.TrimSuffix(rest, words[i])
+		if rest == "" && words[i] != "" {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// normalizeWakeText returns the letters and digits of s, in lower case.
+func normalizeWakeText(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+
+		return -1
+	}, s)
+}
+
+// averageConfidence returns the average confidence of the words.
+func averageConfidence(words []*cubicpb.WordInfo) float64 {
+	var sum float64
+	for _, w := range words {
+		sum += w.Confidence
+	}
+
+	return sum / float64(len(words))
+}
+
+// durationSeconds returns the duration in seconds, or zero if it is nil.
+func durationSeconds(d *durationpb.Duration) float64 {
+	if d == nil {
+		return 0
+	}
+
+	return float64(d.Seconds) + float64(d.Nanos)/1e9 //nolint:gomnd // nanoseconds per second
+}
@@ -4,8 +4,14 @@ go 1.14
 
 require (
 	github.com/BurntSushi/toml v0.3.1
+	github.com/cobaltspeech/examples-go/pkg v0.0.0
+	github.com/cobaltspeech/log v0.1.11
 	github.com/cobaltspeech/sdk-cubic/grpc/go-cubic v1.6.0
 	github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2 v2.1.0
 	golang.org/x/net v0.16.0 // indirect
 	google.golang.org/genproto v0.0.0-20210909211513-a8c4777a87af // indirect
+	google.golang.org/grpc v1.40.0
+	google.golang.org/protobuf v1.27.1
 )
+
+replace github.com/cobaltspeech/examples-go/pkg => ../pkg
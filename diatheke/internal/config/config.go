@@ -16,10 +16,9 @@ package config
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
 
-	"github.com/cobaltspeech/examples-go/diatheke/internal/audio"
+	"github.com/cobaltspeech/examples-go/pkg/audio"
+	"github.com/cobaltspeech/examples-go/pkg/envconfig"
 
 	"github.com/BurntSushi/toml"
 )
@@ -29,8 +28,26 @@ type ServerConfig struct {
 	Address  string
 	Insecure bool
 	ModelID  string
+
+	// ModelName selects the model by name instead of by ID. It is
+	// resolved with ResolveModelID once the models are listed, and is
+	// ignored if ModelID is set.
+	ModelName string
+
+	// RetryAttempts is the number of attempts made for each session
+	// update when the server is unavailable. Zero means the default.
+	RetryAttempts int
+
+	// CACertPath is the path to a PEM encoded CA certificate used to
+	// verify the server's TLS certificate instead of the system CAs.
+	CACertPath string
+
+	caCert []byte
 }
 
+// defaultRetryAttempts is used when ServerConfig.RetryAttempts is not set.
+const defaultRetryAttempts = 3
+
 type WakeWordServerConfig struct {
 	Address                 string
 	Insecure                bool
@@ -38,6 +55,12 @@ type WakeWordServerConfig struct {
 	AudioBufferSec          float32
 	WakePhrases             []string
 	MinWakePhraseConfidence float64
+
+	// CACertPath is the path to a PEM encoded CA certificate used to
+	// verify the server's TLS certificate instead of the system CAs.
+	CACertPath string
+
+	caCert []byte
 }
 
 // Config contains the application configuration
@@ -57,39 +80,49 @@ func ReadConfigFile(filename string) (Config, error) {
 		return config, err
 	}
 
+	if err := applyEnvOverrides(&config.Server); err != nil {
+		return config, fmt.Errorf("server config error - %w", err)
+	}
+
 	if config.Server.Address == "" {
 		return config, fmt.Errorf("missing server address")
 	}
 
-	// If the recording or playback fields are set, check them.
-	if config.Recording.Application != "" {
-		if err := checkAudioConfig(config.Recording.Application); err != nil {
-			return config, fmt.Errorf("recording config error - %w", err)
-		}
+	switch {
+	case config.Server.RetryAttempts < 0:
+		return config, fmt.Errorf("invalid server RetryAttempts %d", config.Server.RetryAttempts)
+	case config.Server.RetryAttempts == 0:
+		config.Server.RetryAttempts = defaultRetryAttempts
 	}
 
-	if config.Playback.Application != "" {
-		if err := checkAudioConfig(config.Playback.Application); err != nil {
-			return config, fmt.Errorf("playback config error - %w", err)
-		}
+	if config.Server.caCert, err = loadCACert(config.Server.CACertPath, config.Server.Insecure); err != nil {
+		return config, fmt.Errorf("server config error - %w", err)
 	}
 
-	return config, nil
-}
-
-func checkAudioConfig(app string) error {
-	// Verify that the file (executable) exists
-	info, err := os.Stat(app)
+	config.WakeWordServer.caCert, err = loadCACert(config.WakeWordServer.CACertPath, config.WakeWordServer.Insecure)
 	if err != nil {
-		// This is a path error, which means we couldn't find the file.
-		// Check the system path to see if we can find it there.
-		_, err = exec.LookPath(app)
-		if err != nil {
-			return fmt.Errorf("could not find application %s", app)
-		}
-	} else if info.IsDir() {
-		return fmt.Errorf("application is a directory, not an executable")
+		return config, fmt.Errorf("wake word server config error - %w", err)
 	}
 
-	return nil
+	if err := config.Recording.Validate(); err != nil {
+		return config, fmt.Errorf("recording config error - %w", err)
+	}
+
+	if err := config.Playback.Validate(); err != nil {
+		return config, fmt.Errorf("playback config error - %w", err)
+	}
+
+	return config, nil
+}
+
+// applyEnvOverrides replaces the Diatheke server settings with the
+// COBALT_SERVER_ADDRESS, COBALT_MODEL_ID and COBALT_INSECURE environment
+// variables, if they are set. The environment takes precedence over the
+// config file.
+func applyEnvOverrides(cfg *ServerConfig) error {
+	return envconfig.Server{
+		Address:  &cfg.Address,
+		ModelID:  &cfg.ModelID,
+		Insecure: &cfg.Insecure,
+	}.Apply()
 }
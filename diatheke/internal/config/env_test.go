@@ -0,0 +1,42 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/cobaltspeech/examples-go/pkg/envconfig"
+)
+
+func TestApplyEnvOverrides(t *testing.T) {
+	t.Setenv(envconfig.ServerAddress, "diatheke.example.com:443")
+	t.Setenv(envconfig.ModelID, "2")
+	t.Setenv(envconfig.Insecure, "false")
+
+	cfg := ServerConfig{Address: "localhost:9002", ModelID: "1", Insecure: true}
+	if err := applyEnvOverrides(&cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Address != "diatheke.example.com:443" || cfg.ModelID != "2" || cfg.Insecure {
+		t.Errorf("settings not overridden: %+v", cfg)
+	}
+
+	t.Setenv(envconfig.Insecure, "maybe")
+
+	if err := applyEnvOverrides(&cfg); err == nil {
+		t.Error("expected an error for an invalid COBALT_INSECURE value")
+	}
+}
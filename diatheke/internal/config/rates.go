@@ -0,0 +1,118 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cobaltspeech/examples-go/pkg/audio"
+	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2/diathekepb"
+)
+
+// SampleRates returns the ASR and TTS sample rates of the model
+// selected with Server.ModelID, found in the given list of models.
+func (c Config) SampleRates(models []*diathekepb.ModelInfo) (asrRate, ttsRate uint32, err error) {
+	for _, mdl := range models {
+		if mdl.Id == c.Server.ModelID {
+			return mdl.AsrSampleRate, mdl.TtsSampleRate, nil
+		}
+	}
+
+	return 0, 0, fmt.Errorf("model %q not found", c.Server.ModelID)
+}
+
+// CheckSampleRates compares the sample rates set in the recording and
+// playback args with the given model rates, and returns a warning for
+// each one that is missing or different. Audio recorded or played at
+// the wrong rate doesn't fail, but gives gibberish transcripts or
+// distorted speech. A zero rate means the model doesn't use that
+// audio, so it isn't checked.
+func (c Config) CheckSampleRates(asrRate, ttsRate uint32) []string {
+	var warnings []string
+
+	// The args are not used when streaming audio from a file.
+	if asrRate > 0 && c.Recording.Application != "" && c.Recording.SourceFile == "" {
+		if w := checkArgRate("recording", c.Recording, asrRate); w != "" {
+			warnings = append(warnings, w)
+		}
+
+		if c.Recording.CapturePath != "" && c.Recording.SampleRate > 0 &&
+			uint32(c.Recording.SampleRate) != asrRate {
+			warnings = append(warnings, fmt.Sprintf(
+				"the recording SampleRate is %d Hz, but the model's ASR sample rate is %d Hz",
+				c.Recording.SampleRate, asrRate))
+		}
+	}
+
+	if ttsRate > 0 && c.Playback.Application != "" {
+		if w := checkArgRate("playback", c.Playback, ttsRate); w != "" {
+			warnings = append(warnings, w)
+		}
+	}
+
+	return warnings
+}
+
+// checkArgRate returns a warning if the args of the audio config don't
+// set the given sample rate, or an empty string if they do.
+func checkArgRate(name string, cfg audio.Config, rate uint32) string {
+	argRate, found := argSampleRate(cfg.ArgList())
+
+	switch {
+	case !found:
+		return fmt.Sprintf("the %s args do not set a sample rate (e.g., -r %d), "+
+			"make sure %s uses the model's rate of %d Hz", name, rate, cfg.Application, rate)
+
+	case argRate != rate:
+		return fmt.Sprintf("the %s args set a sample rate of %d Hz, but the model expects %d Hz",
+			name, argRate, rate)
+	}
+
+	return ""
+}
+
+// argSampleRate returns the sample rate set with the first "-r N",
+// "--rate N" or "--rate=N" option in args, as used by sox, arecord and
+// aplay. Like sox, it accepts a "k" suffix for thousands (e.g., "16k").
+func argSampleRate(args []string) (uint32, bool) {
+	for i, arg := range args {
+		var val string
+
+		switch {
+		case (arg == "-r" || arg == "--rate") && i+1 < len(args):
+			val = args[i+1]
+		case strings.HasPrefix(arg, "--rate="):
+			val = strings.TrimPrefix(arg, "--rate=")
+		default:
+			continue
+		}
+
+		mult := 1.0
+		if trimmed := strings.TrimSuffix(strings.ToLower(val), "k"); trimmed != strings.ToLower(val) {
+			val, mult = trimmed, 1000
+		}
+
+		f, err := strconv.ParseFloat(val, 64)
+		if err != nil || f <= 0 {
+			return 0, false
+		}
+
+		return uint32(f * mult), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,91 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cobaltspeech/examples-go/pkg/audio"
+	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2/diathekepb"
+)
+
+func TestArgSampleRate(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		args     string
+		expected uint32
+		found    bool
+	}{
+		{"-q -d -c 1 -r 16000 -b 16 -L -e signed -t raw -", 16000, true},
+		{"-q -c 1 -r 8k -t raw - -d", 8000, true},
+		{"-f S16_LE --rate=22050 -c 1", 22050, true},
+		{"--rate 44100", 44100, true},
+		{"-q -d -c 1 -t raw -", 0, false},
+		{"-r", 0, false},
+		{"-r fast", 0, false},
+	}
+
+	for _, test := range testList {
+		cfg := audio.Config{Args: test.args}
+
+		rate, found := argSampleRate(cfg.ArgList())
+		if rate != test.expected || found != test.found {
+			t.Errorf("%q - expected: (%d, %t), actual: (%d, %t)", test.args, test.expected, test.found, rate, found)
+		}
+	}
+}
+
+func TestCheckSampleRates(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{
+		Server:    ServerConfig{ModelID: "2"},
+		Recording: audio.Config{Application: "sox", Args: "-d -r 16000 -t raw -"},
+		Playback:  audio.Config{Application: "sox", Args: "-t raw - -d"},
+	}
+
+	models := []*diathekepb.ModelInfo{
+		{Id: "1", AsrSampleRate: 8000, TtsSampleRate: 8000},
+		{Id: "2", AsrSampleRate: 16000, TtsSampleRate: 22050},
+	}
+
+	asrRate, ttsRate, err := cfg.SampleRates(models)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if asrRate != 16000 || ttsRate != 22050 {
+		t.Errorf("incorrect rates - expected: (16000, 22050), actual: (%d, %d)", asrRate, ttsRate)
+	}
+
+	// The recording rate matches, while the playback rate is missing.
+	warnings := cfg.CheckSampleRates(asrRate, ttsRate)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "playback args do not set") {
+		t.Errorf("unexpected warnings: %q", warnings)
+	}
+
+	// The recording rate doesn't match, and a zero TTS rate isn't checked.
+	if warnings := cfg.CheckSampleRates(8000, 0); len(warnings) != 1 ||
+		!strings.Contains(warnings[0], "rate of 16000 Hz, but the model expects 8000 Hz") {
+		t.Errorf("unexpected warnings: %q", warnings)
+	}
+
+	cfg.Server.ModelID = "3"
+	if _, _, err := cfg.SampleRates(models); err == nil {
+		t.Error("expected an error for a missing model")
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/cobaltspeech/sdk-cubic/grpc/go-cubic"
+	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2"
+)
+
+// DiathekeOptions returns the client options for connecting to the
+// Diatheke server with the configured security settings.
+func (cfg ServerConfig) DiathekeOptions() []diatheke.Option {
+	opts := make([]diatheke.Option, 0)
+
+	switch {
+	case cfg.Insecure:
+		// NOTE: Secure connections are recommended for production
+		opts = append(opts, diatheke.WithInsecure())
+	case cfg.caCert != nil:
+		opts = append(opts, diatheke.WithServerCert(cfg.caCert))
+	}
+
+	return opts
+}
+
+// CubicOptions returns the client options for connecting to the
+// wake word server with the configured security settings.
+func (cfg WakeWordServerConfig) CubicOptions() []cubic.Option {
+	opts := make([]cubic.Option, 0)
+
+	switch {
+	case cfg.Insecure:
+		// NOTE: Secure connections are recommended for production
+		opts = append(opts, cubic.WithInsecure())
+	case cfg.caCert != nil:
+		opts = append(opts, cubic.WithServerCert(cfg.caCert))
+	}
+
+	return opts
+}
+
+// loadCACert reads the PEM encoded CA certificate(s) at the given
+// path, used to verify a server that is not signed by a system CA.
+// An empty path returns nil without an error.
+func loadCACert(path string, insecure bool) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	if insecure {
+		return nil, fmt.Errorf("CACertPath is set for an insecure connection")
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read CA certificate: %w", err)
+	}
+
+	if !x509.NewCertPool().AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no valid PEM certificates found in %s", path)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,86 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadCACert(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validPath := filepath.Join(dir, "ca.pem")
+	validPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	invalidPath := filepath.Join(dir, "invalid.pem")
+
+	for path, data := range map[string][]byte{validPath: validPEM, invalidPath: []byte("not a certificate")} {
+		if err := ioutil.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testList := []struct {
+		path     string
+		insecure bool
+		expected []byte
+		wantErr  bool
+	}{
+		{"", false, nil, false},
+		{"", true, nil, false},
+		{validPath, false, validPEM, false},
+		{validPath, true, nil, true},
+		{invalidPath, false, nil, true},
+		{filepath.Join(dir, "missing.pem"), false, nil, true},
+	}
+
+	for _, test := range testList {
+		data, err := loadCACert(test.path, test.insecure)
+		if (err != nil) != test.wantErr || string(data) != string(test.expected) {
+			t.Errorf("(%q, %t) - expected error: %t, actual: %v", test.path, test.insecure, test.wantErr, err)
+		}
+	}
+}
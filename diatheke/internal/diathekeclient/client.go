@@ -0,0 +1,44 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package diathekeclient defines the subset of the Diatheke SDK client used
+// by the example applications, so that they can be tested without a server.
+package diathekeclient
+
+import (
+	"context"
+
+	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2"
+	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2/diathekepb"
+)
+
+// Client contains the methods of *diatheke.Client used by the examples.
+type Client interface {
+	Version(ctx context.Context) (*diathekepb.VersionResponse, error)
+	ListModels(ctx context.Context) (*diathekepb.ListModelsResponse, error)
+	CreateSession(ctx context.Context, modelID string) (*diathekepb.SessionOutput, error)
+	DeleteSession(ctx context.Context, token *diathekepb.TokenData) error
+	ProcessText(ctx context.Context, token *diathekepb.TokenData, text string) (*diathekepb.SessionOutput, error)
+	ProcessASRResult(ctx context.Context, token *diathekepb.TokenData,
+		result *diathekepb.ASRResult) (*diathekepb.SessionOutput, error)
+	ProcessCommandResult(ctx context.Context, token *diathekepb.TokenData,
+		result *diathekepb.CommandResult) (*diathekepb.SessionOutput, error)
+	NewSessionASRStream(ctx context.Context, token *diathekepb.TokenData) (*diatheke.ASRStream, error)
+	NewTTSStream(ctx context.Context, reply *diathekepb.ReplyAction) (*diatheke.TTSStream, error)
+	NewTranscribeStream(ctx context.Context,
+		action *diathekepb.TranscribeAction) (*diatheke.TranscribeStream, error)
+}
+
+// Verify that the SDK client implements the interface.
+var _ Client = (*diatheke.Client)(nil)
@@ -0,0 +1,272 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diathekeclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2"
+	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2/diathekepb"
+)
+
+// Fake is a Client for tests that returns canned responses and records the
+// requests it receives. The zero value is ready to use.
+type Fake struct {
+	// VersionResponse and Models are returned by Version and ListModels.
+	VersionResponse *diathekepb.VersionResponse
+	Models          *diathekepb.ListModelsResponse
+
+	// Sessions are returned, in order, by each call that creates or updates
+	// a session (CreateSession, ProcessText, ProcessASRResult and
+	// ProcessCommandResult). An error is returned once they run out.
+	Sessions []*diathekepb.SessionOutput
+
+	// ASRResult is returned by the streams from NewSessionASRStream.
+	ASRResult *diathekepb.ASRResult
+
+	// TTSAudio is returned by the streams from NewTTSStream.
+	TTSAudio [][]byte
+
+	// TranscribeResults are returned by the streams from NewTranscribeStream.
+	TranscribeResults []*diathekepb.TranscribeResult
+
+	// Err, if set, is returned by every method.
+	Err error
+
+	mu sync.Mutex
+
+	// Requests received by the fake.
+	Texts          []string
+	ASRResults     []*diathekepb.ASRResult
+	CommandResults []*diathekepb.CommandResult
+	Replies        []*diathekepb.ReplyAction
+	Transcribes    []*diathekepb.TranscribeAction
+	DeletedTokens  []*diathekepb.TokenData
+
+	// TranscribeAudio is the audio sent on the streams from
+	// NewTranscribeStream.
+	TranscribeAudio []byte
+}
+
+// Verify that Fake implements the interface.
+var _ Client = (*Fake)(nil)
+
+// nextSession pops the next canned session.
+func (f *Fake) nextSession() (*diathekepb.SessionOutput, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	if len(f.Sessions) == 0 {
+		return nil, fmt.Errorf("fake client has no more sessions")
+	}
+
+	s := f.Sessions[0]
+	f.Sessions = f.Sessions[1:]
+
+	return s, nil
+}
+
+// Version returns f.VersionResponse.
+func (f *Fake) Version(ctx context.Context) (*diathekepb.VersionResponse, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	if f.VersionResponse == nil {
+		return &diathekepb.VersionResponse{}, nil
+	}
+
+	return f.VersionResponse, nil
+}
+
+// ListModels returns f.Models.
+func (f *Fake) ListModels(ctx context.Context) (*diathekepb.ListModelsResponse, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	if f.Models == nil {
+		return &diathekepb.ListModelsResponse{}, nil
+	}
+
+	return f.Models, nil
+}
+
+// CreateSession returns the next canned session.
+func (f *Fake) CreateSession(ctx context.Context, modelID string) (*diathekepb.SessionOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.nextSession()
+}
+
+// DeleteSession records the deleted session token.
+func (f *Fake) DeleteSession(ctx context.Context, token *diathekepb.TokenData) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.DeletedTokens = append(f.DeletedTokens, token)
+
+	return f.Err
+}
+
+// ProcessText records the text and returns the next canned session.
+func (f *Fake) ProcessText(ctx context.Context, token *diathekepb.TokenData,
+	text string) (*diathekepb.SessionOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.Texts = append(f.Texts, text)
+
+	return f.nextSession()
+}
+
+// ProcessASRResult records the ASR result and returns the next canned session.
+func (f *Fake) ProcessASRResult(ctx context.Context, token *diathekepb.TokenData,
+	result *diathekepb.ASRResult) (*diathekepb.SessionOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.ASRResults = append(f.ASRResults, result)
+
+	return f.nextSession()
+}
+
+// ProcessCommandResult records the command result and returns the next
+// canned session.
+func (f *Fake) ProcessCommandResult(ctx context.Context, token *diathekepb.TokenData,
+	result *diathekepb.CommandResult) (*diathekepb.SessionOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.CommandResults = append(f.CommandResults, result)
+
+	return f.nextSession()
+}
+
+// NewSessionASRStream returns a stream that discards the audio it is sent and
+// returns f.ASRResult once closed.
+func (f *Fake) NewSessionASRStream(ctx context.Context,
+	token *diathekepb.TokenData) (*diatheke.ASRStream, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	return &diatheke.ASRStream{PBStream: &fakeASRStream{result: f.ASRResult}}, nil
+}
+
+// NewTTSStream records the reply and returns a stream with f.TTSAudio.
+func (f *Fake) NewTTSStream(ctx context.Context,
+	reply *diathekepb.ReplyAction) (*diatheke.TTSStream, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	f.Replies = append(f.Replies, reply)
+
+	return &diatheke.TTSStream{PBStream: &fakeTTSStream{audio: f.TTSAudio}}, nil
+}
+
+// NewTranscribeStream records the action and returns a stream that records
+// the audio it is sent in f.TranscribeAudio and returns f.TranscribeResults.
+func (f *Fake) NewTranscribeStream(ctx context.Context,
+	action *diathekepb.TranscribeAction) (*diatheke.TranscribeStream, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	f.Transcribes = append(f.Transcribes, action)
+
+	return &diatheke.TranscribeStream{PBStream: &fakeTranscribeStream{fake: f, results: f.TranscribeResults}}, nil
+}
+
+// The fake streams embed their interface for the grpc.ClientStream methods,
+// which are never called by the examples.
+
+type fakeASRStream struct {
+	diathekepb.Diatheke_StreamASRClient
+
+	result *diathekepb.ASRResult
+}
+
+func (s *fakeASRStream) Send(*diathekepb.ASRInput) error {
+	return nil
+}
+
+func (s *fakeASRStream) CloseAndRecv() (*diathekepb.ASRResult, error) {
+	if s.result == nil {
+		return &diathekepb.ASRResult{}, nil
+	}
+
+	return s.result, nil
+}
+
+type fakeTTSStream struct {
+	diathekepb.Diatheke_StreamTTSClient
+
+	audio [][]byte
+}
+
+func (s *fakeTTSStream) Recv() (*diathekepb.TTSAudio, error) {
+	if len(s.audio) == 0 {
+		return nil, io.EOF
+	}
+
+	a := s.audio[0]
+	s.audio = s.audio[1:]
+
+	return &diathekepb.TTSAudio{Audio: a}, nil
+}
+
+type fakeTranscribeStream struct {
+	diathekepb.Diatheke_TranscribeClient
+
+	fake    *Fake
+	results []*diathekepb.TranscribeResult
+}
+
+func (s *fakeTranscribeStream) Send(in *diathekepb.TranscribeInput) error {
+	s.fake.mu.Lock()
+	defer s.fake.mu.Unlock()
+
+	s.fake.TranscribeAudio = append(s.fake.TranscribeAudio, in.GetAudio()...)
+
+	return nil
+}
+
+func (s *fakeTranscribeStream) CloseSend() error {
+	return nil
+}
+
+func (s *fakeTranscribeStream) Recv() (*diathekepb.TranscribeResult, error) {
+	if len(s.results) == 0 {
+		return nil, io.EOF
+	}
+
+	r := s.results[0]
+	s.results = s.results[1:]
+
+	return r, nil
+}
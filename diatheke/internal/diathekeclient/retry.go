@@ -0,0 +1,231 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diathekeclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/cobaltspeech/examples-go/diatheke/internal/logging"
+	"github.com/cobaltspeech/examples-go/pkg/backoff"
+	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2"
+	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2/diathekepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DialFunc creates a new connected Client, e.g. with diatheke.NewClient.
+type DialFunc func() (Client, error)
+
+// DefaultRetryDelay is the initial delay between attempts used by the
+// example applications.
+const DefaultRetryDelay = 500 * time.Millisecond
+
+// maxRetryDelayFactor limits the delay between attempts to this many times
+// the initial delay.
+const maxRetryDelayFactor = 16
+
+// Retry is a Client that retries the session updates (ProcessText,
+// ProcessASRResult and ProcessCommandResult) when they fail because the
+// server is unavailable, re-dialing the server before each new attempt.
+// The same session token is sent again, so the session is resumed where
+// it was. Other calls are forwarded to the current client as is, since
+// streams can't be replayed.
+type Retry struct {
+	mu     sync.Mutex
+	client Client
+	dial   DialFunc
+	logger logging.Logger
+
+	attempts int
+	delay    time.Duration
+}
+
+// Verify that Retry implements the interface.
+var _ Client = (*Retry)(nil)
+
+// NewRetry dials a client and returns a Retry that makes up to the given
+// number of attempts for each session update, waiting an exponentially
+// increasing delay, starting at the given one, between attempts. A single
+// attempt disables retries. The retries are logged to logger.
+func NewRetry(dial DialFunc, attempts int, delay time.Duration, logger logging.Logger) (*Retry, error) {
+	if attempts < 1 {
+		return nil, fmt.Errorf("invalid number of attempts %d, must be at least 1", attempts)
+	}
+
+	if delay <= 0 {
+		return nil, fmt.Errorf("invalid retry delay %v, must be positive", delay)
+	}
+
+	client, err := dial()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Retry{client: client, dial: dial, logger: logger, attempts: attempts, delay: delay}, nil
+}
+
+// Close closes the current client, if it can be closed.
+func (r *Retry) Close() error {
+	return closeClient(r.current())
+}
+
+// current returns the client to use for the next call.
+func (r *Retry) current() Client {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.client
+}
+
+// redial replaces the current client with a new one.
+func (r *Retry) redial() error {
+	client, err := r.dial()
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	old := r.client
+	r.client = client
+	r.mu.Unlock()
+
+	if err := closeClient(old); err != nil {
+		r.logger.Error("msg", "error closing the previous Diatheke client", "err", err)
+	}
+
+	return nil
+}
+
+// closeClient closes the client if it implements io.Closer.
+func closeClient(c Client) error {
+	if closer, ok := c.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// retry calls update until it succeeds, fails with an error that isn't
+// retriable, or the attempts run out.
+func (r *Retry) retry(ctx context.Context, name string,
+	update func(Client) (*diathekepb.SessionOutput, error)) (*diathekepb.SessionOutput, error) {
+	session, err := update(r.current())
+	if err == nil || r.attempts == 1 {
+		return session, err
+	}
+
+	b, berr := backoff.New(r.delay, r.delay*maxRetryDelayFactor)
+	if berr != nil {
+		return nil, berr
+	}
+
+	for attempt := 2; attempt <= r.attempts && isRetriable(err); attempt++ {
+		r.logger.Info("msg", "session update failed, retrying", "call", name,
+			"attempt", attempt, "attempts", r.attempts, "err", err)
+
+		if werr := b.Wait(ctx); werr != nil {
+			return nil, err
+		}
+
+		if derr := r.redial(); derr != nil {
+			err = derr
+
+			continue
+		}
+
+		session, err = update(r.current())
+	}
+
+	return session, err
+}
+
+// isRetriable returns whether the error is a gRPC status for which it is
+// worth reconnecting and trying again.
+func isRetriable(err error) bool {
+	var s interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &s) {
+		return false
+	}
+
+	return s.GRPCStatus().Code() == codes.Unavailable
+}
+
+// ProcessText retries the ProcessText call of the current client.
+func (r *Retry) ProcessText(ctx context.Context, token *diathekepb.TokenData,
+	text string) (*diathekepb.SessionOutput, error) {
+	return r.retry(ctx, "ProcessText", func(c Client) (*diathekepb.SessionOutput, error) {
+		return c.ProcessText(ctx, token, text)
+	})
+}
+
+// ProcessASRResult retries the ProcessASRResult call of the current client.
+func (r *Retry) ProcessASRResult(ctx context.Context, token *diathekepb.TokenData,
+	result *diathekepb.ASRResult) (*diathekepb.SessionOutput, error) {
+	return r.retry(ctx, "ProcessASRResult", func(c Client) (*diathekepb.SessionOutput, error) {
+		return c.ProcessASRResult(ctx, token, result)
+	})
+}
+
+// ProcessCommandResult retries the ProcessCommandResult call of the current
+// client.
+func (r *Retry) ProcessCommandResult(ctx context.Context, token *diathekepb.TokenData,
+	result *diathekepb.CommandResult) (*diathekepb.SessionOutput, error) {
+	return r.retry(ctx, "ProcessCommandResult", func(c Client) (*diathekepb.SessionOutput, error) {
+		return c.ProcessCommandResult(ctx, token, result)
+	})
+}
+
+// Version calls Version on the current client.
+func (r *Retry) Version(ctx context.Context) (*diathekepb.VersionResponse, error) {
+	return r.current().Version(ctx)
+}
+
+// ListModels calls ListModels on the current client.
+func (r *Retry) ListModels(ctx context.Context) (*diathekepb.ListModelsResponse, error) {
+	return r.current().ListModels(ctx)
+}
+
+// CreateSession calls CreateSession on the current client.
+func (r *Retry) CreateSession(ctx context.Context, modelID string) (*diathekepb.SessionOutput, error) {
+	return r.current().CreateSession(ctx, modelID)
+}
+
+// DeleteSession calls DeleteSession on the current client.
+func (r *Retry) DeleteSession(ctx context.Context, token *diathekepb.TokenData) error {
+	return r.current().DeleteSession(ctx, token)
+}
+
+// NewSessionASRStream calls NewSessionASRStream on the current client.
+func (r *Retry) NewSessionASRStream(ctx context.Context,
+	token *diathekepb.TokenData) (*diatheke.ASRStream, error) {
+	return r.current().NewSessionASRStream(ctx, token)
+}
+
+// NewTTSStream calls NewTTSStream on the current client.
+func (r *Retry) NewTTSStream(ctx context.Context,
+	reply *diathekepb.ReplyAction) (*diatheke.TTSStream, error) {
+	return r.current().NewTTSStream(ctx, reply)
+}
+
+// NewTranscribeStream calls NewTranscribeStream on the current client.
+func (r *Retry) NewTranscribeStream(ctx context.Context,
+	action *diathekepb.TranscribeAction) (*diatheke.TranscribeStream, error) {
+	return r.current().NewTranscribeStream(ctx, action)
+}
@@ -0,0 +1,104 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diathekeclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cobaltspeech/log"
+	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2/diathekepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testDialer returns the fakes in order, one per dial.
+type testDialer struct {
+	fakes []*Fake
+	dials int
+}
+
+func (d *testDialer) dial() (Client, error) {
+	if d.dials >= len(d.fakes) {
+		return nil, errors.New("no more fakes")
+	}
+
+	f := d.fakes[d.dials]
+	d.dials++
+
+	return f, nil
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+	session := &diathekepb.SessionOutput{Token: &diathekepb.TokenData{Id: "1"}}
+
+	testList := []struct {
+		name     string
+		fakes    []*Fake
+		attempts int
+		dials    int
+		err      error
+	}{
+		{"success", []*Fake{{Sessions: []*diathekepb.SessionOutput{session}}}, 3, 1, nil},
+		{"retried", []*Fake{{Err: unavailable}, {Err: unavailable}, {Sessions: []*diathekepb.SessionOutput{session}}}, 3, 3, nil},
+		{"attempts run out", []*Fake{{Err: unavailable}, {Err: unavailable}, {}}, 2, 2, unavailable},
+		{"not retriable", []*Fake{{Err: context.Canceled}, {}}, 3, 1, context.Canceled},
+	}
+
+	for i := range testList {
+		test := testList[i]
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := &testDialer{fakes: test.fakes}
+
+			r, err := NewRetry(d.dial, test.attempts, time.Millisecond, log.NewDiscardLogger())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			token := &diathekepb.TokenData{Id: "token"}
+
+			actual, err := r.ProcessText(context.Background(), token, "hello")
+			if !errors.Is(err, test.err) {
+				t.Errorf("incorrect error - expected: %v, actual: %v", test.err, err)
+			}
+
+			if test.err == nil && actual != session {
+				t.Errorf("incorrect session - expected: %v, actual: %v", session, actual)
+			}
+
+			if d.dials != test.dials {
+				t.Errorf("incorrect number of dials - expected: %d, actual: %d", test.dials, d.dials)
+			}
+
+			// The same text is sent with each attempt.
+			last := test.fakes[d.dials-1]
+			if len(last.Texts) != 1 || last.Texts[0] != "hello" {
+				t.Errorf("incorrect texts sent to the last client: %v", last.Texts)
+			}
+		})
+	}
+
+	if _, err := NewRetry((&testDialer{}).dial, 0, time.Millisecond, log.NewDiscardLogger()); err == nil {
+		t.Error("expected an error for zero attempts")
+	}
+}
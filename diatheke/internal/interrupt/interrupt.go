@@ -0,0 +1,48 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package interrupt cancels a context when the user interrupts the
+// application, so that a dialog can be cleaned up before it exits.
+package interrupt
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Context returns a copy of parent that is cancelled when the process
+// receives an interrupt (Ctrl-C) or SIGTERM. The default handling of
+// these signals is restored after the first one, so that a second
+// Ctrl-C exits immediately if the cleanup hangs. Calling the returned
+// cancel function releases the signal handler.
+func Context(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case <-sigCh:
+		case <-ctx.Done():
+		}
+
+		signal.Stop(sigCh)
+		cancel()
+	}()
+
+	return ctx, cancel
+}
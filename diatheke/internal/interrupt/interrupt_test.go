@@ -0,0 +1,55 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interrupt
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestContext(t *testing.T) {
+	ctx, cancel := Context(context.Background())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		t.Fatal("context cancelled before the interrupt")
+	}
+
+	proc, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := proc.Signal(os.Interrupt); err != nil {
+		t.Skipf("could not send the interrupt: %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context was not cancelled by the interrupt")
+	}
+}
+
+func TestContextCancel(t *testing.T) {
+	ctx, cancel := Context(context.Background())
+	cancel()
+
+	if ctx.Err() != context.Canceled {
+		t.Errorf("incorrect error - expected: %v, actual: %v", context.Canceled, ctx.Err())
+	}
+}
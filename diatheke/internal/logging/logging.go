@@ -0,0 +1,37 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logging creates the leveled loggers used by the diatheke
+// clients for diagnostic messages. User-facing output, such as prompts
+// and replies, is printed to stdout instead.
+package logging
+
+import (
+	"github.com/cobaltspeech/log"
+	"github.com/cobaltspeech/log/pkg/level"
+)
+
+// Logger is the leveled logger used by the clients.
+type Logger = log.Logger
+
+// New returns a logger that writes Error and Info messages to stderr,
+// and Debug messages as well if verbose is set.
+func New(verbose bool) Logger {
+	lvl := level.Error | level.Info
+	if verbose {
+		lvl |= level.Debug
+	}
+
+	return log.NewLeveledLogger(log.WithFilterLevel(lvl))
+}
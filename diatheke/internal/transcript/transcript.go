@@ -0,0 +1,132 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package transcript writes a log of the dialog to a file, as one JSON
+// object per line, to help debug Diatheke models.
+package transcript
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cobaltspeech/examples-go/diatheke/internal/logging"
+	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2/diathekepb"
+)
+
+// Entry types.
+const (
+	TypeReply     = "reply"
+	TypeText      = "text"
+	TypeASRResult = "asr"
+	TypeCommand   = "command"
+
+	TypeTranscription = "transcription"
+)
+
+// Entry is a line of the transcript.
+type Entry struct {
+	Time       time.Time         `json:"time"`
+	Type       string            `json:"type"`
+	Text       string            `json:"text,omitempty"`
+	Confidence float64           `json:"confidence,omitempty"`
+	CommandID  string            `json:"commandId,omitempty"`
+	Params     map[string]string `json:"params,omitempty"`
+
+	// TranscribeID is the ID of the transcribe action of a transcription.
+	TranscribeID string `json:"transcribeId,omitempty"`
+}
+
+// Writer appends entries to a transcript. Each entry is written with a
+// single unbuffered write, so the transcript is complete up to the last
+// entry even if the application crashes. A nil *Writer discards the
+// entries, so that applications can call it unconditionally.
+type Writer struct {
+	mu     sync.Mutex
+	out    io.WriteCloser
+	now    func() time.Time
+	logger logging.Logger
+}
+
+// Open opens the transcript file at the given path, creating it if
+// needed. New entries are appended to the existing ones. Errors writing
+// entries are logged to logger.
+func Open(path string, logger logging.Logger) (*Writer, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644) //nolint:gosec,gomnd // not secret
+	if err != nil {
+		return nil, err
+	}
+
+	return &Writer{out: f, now: time.Now, logger: logger}, nil
+}
+
+// Close closes the transcript file.
+func (w *Writer) Close() error {
+	if w == nil {
+		return nil
+	}
+
+	return w.out.Close()
+}
+
+// Reply records a reply from Diatheke.
+func (w *Writer) Reply(reply *diathekepb.ReplyAction) {
+	w.write(Entry{Type: TypeReply, Text: reply.Text})
+}
+
+// Text records text input from the user.
+func (w *Writer) Text(text string) {
+	w.write(Entry{Type: TypeText, Text: text})
+}
+
+// ASRResult records the transcription of the user's speech.
+func (w *Writer) ASRResult(result *diathekepb.ASRResult) {
+	w.write(Entry{Type: TypeASRResult, Text: result.Text, Confidence: result.Confidence})
+}
+
+// Command records a command to execute.
+func (w *Writer) Command(cmd *diathekepb.CommandAction) {
+	w.write(Entry{Type: TypeCommand, CommandID: cmd.Id, Params: cmd.InputParameters})
+}
+
+// Transcription records the final text of a transcribe action.
+func (w *Writer) Transcription(action *diathekepb.TranscribeAction, text string) {
+	w.write(Entry{Type: TypeTranscription, Text: text, TranscribeID: action.Id})
+}
+
+// write adds the timestamp to the entry and writes it. Errors are logged
+// rather than returned, since the transcript is only a debugging aid.
+func (w *Writer) write(e Entry) {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	e.Time = w.now()
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		w.logger.Error("msg", "error encoding transcript entry", "err", err)
+
+		return
+	}
+
+	if _, err := w.out.Write(append(line, '\n')); err != nil {
+		w.logger.Error("msg", "error writing transcript", "err", err)
+	}
+}
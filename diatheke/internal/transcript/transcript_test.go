@@ -0,0 +1,104 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cobaltspeech/log"
+	"github.com/cobaltspeech/sdk-diatheke/grpc/go-diatheke/v2/diathekepb"
+)
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	now := time.Date(2021, time.June, 15, 13, 45, 0, 0, time.UTC)
+
+	// Entries are appended to an existing transcript.
+	for i := 0; i < 2; i++ {
+		w, err := Open(path, log.NewDiscardLogger())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w.now = func() time.Time { return now }
+
+		w.Reply(&diathekepb.ReplyAction{Text: "What can I do for you?"})
+		w.ASRResult(&diathekepb.ASRResult{Text: "turn on the lights", Confidence: 0.9})
+		w.Text("turn off the lights")
+		w.Command(&diathekepb.CommandAction{Id: "lights", InputParameters: map[string]string{"state": "off"}})
+		w.Transcription(&diathekepb.TranscribeAction{Id: "note"}, "buy milk")
+
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	expected := []Entry{
+		{Time: now, Type: TypeReply, Text: "What can I do for you?"},
+		{Time: now, Type: TypeASRResult, Text: "turn on the lights", Confidence: 0.9},
+		{Time: now, Type: TypeText, Text: "turn off the lights"},
+		{Time: now, Type: TypeCommand, CommandID: "lights", Params: map[string]string{"state": "off"}},
+		{Time: now, Type: TypeTranscription, Text: "buy milk", TranscribeID: "note"},
+	}
+
+	var lines int
+
+	scanner := bufio.NewScanner(f)
+	for ; scanner.Scan(); lines++ {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", lines+1, err)
+		}
+
+		exp := expected[lines%len(expected)]
+		if !e.Time.Equal(exp.Time) || e.Type != exp.Type || e.Text != exp.Text ||
+			e.Confidence != exp.Confidence || e.CommandID != exp.CommandID || e.Params["state"] != exp.Params["state"] ||
+			e.TranscribeID != exp.TranscribeID {
+			t.Errorf("line %d - expected: %+v, actual: %+v", lines+1, exp, e)
+		}
+	}
+
+	if lines != 2*len(expected) {
+		t.Errorf("incorrect number of lines - expected: %d, actual: %d", 2*len(expected), lines)
+	}
+}
+
+func TestNilWriter(t *testing.T) {
+	t.Parallel()
+
+	var w *Writer
+
+	// A nil writer discards everything.
+	w.Reply(&diathekepb.ReplyAction{Text: "hello"})
+	w.Text("hello")
+
+	if err := w.Close(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package appconfig has the config file helpers shared by the example
+// clients: selecting a model by name and dumping the effective settings.
+// Each client adapts them to the model and config types of its SDK.
+package appconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Model is the ID and name of a model listed by a server.
+type Model struct {
+	ID   string
+	Name string
+}
+
+// ResolveModelID returns the ID of the model to use: modelID if it is set, so
+// that it takes precedence, or else the ID of the model named modelName,
+// found in the given list of models (e.g., from ListModels). Names are
+// compared without regard to case. It returns an empty ID if neither is set,
+// and an error listing the available models if the name matches no model or
+// more than one.
+func ResolveModelID(modelID, modelName string, models []Model) (string, error) {
+	if modelID != "" || modelName == "" {
+		return modelID, nil
+	}
+
+	var matches []string
+
+	for _, mdl := range models {
+		if strings.EqualFold(mdl.Name, modelName) {
+			matches = append(matches, mdl.ID)
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return "", fmt.Errorf("no model named %q, available models: %s", modelName, modelList(models))
+	default:
+		return "", fmt.Errorf("%d models named %q, set ModelID to one of: %s",
+			len(matches), modelName, modelList(models))
+	}
+}
+
+// modelList formats the IDs and names of the models for error messages.
+func modelList(models []Model) string {
+	if len(models) == 0 {
+		return "none"
+	}
+
+	list := make([]string, 0, len(models))
+	for _, mdl := range models {
+		list = append(list, fmt.Sprintf("%q (ID %q)", mdl.Name, mdl.ID))
+	}
+
+	return strings.Join(list, ", ")
+}
+
+// Dump writes the given configuration to w, formatted as "toml" or "json".
+// It is meant to show the settings actually in effect after the config file
+// has been loaded and any overrides applied.
+func Dump(w io.Writer, cfg interface{}, format string) error {
+	switch format {
+	case "toml":
+		return toml.NewEncoder(w).Encode(cfg)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(cfg)
+	default:
+		return fmt.Errorf("unsupported config dump format %q (expected toml or json)", format)
+	}
+}
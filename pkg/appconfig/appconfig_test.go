@@ -0,0 +1,93 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appconfig
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestResolveModelID(t *testing.T) {
+	t.Parallel()
+
+	models := []Model{
+		{ID: "en-us-8-close", Name: "English (US) 8kHz"},
+		{ID: "en-us-16-far", Name: "English (US) 16kHz"},
+		{ID: "en-us-16-near", Name: "english (us) 16khz"},
+	}
+
+	testList := []struct {
+		modelID   string
+		modelName string
+		expected  string
+		errMsg    string
+	}{
+		{"", "english (us) 8khz", "en-us-8-close", ""},
+		{"en-us-16-far", "English (US) 8kHz", "en-us-16-far", ""},
+		{"", "", "", ""},
+		{"", "Spanish", "", `available models: "English (US) 8kHz" (ID "en-us-8-close")`},
+		{"", "English (US) 16kHz", "", "2 models named"},
+	}
+
+	for _, test := range testList {
+		actual, err := ResolveModelID(test.modelID, test.modelName, models)
+		if test.errMsg == "" && err != nil {
+			t.Errorf("%q: unexpected error: %v", test.modelName, err)
+		} else if test.errMsg != "" && (err == nil || !strings.Contains(err.Error(), test.errMsg)) {
+			t.Errorf("%q: expected an error containing %q, got: %v", test.modelName, test.errMsg, err)
+		}
+
+		if actual != test.expected {
+			t.Errorf("%q: incorrect model ID - expected: %q, actual: %q", test.modelName, test.expected, actual)
+		}
+	}
+
+	if _, err := ResolveModelID("", "English", nil); err == nil || !strings.Contains(err.Error(), "available models: none") {
+		t.Errorf("expected an error listing no models, got: %v", err)
+	}
+}
+
+func TestDump(t *testing.T) {
+	t.Parallel()
+
+	cfg := struct {
+		Address string
+		Workers int
+	}{"localhost:2727", 2}
+
+	testList := []struct {
+		format   string
+		expected string
+	}{
+		{"toml", "Address = \"localhost:2727\"\nWorkers = 2\n"},
+		{"json", "{\n  \"Address\": \"localhost:2727\",\n  \"Workers\": 2\n}\n"},
+	}
+
+	for _, test := range testList {
+		var buf bytes.Buffer
+		if err := Dump(&buf, cfg, test.format); err != nil {
+			t.Fatalf("%s: %v", test.format, err)
+		}
+
+		if actual := buf.String(); actual != test.expected {
+			t.Errorf("%s: incorrect output - expected: %q, actual: %q", test.format, test.expected, actual)
+		}
+	}
+
+	if err := Dump(&bytes.Buffer{}, cfg, "yaml"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
@@ -0,0 +1,726 @@
+// Copyright (2020 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audio provides audio I/O to an application by piping the
+// audio data to an external application over stdin or stdout.
+package audio
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config contains the information to run an external
+// application for the audio I/O.
+type Config struct {
+	Application string
+	Args        string
+
+	// SourceFile, if set, makes a Recorder stream audio from the given
+	// file instead of running Application. Raw files are read as is,
+	// while the header of .wav files is skipped. It is ignored by Player.
+	SourceFile string
+
+	// CapturePath, if set, makes a Recorder save the audio it records to
+	// a WAV file at the given path, e.g. to debug bad transcripts. It is
+	// ignored by Player.
+	CapturePath string
+
+	// PauseMode selects what a Recorder does with the audio recorded
+	// while it is paused: "buffer" (the default) returns it once resumed,
+	// while "discard" drops it. It is ignored by Player.
+	PauseMode string
+
+	// SampleRate is the sample rate of the recorded audio, used for the
+	// header of the capture file and by clients that resample or filter
+	// the audio. The audio is assumed to be 16-bit mono. Defaults to 16000.
+	SampleRate int
+
+	// Gain is the factor applied to the 16-bit PCM audio pushed to a
+	// Player, e.g. 2.0 to boost quiet audio. Samples are clipped to the
+	// int16 range. Zero (unset) means 1.0. It is ignored by Recorder.
+	Gain float32
+}
+
+// ArgList returns the arguments as a list of strings
+func (ac *Config) ArgList() []string {
+	return strings.Fields(ac.Args)
+}
+
+// Validate checks that Application, if set, is an existing file or can be
+// found in the system path, so that a misspelled application is reported
+// before any audio is recorded or played.
+func (ac *Config) Validate() error {
+	if ac.Application == "" {
+		return nil
+	}
+
+	// Verify that the file (executable) exists
+	info, err := os.Stat(ac.Application)
+	if err != nil {
+		// This is a path error, which means we couldn't find the file.
+		// Check the system path to see if we can find it there.
+		if _, err := exec.LookPath(ac.Application); err != nil {
+			return fmt.Errorf("could not find application %s", ac.Application)
+		}
+	} else if info.IsDir() {
+		return fmt.Errorf("application is a directory, not an executable")
+	}
+
+	return nil
+}
+
+// Recorder launches an external application to handle recording audio.
+type Recorder struct {
+	// Internal data
+	appConfig Config
+	cmd       *exec.Cmd
+	ctx       context.Context
+	cancel    context.CancelFunc
+	stdout    io.ReadCloser
+	pauser    *pausableReader // stdout, which can be paused
+	output    io.Reader       // pauser, teed to the capture file if there is one
+	capture   *wavWriter      // the capture file, if any
+	errCh     chan error
+	done      chan struct{} // closed once the application has exited
+
+	captureErr error // error writing the capture file
+}
+
+// NewRecorder returns a new recorder object based the given configuration.
+func NewRecorder(cfg Config) Recorder {
+	return Recorder{
+		appConfig: cfg,
+	}
+}
+
+// Start the external recording application, or open the source file
+// if one is configured.
+func (rec *Recorder) Start() error {
+	if rec.stdout != nil {
+		// Ignore if we are already recording
+		return nil
+	}
+
+	// Forget the result of any previous run.
+	rec.errCh = nil
+	rec.captureErr = nil
+
+	discard, err := isDiscardPauseMode(rec.appConfig.PauseMode)
+	if err != nil {
+		return err
+	}
+
+	if rec.appConfig.CapturePath != "" {
+		sampleRate := rec.appConfig.SampleRate
+		if sampleRate == 0 {
+			sampleRate = defaultSampleRate
+		}
+
+		capture, err := createWAV(rec.appConfig.CapturePath, sampleRate, 1)
+		if err != nil {
+			return fmt.Errorf("failed to create capture file: %w", err)
+		}
+
+		rec.capture = capture
+	}
+
+	if rec.appConfig.SourceFile != "" {
+		err = rec.startFile()
+	} else {
+		err = rec.startApp()
+	}
+
+	if err != nil {
+		rec.closeCapture()
+		return err
+	}
+
+	rec.pauser = newPausableReader(rec.stdout, discard)
+	rec.output = rec.pauser
+
+	if rec.capture != nil {
+		rec.output = io.TeeReader(rec.pauser, rec.capture)
+	}
+
+	return nil
+}
+
+// Pause makes reads from the recorder block, without stopping the
+// recording application, until Resume() is called. The audio recorded in
+// the meantime is buffered or discarded according to Config.PauseMode. It
+// is safe to call concurrently with Read(), and does nothing if the
+// recorder is not running.
+func (rec *Recorder) Pause() {
+	if rec.pauser != nil {
+		rec.pauser.Pause()
+	}
+}
+
+// Resume continues reading after Pause().
+func (rec *Recorder) Resume() {
+	if rec.pauser != nil {
+		rec.pauser.Resume()
+	}
+}
+
+// defaultSampleRate is the sample rate assumed for recorded audio when
+// none is configured.
+const defaultSampleRate = 16000
+
+// closeCapture closes the capture file, if any, keeping the error.
+func (rec *Recorder) closeCapture() {
+	if rec.capture == nil {
+		return
+	}
+
+	if err := rec.capture.Close(); err != nil {
+		rec.captureErr = fmt.Errorf("failed to write capture file: %w", err)
+	}
+
+	rec.capture = nil
+}
+
+// startApp starts the external recording application.
+func (rec *Recorder) startApp() error {
+	// Create the command context so we can cancel it in the stop function.
+	// This is how we can kill the external application.
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Create the record command. Its stdout is connected to our own pipe
+	// rather than cmd.StdoutPipe() so that waiting for the application to
+	// exit does not close the pipe before all the audio has been read.
+	args := rec.appConfig.ArgList()
+	name := rec.appConfig.Application
+	cmd := exec.CommandContext(ctx, name, args...)
+
+	stdout, stdoutW, err := os.Pipe()
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	var stderr bytes.Buffer
+
+	cmd.Stdout = stdoutW
+	cmd.Stderr = &stderr
+
+	// Run the application
+	if err = cmd.Start(); err != nil {
+		cancel()
+		stdout.Close()
+		stdoutW.Close()
+
+		return err
+	}
+
+	// The application has its own copy of the write end.
+	stdoutW.Close()
+
+	// Save the command
+	rec.cmd = cmd
+	rec.ctx = ctx
+	rec.cancel = cancel
+	rec.stdout = &eofReader{ReadCloser: stdout}
+	rec.errCh = make(chan error, 1)
+	rec.done = make(chan struct{})
+
+	go waitRecorder(ctx, cmd, &stderr, rec.errCh, rec.done)
+
+	return nil
+}
+
+// waitRecorder waits for the recording application to exit and reports
+// any failure, along with its stderr output, on errCh. The application
+// being killed because ctx was cancelled by Stop is not a failure.
+func waitRecorder(ctx context.Context, cmd *exec.Cmd, stderr *bytes.Buffer,
+	errCh chan<- error, done chan<- struct{}) {
+	defer close(done)
+	defer close(errCh)
+
+	err := cmd.Wait()
+	if err == nil {
+		return
+	}
+
+	// An exit code of -1 means the application was terminated by a
+	// signal rather than exiting on its own.
+	if ctx.Err() != nil && cmd.ProcessState != nil && cmd.ProcessState.ExitCode() == -1 {
+		return
+	}
+
+	errCh <- fmt.Errorf("recording application %s failed: %w: %s",
+		cmd.Path, err, strings.TrimSpace(stderr.String()))
+}
+
+// Err returns a channel that receives an error if the recording
+// application exits abnormally, and is closed once the application has
+// exited. The channel remains available after Stop(), so it can be checked
+// to find out why the audio ended. It returns nil before Start() is called
+// and when recording from a source file.
+func (rec *Recorder) Err() <-chan error {
+	return rec.errCh
+}
+
+// startFile opens the source file so that its audio can be read in
+// place of the output of the recording application.
+func (rec *Recorder) startFile() error {
+	fs := NewFileSource(rec.appConfig.SourceFile)
+	if err := fs.Start(); err != nil {
+		return err
+	}
+
+	rec.stdout = fs
+
+	return nil
+}
+
+// Stop the external recording application, or close the source file.
+// The capture file, if any, is completed.
+func (rec *Recorder) Stop() {
+	if rec.stdout == nil {
+		// Ignore if it is already stopped.
+		return
+	}
+
+	defer rec.closeCapture()
+
+	// Unblock any paused reads, which fail once the output is closed.
+	rec.pauser.Resume()
+
+	if rec.cmd == nil {
+		// Recording from a file
+		rec.stdout.Close()
+		rec.stdout = nil
+		rec.pauser = nil
+		rec.output = nil
+
+		return
+	}
+
+	// By the time we exit this function, we want everything to be reset
+	defer func() {
+		rec.ctx = nil
+		rec.cancel = nil
+		rec.cmd = nil
+		rec.stdout = nil
+		rec.pauser = nil
+		rec.output = nil
+		rec.done = nil
+	}()
+
+	// If all the output has been read, the application is exiting on its
+	// own. Give it a moment so that a failure is reported rather than
+	// hidden by killing it.
+	if r, ok := rec.stdout.(*eofReader); ok && r.reachedEOF() {
+		select {
+		case <-rec.done:
+		case <-time.After(exitGracePeriod):
+		}
+	}
+
+	// Cancel the context, which should kill the executable. Then wait
+	// for it to finish.
+	rec.cancel()
+	<-rec.done
+	rec.stdout.Close()
+}
+
+// exitGracePeriod is how long Stop waits for a recording application
+// that closed its output to exit before killing it.
+const exitGracePeriod = time.Second
+
+// eofReader records whether the wrapped reader has returned io.EOF.
+type eofReader struct {
+	io.ReadCloser
+	eof int32
+}
+
+func (r *eofReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err == io.EOF {
+		atomic.StoreInt32(&r.eof, 1)
+	}
+
+	return n, err
+}
+
+func (r *eofReader) reachedEOF() bool {
+	return atomic.LoadInt32(&r.eof) == 1
+}
+
+// CaptureErr returns the error, if any, from writing the capture file.
+// It should be called after Stop().
+func (rec *Recorder) CaptureErr() error {
+	return rec.captureErr
+}
+
+// Output returns an io.Reader that reads audio from the application.
+// Should be called after Start() has been called.
+func (rec *Recorder) Output() io.Reader {
+	return rec.output
+}
+
+// Read audio data from the external recording application and put it into p.
+func (rec *Recorder) Read(p []byte) (n int, err error) {
+	if rec.stdout == nil {
+		// It is an error to call this if the recorder isn't running.
+		return 0, fmt.Errorf("recorder application is not running")
+	}
+
+	// Grab data from stdout.
+	return rec.output.Read(p)
+}
+
+// Player represents the external playback executable
+// with args.
+type Player struct {
+	appConfig Config
+	cmd       *exec.Cmd
+	ctx       context.Context
+	stdin     io.WriteCloser
+	input     io.Writer // stdin, with the gain applied
+	stderr    *tailWriter
+}
+
+const (
+	// playerStderrLines is the number of lines of the playback
+	// application's stderr output included in a PlaybackError.
+	playerStderrLines = 10
+
+	// playerStderrBytes limits the size of the kept stderr output.
+	playerStderrBytes = 4096
+)
+
+// PlaybackError is returned by Player.Stop when the playback application
+// exits abnormally, e.g. because the audio device is busy.
+type PlaybackError struct {
+	Application string
+	ExitCode    int    // -1 if the application was killed by a signal
+	Stderr      string // the last lines written to stderr
+	Err         error  // the error returned by waiting for the application
+}
+
+func (e *PlaybackError) Error() string {
+	msg := fmt.Sprintf("playback application %s exited with code %d", e.Application, e.ExitCode)
+	if e.Stderr != "" {
+		msg += ": " + e.Stderr
+	}
+
+	return msg
+}
+
+// Unwrap returns the underlying error.
+func (e *PlaybackError) Unwrap() error {
+	return e.Err
+}
+
+// NewPlayer creates a new player object based on the
+// given config.
+func NewPlayer(cfg Config) Player {
+	return Player{
+		appConfig: cfg,
+	}
+}
+
+// Start the external playback application.
+func (p *Player) Start() error {
+	return p.StartContext(context.Background())
+}
+
+// StartContext starts the external playback application, which is killed
+// if the context is cancelled before the playback completes. This allows
+// aborting a long playback, e.g. when the user barges in.
+func (p *Player) StartContext(ctx context.Context) error {
+	// Ignore if it is already running
+	if p.cmd != nil {
+		return nil
+	}
+
+	// Setup the command and get its stdin pipe
+	name := p.appConfig.Application
+	args := p.appConfig.ArgList()
+	cmd := exec.CommandContext(ctx, name, args...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	// Keep the end of the stderr output to explain failures.
+	stderr := newTailWriter(playerStderrLines, playerStderrBytes)
+	cmd.Stderr = stderr
+
+	// Run the application
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	// Save the command
+	p.cmd = cmd
+	p.ctx = ctx
+	p.stdin = stdin
+	p.input = stdin
+	p.stderr = stderr
+
+	if g := p.appConfig.Gain; g != 0 && g != 1 {
+		p.input = newGainWriter(stdin, float64(g))
+	}
+
+	return nil
+}
+
+// Stop the external playback application. If it exits abnormally, the
+// returned error is a *PlaybackError with its exit code and the last lines
+// of its stderr output.
+func (p *Player) Stop() error {
+	// Ignore if it is not running
+	if p.cmd == nil {
+		return nil
+	}
+
+	// By the time we exit this function, we want everything to be reset
+	defer func() {
+		p.cmd = nil
+		p.ctx = nil
+		p.stdin = nil
+		p.input = nil
+		p.stderr = nil
+	}()
+
+	// Close the stdin pipe (which should also close the application)
+	// and wait for the app to complete
+	p.stdin.Close()
+
+	if err := p.cmd.Wait(); err != nil {
+		if p.ctx.Err() != nil {
+			// The application was killed because the context was
+			// cancelled, which is not an error when stopping.
+			return nil
+		}
+
+		perr := &PlaybackError{
+			Application: p.cmd.Path,
+			ExitCode:    -1,
+			Stderr:      p.stderr.String(),
+			Err:         err,
+		}
+
+		if p.cmd.ProcessState != nil {
+			perr.ExitCode = p.cmd.ProcessState.ExitCode()
+		}
+
+		return perr
+	}
+
+	return nil
+}
+
+// PushAudio data to the player app. Start() should
+// be called prior to using this function.
+func (p *Player) PushAudio(audio []byte) error {
+	if p.stdin == nil {
+		return fmt.Errorf("player application is not running")
+	}
+
+	// Write the audio data to stdin
+	return binary.Write(p.input, binary.LittleEndian, audio)
+}
+
+// Input returns an io.Writer that TTS audio can be pushed to.
+func (p *Player) Input() io.Writer {
+	return p.input
+}
+
+// StoppableReader wraps an existing Reader that can be "stopped"
+// with a function call where the next Read() will return EOF, but future
+// reads will be successful. This reader can also be "rewound" to a point in
+// the past that allows some old data to be re-read before any new data is
+// read. Rewind() cannot go back more than the maximum buffer size, but it
+// may be called repeatedly without Reset() to re-read the same or earlier
+// data.
+// The maximum buffer size parameter is not a hard limit, but if the
+// buffer grows past maxBufferSize*bufferSizeFactor then it will be pruned
+// down to a size of maxBufferSize the oldest bytes will be removed from
+// the buffer. Bytes from an offset registered with ProtectOffset() are
+// never removed, so the buffer may grow larger until the offset is
+// unprotected.
+type StoppableReader struct {
+	mu            sync.Mutex
+	origReader    io.Reader    // the original reader
+	appendReader  io.Reader    // the wrapped reader (buffers what is read)
+	currentReader io.Reader    // the current reader, may include buffered bytes if Rewind() was called
+	buffer        bytes.Buffer // a buffer of previously read bytes
+
+	bufferStartOffset int     // "actual" index at the start of the buffer
+	maxBufferSize     int     // number of bytes stored in the buffer before it may be trimmed
+	bufferSizeFactor  float32 // buffer will be pruned if it grows to maxaBufferSize*bufferSizeFactor
+
+	protectedOffset int  // "actual" index of the oldest byte that may not be pruned
+	protected       bool // set to true if protectedOffset is in use
+
+	pauseRead bool // set to true if the next Read() should return EOF
+}
+
+// NewStoppableReader creates a new stoppable reader that wraps the
+// provided reader and sets the specified maximum buffer size.
+func NewStoppableReader(reader io.Reader, maxBufferSize int) *StoppableReader {
+	var sr StoppableReader
+	sr.origReader = reader
+	sr.appendReader = io.TeeReader(reader, &sr.buffer)
+	sr.currentReader = sr.appendReader
+	sr.bufferStartOffset = 0
+	sr.maxBufferSize = maxBufferSize
+	sr.bufferSizeFactor = 2.0
+	sr.pauseRead = false
+
+	return &sr
+}
+
+// Read bytes from the wrapped Reader and append the bytes to the buffer.
+func (sr *StoppableReader) Read(p []byte) (n int, err error) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	if sr.pauseRead {
+		sr.pauseRead = false // let the next Read() call return data
+
+		return 0, io.EOF
+	}
+
+	// Shrink the buffer if is too large, keeping the protected bytes
+	if sr.buffer.Len() > int(sr.bufferSizeFactor)*sr.maxBufferSize {
+		trim := sr.buffer.Len() - sr.maxBufferSize
+		if sr.protected && sr.protectedOffset-sr.bufferStartOffset < trim {
+			trim = sr.protectedOffset - sr.bufferStartOffset
+		}
+
+		if trim > 0 {
+			sr.buffer = *bytes.NewBuffer(sr.buffer.Bytes()[trim:])
+			sr.bufferStartOffset += trim
+		}
+	}
+
+	return sr.currentReader.Read(p)
+}
+
+// Stop forces the next Read() of the StoppableReader to return EOF, but
+// additional Read() operations will continue to read bytes as usual.
+func (sr *StoppableReader) Stop() {
+	sr.mu.Lock()
+	sr.pauseRead = true
+	sr.mu.Unlock()
+}
+
+// ProtectOffset keeps the bytes from the specified offset onwards from
+// being pruned from the buffer, so that a later Rewind() to the offset
+// succeeds however much is read in the meantime. Only one offset is
+// protected at a time; calling it again replaces the previous offset.
+// Offsets that were already pruned cannot be recovered.
+func (sr *StoppableReader) ProtectOffset(offset int) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	sr.protectedOffset = offset
+	sr.protected = true
+}
+
+// UnprotectOffset removes the offset set by ProtectOffset(), allowing the
+// buffer to be pruned down to the maximum buffer size again.
+func (sr *StoppableReader) UnprotectOffset() {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	sr.protected = false
+}
+
+// Rewind returns a new Reader that starts at the specified byte offset.
+// This may allow data that had been previously read to be re-read.
+// If the StoppableAudioReader has a maximum buffer size and data
+// was not fully retained for the specified offset an error will be
+// returned, but a reader with as much buffered context as possible
+// will still be returned (what to do in this situation is up to
+// the caller).
+// if resetTimeZero is true, then future "offset" values sent to Rewing()
+// will consider the start of the last rewound stream to be zero, otherwise
+// the "zero time" will remain the start of the original input stream. The
+// protected offset, if any, keeps pointing to the same byte either way.
+func (sr *StoppableReader) Rewind(offset int, resetTimeZero bool) error {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	var (
+		err            error
+		adjustedOffset int
+	)
+
+	if offset < sr.bufferStartOffset {
+		// Offset value is before the start of the buffer, data returned will be incomplete.
+		adjustedOffset = 0
+		err = fmt.Errorf("StoppableAudioReader::Rewind() error, the requested offset %d is smaller that the start of the buffer: %d",
+			offset, sr.bufferStartOffset)
+	} else if offset > (sr.bufferStartOffset + sr.buffer.Len()) {
+		// Offset value is after the end of the buffer (impossible to buffer future data).
+		err = fmt.Errorf(
+			"StoppableAudioReader::Rewind() error, the requested offset %d is larger that the end of the buffer (in the future!): %d",
+			offset, sr.bufferStartOffset+sr.buffer.Len())
+		sr.currentReader = sr.appendReader
+
+		return err
+	} else {
+		// The requested offset is in the current buffer.
+		adjustedOffset = offset - sr.bufferStartOffset
+	}
+
+	// Return a MultiReader that will first read bytes from a snapshot of the buffer,
+	// and will read from the wrapped Reader afterwards (and will continue to add to the
+	// buffer when reading new data). The snapshot is a copy so that the buffer can be
+	// appended to or trimmed while the rewound data is read, which allows Rewind() to
+	// be called again without a Reset().
+	snapshot := make([]byte, sr.buffer.Len()-adjustedOffset)
+	copy(snapshot, sr.buffer.Bytes()[adjustedOffset:])
+	sr.currentReader = io.MultiReader(bytes.NewReader(snapshot), sr.appendReader)
+
+	// If the flag is set to consider the start of the rewound reader to be the new time
+	// zero, adjust the buffer start offset.  This will be a value <=0 because the data
+	// at the start of the buffer will be *before* the new time zero.
+	if resetTimeZero {
+		sr.protectedOffset -= sr.bufferStartOffset + adjustedOffset
+		sr.bufferStartOffset = -adjustedOffset
+	}
+
+	return err
+}
+
+// Reset clears the buffered data.
+func (sr *StoppableReader) Reset() {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	sr.buffer.Reset()
+
+	sr.appendReader = io.TeeReader(sr.origReader, &sr.buffer)
+	sr.currentReader = sr.appendReader
+	sr.bufferStartOffset = 0
+	sr.protected = false
+	sr.pauseRead = false
+}
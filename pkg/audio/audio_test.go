@@ -0,0 +1,472 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileRecorder(t *testing.T) {
+	t.Parallel()
+
+	samples := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	dir := t.TempDir()
+
+	testList := []struct {
+		name string
+		file []byte
+	}{
+		{"rec.raw", samples},
+		{"rec.wav", wavFile(fmtChunk(1, 16000, 16), wavChunk("data", samples))},
+	}
+
+	for i := range testList {
+		test := testList[i]
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(dir, test.name)
+			if err := os.WriteFile(path, test.file, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			rec := NewRecorder(Config{SourceFile: path})
+			if err := rec.Start(); err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := io.ReadAll(&rec)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(actual, samples) {
+				t.Errorf("incorrect audio - expected: %v, actual: %v", samples, actual)
+			}
+
+			rec.Stop()
+
+			if _, err := rec.Read(make([]byte, 1)); err == nil || err.Error() != "recorder application is not running" {
+				t.Errorf("expected the not running error after Stop, got: %v", err)
+			}
+
+			// The recorder can be started again, from the start of the file.
+			if err := rec.Start(); err != nil {
+				t.Fatal(err)
+			}
+
+			defer rec.Stop()
+
+			if actual, err := io.ReadAll(rec.Output()); err != nil || !bytes.Equal(actual, samples) {
+				t.Errorf("incorrect audio after restart - expected: %v, actual: %v (err: %v)", samples, actual, err)
+			}
+		})
+	}
+}
+
+func TestFileRecorderMissingFile(t *testing.T) {
+	t.Parallel()
+
+	rec := NewRecorder(Config{SourceFile: filepath.Join(t.TempDir(), "missing.raw")})
+	if err := rec.Start(); err == nil {
+		t.Error("expected an error for a missing source file")
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	app := filepath.Join(dir, "rec")
+
+	if err := os.WriteFile(app, nil, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	testList := []struct {
+		app   string
+		valid bool
+	}{
+		{"", true},
+		{app, true},
+		{"sh", true},
+		{dir, false},
+		{filepath.Join(dir, "missing"), false},
+		{"no-such-audio-app", false},
+	}
+
+	for _, test := range testList {
+		cfg := Config{Application: test.app}
+		if err := cfg.Validate(); (err == nil) != test.valid {
+			t.Errorf("application %q: expected valid=%t, got error: %v", test.app, test.valid, err)
+		}
+	}
+}
+
+func TestRecorderCapture(t *testing.T) {
+	t.Parallel()
+
+	samples := testBytes(1000)
+	dir := t.TempDir()
+	source := filepath.Join(dir, "rec.raw")
+	capture := filepath.Join(dir, "capture.wav")
+
+	if err := os.WriteFile(source, samples, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := NewRecorder(Config{SourceFile: source, CapturePath: capture, SampleRate: 8000})
+	if err := rec.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := io.ReadAll(rec.Output()); err != nil {
+		t.Fatal(err)
+	}
+
+	rec.Stop()
+
+	if err := rec.CaptureErr(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(capture)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	info, r, err := ParseWAVHeader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := WAVInfo{AudioFormat: 1, Channels: 1, SampleRate: 8000, BitsPerSample: 16, DataSize: uint32(len(samples))}
+	if info != expected {
+		t.Errorf("incorrect WAV info - expected: %+v, actual: %+v", expected, info)
+	}
+
+	if data, err := io.ReadAll(r); err != nil || !bytes.Equal(data, samples) {
+		t.Errorf("incorrect captured audio (err: %v)", err)
+	}
+
+	st, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The RIFF size covers everything after the RIFF size field.
+	header := make([]byte, 8)
+	if _, err := f.ReadAt(header, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if riffSize := int64(binary.LittleEndian.Uint32(header[4:])); riffSize != st.Size()-8 {
+		t.Errorf("incorrect RIFF size - expected: %d, actual: %d", st.Size()-8, riffSize)
+	}
+}
+
+func TestPlayerStop(t *testing.T) {
+	t.Parallel()
+
+	p := NewPlayer(Config{Application: "cat"})
+	if err := p.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.PushAudio([]byte{1, 2, 3, 4}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Stop(); err != nil {
+		t.Errorf("unexpected error stopping the player: %v", err)
+	}
+}
+
+func TestPlayerStopError(t *testing.T) {
+	t.Parallel()
+
+	// The script prints more stderr lines than are kept, then fails.
+	script := filepath.Join(t.TempDir(), "play.sh")
+	body := "#!/bin/sh\ncat >/dev/null\nfor i in $(seq 1 12); do echo line $i >&2; done\nexit 3\n"
+
+	if err := os.WriteFile(script, []byte(body), 0o700); err != nil { //nolint:gosec // test script
+		t.Fatal(err)
+	}
+
+	p := NewPlayer(Config{Application: script})
+	if err := p.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	err := p.Stop()
+
+	var perr *PlaybackError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected a *PlaybackError, got: %v", err)
+	}
+
+	if perr.ExitCode != 3 {
+		t.Errorf("incorrect exit code - expected: 3, actual: %d", perr.ExitCode)
+	}
+
+	lines := strings.Split(perr.Stderr, "\n")
+	if len(lines) != playerStderrLines || lines[0] != "line 3" || lines[len(lines)-1] != "line 12" {
+		t.Errorf("incorrect stderr lines: %q", lines)
+	}
+}
+
+func TestPlayerStartContextCancel(t *testing.T) {
+	t.Parallel()
+
+	// sleep ignores its stdin, so it only exits early if it is killed.
+	p := NewPlayer(Config{Application: "sleep", Args: "30"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := p.StartContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- p.Stop() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error stopping a cancelled player: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("player was not killed when the context was cancelled")
+	}
+}
+
+// recorderErr waits for the recorder's application to exit and returns the
+// error it reported, if any.
+func recorderErr(t *testing.T, rec *Recorder) error {
+	t.Helper()
+
+	select {
+	case err := <-rec.Err():
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("recorder error channel was not closed")
+	}
+
+	return nil
+}
+
+func TestRecorderErr(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		name    string
+		cfg     Config
+		stop    bool   // stop the recorder before it exits on its own
+		errText string // expected in the error, empty for no error
+	}{
+		{"failure", Config{Application: "ls", Args: "/nonexistent-recording-device"}, false, "nonexistent-recording-device"},
+		{"success", Config{Application: "true"}, false, ""},
+		{"stopped", Config{Application: "sleep", Args: "30"}, true, ""},
+	}
+
+	for i := range testList {
+		test := testList[i]
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := NewRecorder(test.cfg)
+			if err := rec.Start(); err != nil {
+				t.Fatal(err)
+			}
+
+			if !test.stop {
+				if _, err := io.ReadAll(rec.Output()); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			rec.Stop()
+
+			err := recorderErr(t, &rec)
+
+			switch {
+			case test.errText == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case test.errText != "" && (err == nil || !strings.Contains(err.Error(), test.errText)):
+				t.Errorf("expected an error containing %q, got: %v", test.errText, err)
+			}
+		})
+	}
+}
+
+// testBytes returns n bytes with distinct values (modulo 256).
+func testBytes(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i)
+	}
+
+	return b
+}
+
+// readN reads exactly n bytes from r.
+func readN(t *testing.T, r io.Reader, n int) []byte {
+	t.Helper()
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func TestStoppableReaderRepeatedRewind(t *testing.T) {
+	t.Parallel()
+
+	data := testBytes(1000)
+	sr := NewStoppableReader(bytes.NewReader(data), 1000)
+
+	readN(t, sr, 100)
+
+	// Rewind to the same offset twice, then to an earlier one, without
+	// a Reset() in between.
+	for _, offset := range []int{40, 40, 20} {
+		if err := sr.Rewind(offset, false); err != nil {
+			t.Fatal(err)
+		}
+
+		if actual := readN(t, sr, 30); !bytes.Equal(actual, data[offset:offset+30]) {
+			t.Errorf("rewind to %d - expected: %v, actual: %v", offset, data[offset:offset+30], actual)
+		}
+	}
+
+	// Reading past the rewound data continues with new data.
+	if err := sr.Rewind(90, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual := readN(t, sr, 20); !bytes.Equal(actual, data[90:110]) {
+		t.Errorf("expected: %v, actual: %v", data[90:110], actual)
+	}
+}
+
+func TestStoppableReaderRewindResetTimeZero(t *testing.T) {
+	t.Parallel()
+
+	data := testBytes(1000)
+	sr := NewStoppableReader(bytes.NewReader(data), 1000)
+
+	readN(t, sr, 100)
+
+	// Offset 40 becomes the new time zero.
+	if err := sr.Rewind(40, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual := readN(t, sr, 80); !bytes.Equal(actual, data[40:120]) {
+		t.Errorf("expected: %v, actual: %v", data[40:120], actual)
+	}
+
+	// Offset 10 relative to the new time zero is offset 50 of the input.
+	if err := sr.Rewind(10, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual := readN(t, sr, 100); !bytes.Equal(actual, data[50:150]) {
+		t.Errorf("expected: %v, actual: %v", data[50:150], actual)
+	}
+}
+
+func TestStoppableReaderProtectOffset(t *testing.T) {
+	t.Parallel()
+
+	const maxBufferSize = 100
+
+	data := testBytes(2000)
+	sr := NewStoppableReader(bytes.NewReader(data), maxBufferSize)
+	unprotected := NewStoppableReader(bytes.NewReader(data), maxBufferSize)
+
+	sr.ProtectOffset(50)
+
+	// Read well past bufferSizeFactor*maxBufferSize in small chunks, so
+	// the buffer is trimmed repeatedly.
+	for i := 0; i < 100; i++ {
+		readN(t, sr, 10)
+		readN(t, unprotected, 10)
+	}
+
+	if err := unprotected.Rewind(50, false); err == nil {
+		t.Error("expected an error for a trimmed offset")
+	}
+
+	if err := sr.Rewind(50, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual := readN(t, sr, 30); !bytes.Equal(actual, data[50:80]) {
+		t.Errorf("expected: %v, actual: %v", data[50:80], actual)
+	}
+
+	// Once unprotected, the buffer is trimmed again.
+	sr.UnprotectOffset()
+	readN(t, sr, 10)
+
+	if err := sr.Rewind(50, false); err == nil {
+		t.Error("expected an error once the offset is unprotected")
+	}
+}
+
+func TestStoppableReaderProtectOffsetResetTimeZero(t *testing.T) {
+	t.Parallel()
+
+	data := testBytes(2000)
+	sr := NewStoppableReader(bytes.NewReader(data), 100)
+
+	readN(t, sr, 100)
+	sr.ProtectOffset(60)
+
+	// Offset 40 becomes the new time zero, so the protected byte is now
+	// at offset 20.
+	if err := sr.Rewind(40, true); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 100; i++ {
+		readN(t, sr, 10)
+	}
+
+	if err := sr.Rewind(20, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual := readN(t, sr, 30); !bytes.Equal(actual, data[60:90]) {
+		t.Errorf("expected: %v, actual: %v", data[60:90], actual)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"io"
+)
+
+const (
+	// clipLevel is the magnitude at or above which a sample is
+	// considered clipped (99% of full scale).
+	clipLevel = 32439
+
+	// minClipSamples is the number of samples to read before clipping
+	// is reported, so a single loud sample at the start does not
+	// trigger the warning.
+	minClipSamples = 8000
+)
+
+// clipDetector counts the samples at or near full scale.
+type clipDetector struct {
+	thresholdPercent float64
+	onClip           func(percent float64)
+
+	total   int
+	clipped int
+	warned  bool
+}
+
+// NewClipDetector returns an io.Reader that passes through the 16-bit little
+// endian PCM audio read from r unchanged, while counting the samples at or
+// near full scale. Once clipped samples make up at least thresholdPercent of
+// the audio read so far, onClip is called with that percentage. It is only
+// called once per reader.
+func NewClipDetector(r io.Reader, thresholdPercent float64, onClip func(percent float64)) io.Reader {
+	cd := &clipDetector{
+		thresholdPercent: thresholdPercent,
+		onClip:           onClip,
+	}
+
+	return newPCMReader(r, bytesPerSample, cd.process)
+}
+
+func (cd *clipDetector) process(b []byte) []byte {
+	if cd.warned {
+		return b
+	}
+
+	for i := 0; i < len(b)/bytesPerSample; i++ {
+		if s := sampleAt(b, i); s >= clipLevel || s <= -clipLevel {
+			cd.clipped++
+		}
+	}
+
+	cd.total += len(b) / bytesPerSample
+
+	if cd.total >= minClipSamples {
+		percent := 100 * float64(cd.clipped) / float64(cd.total) //nolint:gomnd // converting to a percentage
+		if percent >= cd.thresholdPercent {
+			cd.warned = true
+			cd.onClip(percent)
+		}
+	}
+
+	return b
+}
@@ -0,0 +1,72 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"testing"
+	"testing/iotest"
+)
+
+func TestClipDetector(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		name      string
+		amplitude float64
+		threshold float64
+		warn      bool
+	}{
+		// A full scale sine wave spends a few percent of its time
+		// above 99% of full scale.
+		{"clipped", math.MaxInt16, 1, true},
+		{"clipped below threshold", math.MaxInt16, 50, false},
+		{"quiet", 0.5 * math.MaxInt16, 1, false},
+	}
+
+	for i := range testList {
+		test := testList[i]
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := sineWave(440, test.amplitude, 1)
+			warnings := 0
+
+			r := NewClipDetector(iotest.HalfReader(bytes.NewReader(input)), test.threshold, func(percent float64) {
+				warnings++
+
+				if percent < test.threshold {
+					t.Errorf("warning reported %.2f%% clipping, below the %.2f%% threshold", percent, test.threshold)
+				}
+			})
+
+			output, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(output, input) {
+				t.Error("clip detector modified the audio")
+			}
+
+			if expected := map[bool]int{true: 1, false: 0}[test.warn]; warnings != expected {
+				t.Errorf("incorrect number of warnings - expected: %d, actual: %d", expected, warnings)
+			}
+		})
+	}
+}
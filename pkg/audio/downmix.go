@@ -0,0 +1,42 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"io"
+)
+
+// stereoFrameSize is the size of a single 16-bit stereo frame.
+const stereoFrameSize = 2 * bytesPerSample
+
+// DownmixStereo returns an io.Reader that converts the interleaved 16-bit
+// little endian stereo PCM audio read from r to mono by averaging the left
+// and right samples of each frame.
+func DownmixStereo(r io.Reader) io.Reader {
+	return newPCMReader(r, stereoFrameSize, downmix)
+}
+
+func downmix(b []byte) []byte {
+	n := len(b) / stereoFrameSize
+	out := make([]byte, n*bytesPerSample)
+
+	for i := 0; i < n; i++ {
+		left := int(sampleAt(b, 2*i))
+		right := int(sampleAt(b, 2*i+1))
+		setSampleAt(out, i, int16((left+right)/2)) //nolint:gomnd // average of two channels
+	}
+
+	return out
+}
@@ -0,0 +1,57 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"testing"
+	"testing/iotest"
+)
+
+func TestDownmixStereo(t *testing.T) {
+	t.Parallel()
+
+	stereo := []int16{100, 200, -100, -300, math.MaxInt16, math.MaxInt16, math.MinInt16, math.MaxInt16}
+	expected := []int16{150, -200, math.MaxInt16, 0}
+
+	in := make([]byte, len(stereo)*bytesPerSample)
+	for i, s := range stereo {
+		setSampleAt(in, i, s)
+	}
+
+	// Reading a byte at a time splits every frame across reads.
+	for name, r := range map[string]io.Reader{
+		"whole":    bytes.NewReader(in),
+		"one byte": iotest.OneByteReader(bytes.NewReader(in)),
+	} {
+		out, err := io.ReadAll(DownmixStereo(r))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		actual := samples(out)
+		if len(actual) != len(expected) {
+			t.Fatalf("%s: incorrect number of samples - expected: %d, actual: %d", name, len(expected), len(actual))
+		}
+
+		for i := range expected {
+			if actual[i] != expected[i] {
+				t.Errorf("%s: sample %d - expected: %d, actual: %d", name, i, expected[i], actual[i])
+			}
+		}
+	}
+}
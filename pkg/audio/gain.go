@@ -0,0 +1,53 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"io"
+)
+
+// gainWriter is an io.Writer that multiplies the 16-bit little endian PCM
+// samples written to it by a gain factor before writing them to a wrapped
+// writer. Since a sample may be split across two Write calls, a trailing odd
+// byte is held back until the next Write.
+type gainWriter struct {
+	w       io.Writer
+	gain    float64
+	partial []byte // first byte of a split sample, if any
+	buf     []byte
+}
+
+func newGainWriter(w io.Writer, gain float64) *gainWriter {
+	return &gainWriter{w: w, gain: gain}
+}
+
+// Write applies the gain to p and writes the complete samples.
+func (gw *gainWriter) Write(p []byte) (int, error) {
+	gw.buf = append(append(gw.buf[:0], gw.partial...), p...)
+
+	complete := len(gw.buf) - len(gw.buf)%bytesPerSample
+	gw.partial = append(gw.partial[:0], gw.buf[complete:]...)
+
+	b := gw.buf[:complete]
+	for i := 0; i < len(b)/bytesPerSample; i++ {
+		setSampleAt(b, i, clampSample(float64(sampleAt(b, i))*gw.gain))
+	}
+
+	if _, err := gw.w.Write(b); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
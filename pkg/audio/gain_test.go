@@ -0,0 +1,67 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestGainWriter(t *testing.T) {
+	t.Parallel()
+
+	input := []int16{100, -100, 20000, -20000, math.MaxInt16, 0}
+	expected := []int16{200, -200, math.MaxInt16, math.MinInt16, math.MaxInt16, 0}
+
+	b := make([]byte, len(input)*bytesPerSample)
+	for i, s := range input {
+		setSampleAt(b, i, s)
+	}
+
+	// Write in chunks of odd sizes so samples are split across writes.
+	for _, chunk := range []int{1, 3, len(b)} {
+		var out bytes.Buffer
+
+		gw := newGainWriter(&out, 2)
+
+		for i := 0; i < len(b); i += chunk {
+			end := i + chunk
+			if end > len(b) {
+				end = len(b)
+			}
+
+			n, err := gw.Write(b[i:end])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if n != end-i {
+				t.Errorf("chunk %d: incorrect write count - expected: %d, actual: %d", chunk, end-i, n)
+			}
+		}
+
+		actual := samples(out.Bytes())
+		if len(actual) != len(expected) {
+			t.Fatalf("chunk %d: incorrect number of samples - expected: %d, actual: %d", chunk, len(expected), len(actual))
+		}
+
+		for i := range expected {
+			if actual[i] != expected[i] {
+				t.Errorf("chunk %d: sample %d - expected: %d, actual: %d", chunk, i, expected[i], actual[i])
+			}
+		}
+	}
+}
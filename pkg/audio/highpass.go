@@ -0,0 +1,66 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// highPass is a first-order high-pass filter for 16-bit PCM audio.
+type highPass struct {
+	alpha float64
+	prevX float64 // previous input sample
+	prevY float64 // previous output sample
+}
+
+// NewHighPass returns an io.Reader that applies a first-order high-pass
+// filter to the 16-bit little endian mono PCM audio read from r. This
+// removes any DC offset and attenuates low-frequency hum below cutoffHz.
+// The filter state is kept across Read calls. The cutoff and sample rate
+// must be positive.
+func NewHighPass(r io.Reader, cutoffHz, sampleRate int) (io.Reader, error) {
+	if cutoffHz <= 0 {
+		return nil, fmt.Errorf("invalid high-pass cutoff %d Hz, must be positive", cutoffHz)
+	}
+
+	if sampleRate <= 0 {
+		return nil, fmt.Errorf("invalid sample rate %d Hz, must be positive", sampleRate)
+	}
+
+	rc := 1 / (2 * math.Pi * float64(cutoffHz))
+	dt := 1 / float64(sampleRate)
+
+	hp := &highPass{
+		alpha: rc / (rc + dt),
+	}
+
+	return newPCMReader(r, bytesPerSample, hp.process), nil
+}
+
+func (hp *highPass) process(b []byte) []byte {
+	for i := 0; i < len(b)/bytesPerSample; i++ {
+		x := float64(sampleAt(b, i))
+		y := hp.alpha * (hp.prevY + x - hp.prevX)
+
+		hp.prevX = x
+		hp.prevY = y
+
+		setSampleAt(b, i, clampSample(y))
+	}
+
+	return b
+}
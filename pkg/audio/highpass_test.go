@@ -0,0 +1,81 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+	"testing/iotest"
+)
+
+func TestHighPass(t *testing.T) {
+	t.Parallel()
+
+	const offset = 2000
+
+	// A sine wave with a DC offset.
+	in := sineWave(440, 1000, 2)
+	for i := 0; i < len(in)/bytesPerSample; i++ {
+		s := int16(binary.LittleEndian.Uint16(in[i*bytesPerSample:])) + offset
+		binary.LittleEndian.PutUint16(in[i*bytesPerSample:], uint16(s))
+	}
+
+	if mean := meanSample(samples(in)); math.Abs(mean-offset) > 10 {
+		t.Fatalf("unexpected input mean: %v", mean)
+	}
+
+	hp, err := NewHighPass(iotest.OneByteReader(bytes.NewReader(in)), 50, testSampleRate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := io.ReadAll(hp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(out) != len(in) {
+		t.Fatalf("incorrect output length - expected: %d, actual: %d", len(in), len(out))
+	}
+
+	// After the filter settles, the mean should be close to zero.
+	s := samples(out)
+	if mean := meanSample(s[len(s)/2:]); math.Abs(mean) > 10 {
+		t.Errorf("DC offset was not removed - mean: %v", mean)
+	}
+}
+
+func TestHighPassInvalid(t *testing.T) {
+	t.Parallel()
+
+	// A zero cutoff or sample rate would give a NaN filter coefficient.
+	for _, args := range [][2]int{{0, testSampleRate}, {-50, testSampleRate}, {50, 0}, {50, -1}} {
+		if _, err := NewHighPass(bytes.NewReader(nil), args[0], args[1]); err == nil {
+			t.Errorf("expected an error for cutoff %d Hz and sample rate %d Hz", args[0], args[1])
+		}
+	}
+}
+
+func meanSample(s []int16) float64 {
+	var sum float64
+	for _, v := range s {
+		sum += float64(v)
+	}
+
+	return sum / float64(len(s))
+}
@@ -0,0 +1,88 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"io"
+	"math"
+)
+
+// LevelMeter is an io.Reader that passes through 16-bit little endian PCM
+// audio unchanged while measuring its RMS and peak levels.
+type LevelMeter struct {
+	r io.Reader
+
+	samples    int
+	sumSquares float64
+	peak       int
+}
+
+// NewLevelMeter returns a LevelMeter that measures the audio read from r.
+func NewLevelMeter(r io.Reader) *LevelMeter {
+	lm := &LevelMeter{}
+	lm.r = newPCMReader(r, bytesPerSample, lm.process)
+
+	return lm
+}
+
+// Read audio from the wrapped reader into p.
+func (lm *LevelMeter) Read(p []byte) (int, error) {
+	return lm.r.Read(p)
+}
+
+func (lm *LevelMeter) process(b []byte) []byte {
+	for i := 0; i < len(b)/bytesPerSample; i++ {
+		s := int(sampleAt(b, i))
+		if s < 0 {
+			s = -s
+		}
+
+		if s > lm.peak {
+			lm.peak = s
+		}
+
+		lm.sumSquares += float64(s) * float64(s)
+	}
+
+	lm.samples += len(b) / bytesPerSample
+
+	return b
+}
+
+// Samples returns the number of samples read so far.
+func (lm *LevelMeter) Samples() int {
+	return lm.samples
+}
+
+// RMSDBFS returns the RMS level of the audio read so far in dBFS. It is
+// negative infinity if no audio was read or the audio is digital silence.
+func (lm *LevelMeter) RMSDBFS() float64 {
+	if lm.samples == 0 {
+		return math.Inf(-1)
+	}
+
+	return toDBFS(math.Sqrt(lm.sumSquares / float64(lm.samples)))
+}
+
+// PeakDBFS returns the peak level of the audio read so far in dBFS. It is
+// negative infinity if no audio was read or the audio is digital silence.
+func (lm *LevelMeter) PeakDBFS() float64 {
+	return toDBFS(float64(lm.peak))
+}
+
+// toDBFS converts a sample magnitude to dBFS.
+func toDBFS(v float64) float64 {
+	return 20 * math.Log10(v/math.MaxInt16) //nolint:gomnd // decibel conversion
+}
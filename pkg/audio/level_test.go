@@ -0,0 +1,69 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestLevelMeter(t *testing.T) {
+	t.Parallel()
+
+	input := []int16{16384, -16384, 16384, -16384}
+
+	b := make([]byte, len(input)*bytesPerSample)
+	for i, s := range input {
+		setSampleAt(b, i, s)
+	}
+
+	lm := NewLevelMeter(bytes.NewReader(b))
+
+	out, err := io.ReadAll(lm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(out, b) {
+		t.Error("audio was modified by the level meter")
+	}
+
+	if lm.Samples() != len(input) {
+		t.Errorf("incorrect sample count - expected: %d, actual: %d", len(input), lm.Samples())
+	}
+
+	// Half of full scale is about -6 dBFS.
+	for name, level := range map[string]float64{"rms": lm.RMSDBFS(), "peak": lm.PeakDBFS()} {
+		if math.Abs(level-(-6.02)) > 0.01 {
+			t.Errorf("incorrect %s level - expected: -6.02, actual: %.2f", name, level)
+		}
+	}
+}
+
+func TestLevelMeterSilence(t *testing.T) {
+	t.Parallel()
+
+	lm := NewLevelMeter(bytes.NewReader(make([]byte, 100)))
+
+	if _, err := io.ReadAll(lm); err != nil {
+		t.Fatal(err)
+	}
+
+	if !math.IsInf(lm.RMSDBFS(), -1) || !math.IsInf(lm.PeakDBFS(), -1) {
+		t.Errorf("expected silence, got rms: %v, peak: %v", lm.RMSDBFS(), lm.PeakDBFS())
+	}
+}
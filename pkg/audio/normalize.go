@@ -0,0 +1,107 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"io"
+	"math"
+)
+
+const (
+	// normalizerWindowSize is the number of samples used to measure the
+	// RMS level of the audio.
+	normalizerWindowSize = 4096
+
+	// normalizerMaxGainDB is the largest gain applied to quiet audio.
+	normalizerMaxGainDB = 30.0
+
+	// normalizerSilenceDBFS is the RMS level below which the audio is
+	// considered to be silence and is not amplified.
+	normalizerSilenceDBFS = -60.0
+
+	// normalizerSmoothing controls how quickly the gain moves toward the
+	// desired gain for each sample.
+	normalizerSmoothing = 0.001
+)
+
+// normalizer applies automatic gain to 16-bit PCM audio so that its RMS
+// level approaches a target level.
+type normalizer struct {
+	targetRMS  float64
+	silenceRMS float64
+	maxGain    float64
+	gain       float64
+
+	window     [normalizerWindowSize]float64 // squared samples in the window
+	pos        int                           // next position in the window
+	count      int                           // number of samples in the window
+	sumSquares float64                       // sum of the squared samples in the window
+}
+
+// NewNormalizer returns an io.Reader that applies automatic gain to the
+// 16-bit little endian mono PCM audio read from r, moving its RMS level
+// (measured over a sliding window) toward targetDBFS (e.g., -20). The gain
+// is limited so that quiet audio isn't amplified by more than 30 dB, audio
+// quieter than -60 dBFS is treated as silence and left alone, and samples
+// never clip.
+func NewNormalizer(r io.Reader, targetDBFS float64) io.Reader {
+	n := &normalizer{
+		targetRMS:  dbfsToRMS(targetDBFS),
+		silenceRMS: dbfsToRMS(normalizerSilenceDBFS),
+		maxGain:    math.Pow(10, normalizerMaxGainDB/20), //nolint:gomnd // decibel conversion
+		gain:       1.0,
+	}
+
+	return newPCMReader(r, bytesPerSample, n.process)
+}
+
+func (n *normalizer) process(b []byte) []byte {
+	for i := 0; i < len(b)/bytesPerSample; i++ {
+		s := float64(sampleAt(b, i))
+
+		// Update the sliding window with the current sample.
+		sq := s * s
+		n.sumSquares += sq - n.window[n.pos]
+		n.window[n.pos] = sq
+		n.pos = (n.pos + 1) % normalizerWindowSize
+
+		if n.count < normalizerWindowSize {
+			n.count++
+		}
+
+		// Move the gain toward the gain required to reach the target level.
+		desired := 1.0
+		if rms := math.Sqrt(math.Max(n.sumSquares, 0) / float64(n.count)); rms > n.silenceRMS {
+			desired = math.Min(n.targetRMS/rms, n.maxGain)
+		}
+
+		n.gain += (desired - n.gain) * normalizerSmoothing
+
+		// Limit the gain of this sample so it does not clip.
+		gain := n.gain
+		if peak := math.Abs(s) * gain; peak > math.MaxInt16 {
+			gain = math.MaxInt16 / math.Abs(s)
+		}
+
+		setSampleAt(b, i, clampSample(s*gain))
+	}
+
+	return b
+}
+
+// dbfsToRMS converts a level in dBFS to the equivalent RMS of 16-bit samples.
+func dbfsToRMS(dbfs float64) float64 {
+	return math.MaxInt16 * math.Pow(10, dbfs/20) //nolint:gomnd // decibel conversion
+}
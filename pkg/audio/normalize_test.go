@@ -0,0 +1,106 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+	"testing/iotest"
+)
+
+const testSampleRate = 16000
+
+// sineWave returns the given number of seconds of a 16-bit little endian
+// sine wave with the given frequency and amplitude.
+func sineWave(freq, amplitude, seconds float64) []byte {
+	n := int(seconds * testSampleRate)
+	b := make([]byte, n*bytesPerSample)
+
+	for i := 0; i < n; i++ {
+		s := amplitude * math.Sin(2*math.Pi*freq*float64(i)/testSampleRate)
+		binary.LittleEndian.PutUint16(b[i*bytesPerSample:], uint16(int16(s)))
+	}
+
+	return b
+}
+
+// samples decodes 16-bit little endian PCM audio.
+func samples(b []byte) []int16 {
+	s := make([]int16, len(b)/bytesPerSample)
+	for i := range s {
+		s[i] = int16(binary.LittleEndian.Uint16(b[i*bytesPerSample:]))
+	}
+
+	return s
+}
+
+func rmsDBFS(s []int16) float64 {
+	var sum float64
+	for _, v := range s {
+		sum += float64(v) * float64(v)
+	}
+
+	return 20 * math.Log10(math.Sqrt(sum/float64(len(s)))/math.MaxInt16)
+}
+
+func TestNormalizer(t *testing.T) {
+	t.Parallel()
+
+	const target = -20.0
+
+	// A quiet sine wave at roughly -44 dBFS.
+	in := sineWave(440, 300, 3)
+
+	// Use a reader that returns odd-sized reads so that samples are split
+	// across Read calls.
+	out, err := io.ReadAll(NewNormalizer(iotest.HalfReader(bytes.NewReader(in)), target))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(out) != len(in) {
+		t.Fatalf("incorrect output length - expected: %d, actual: %d", len(in), len(out))
+	}
+
+	s := samples(out)
+	for i, v := range s {
+		if v == math.MaxInt16 || v == math.MinInt16 {
+			t.Fatalf("sample %d is clipped", i)
+		}
+	}
+
+	// Measure the level of the last second, after the gain has settled.
+	if level := rmsDBFS(s[len(s)-testSampleRate:]); math.Abs(level-target) > 1 {
+		t.Errorf("incorrect output level - expected: %v dBFS, actual: %.2f dBFS", target, level)
+	}
+}
+
+func TestNormalizerSilence(t *testing.T) {
+	t.Parallel()
+
+	in := make([]byte, testSampleRate*bytesPerSample)
+
+	out, err := io.ReadAll(NewNormalizer(bytes.NewReader(in), -20))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(in, out) {
+		t.Errorf("silence was modified by the normalizer")
+	}
+}
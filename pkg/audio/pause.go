@@ -0,0 +1,172 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Values of Config.PauseMode.
+const (
+	PauseModeBuffer  = "buffer"
+	PauseModeDiscard = "discard"
+)
+
+// pauseBufferSize is the size of the reads made while paused.
+const pauseBufferSize = 4096
+
+// pausableReader wraps a reader that must keep being read, such as the
+// output of a recording application, so that reads can be paused. While
+// paused, a goroutine keeps reading from the wrapped reader and either
+// buffers the data, to be returned once resumed, or discards it. Since a
+// read that is blocked can't be interrupted, the data returned by the
+// goroutine's last read, which may complete after Resume, is handled the
+// same way.
+type pausableReader struct {
+	r       io.Reader
+	discard bool
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	paused   bool
+	draining bool          // the drain goroutine is running
+	drained  chan struct{} // closed when the drain goroutine exits
+	pending  []byte        // data buffered while paused
+	err      error         // error returned to the drain goroutine
+}
+
+// isDiscardPauseMode returns whether the given Config.PauseMode discards
+// the audio recorded while paused.
+func isDiscardPauseMode(mode string) (bool, error) {
+	switch mode {
+	case "", PauseModeBuffer:
+		return false, nil
+	case PauseModeDiscard:
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid pause mode %q, must be %s or %s", mode, PauseModeBuffer, PauseModeDiscard)
+	}
+}
+
+func newPausableReader(r io.Reader, discard bool) *pausableReader {
+	pr := &pausableReader{r: r, discard: discard}
+	pr.cond = sync.NewCond(&pr.mu)
+
+	return pr
+}
+
+// Pause makes Read block until Resume is called.
+func (pr *pausableReader) Pause() {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if pr.paused {
+		return
+	}
+
+	pr.paused = true
+
+	if !pr.draining && pr.err == nil {
+		pr.draining = true
+		pr.drained = make(chan struct{})
+
+		go pr.drain()
+	}
+}
+
+// Resume lets blocked and future Read calls continue.
+func (pr *pausableReader) Resume() {
+	pr.mu.Lock()
+	pr.paused = false
+	pr.cond.Broadcast()
+	pr.mu.Unlock()
+}
+
+// drain reads from the wrapped reader until resumed.
+func (pr *pausableReader) drain() {
+	buf := make([]byte, pauseBufferSize)
+
+	pr.mu.Lock()
+	defer func() {
+		pr.draining = false
+		close(pr.drained)
+		pr.mu.Unlock()
+	}()
+
+	for pr.paused {
+		pr.mu.Unlock()
+		n, err := pr.r.Read(buf)
+		pr.mu.Lock()
+
+		if !pr.discard {
+			pr.pending = append(pr.pending, buf[:n]...)
+		}
+
+		if err != nil {
+			pr.err = err
+			return
+		}
+	}
+}
+
+// Read blocks while paused, then returns any data buffered while paused
+// before reading from the wrapped reader again. A Read that is already in
+// progress when Pause is called completes normally.
+func (pr *pausableReader) Read(p []byte) (int, error) {
+	pr.mu.Lock()
+
+	for {
+		if pr.paused {
+			pr.cond.Wait()
+
+			continue
+		}
+
+		if pr.draining {
+			// Let the drain goroutine finish its last read so the data
+			// is returned in order.
+			drained := pr.drained
+
+			pr.mu.Unlock()
+			<-drained
+			pr.mu.Lock()
+
+			continue
+		}
+
+		break
+	}
+
+	if len(pr.pending) > 0 {
+		n := copy(p, pr.pending)
+		pr.pending = pr.pending[n:]
+		pr.mu.Unlock()
+
+		return n, nil
+	}
+
+	if pr.err != nil {
+		err := pr.err
+		pr.mu.Unlock()
+
+		return 0, err
+	}
+
+	pr.mu.Unlock()
+
+	return pr.r.Read(p)
+}
@@ -0,0 +1,129 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+func TestPausableReader(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		mode     string
+		expected string
+	}{
+		{PauseModeBuffer, "ab"},
+		{PauseModeDiscard, "a"},
+	}
+
+	for i := range testList {
+		test := testList[i]
+
+		t.Run(test.mode, func(t *testing.T) {
+			t.Parallel()
+
+			discard, err := isDiscardPauseMode(test.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			src, w := io.Pipe()
+			pr := newPausableReader(src, discard)
+
+			go w.Write([]byte("a"))
+
+			first := make([]byte, 1)
+			if _, err := pr.Read(first); err != nil {
+				t.Fatal(err)
+			}
+
+			// No Read is in progress, so "b" is read by the drain
+			// goroutine, and the write returns once it has been read.
+			pr.Pause()
+
+			if _, err := w.Write([]byte("b")); err != nil {
+				t.Fatal(err)
+			}
+
+			pr.Resume()
+			w.Close()
+
+			rest, err := io.ReadAll(pr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := string(first) + string(rest)
+
+			if actual != test.expected {
+				t.Errorf("incorrect data - expected: %q, actual: %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPausableReaderBlocks(t *testing.T) {
+	t.Parallel()
+
+	src, w := io.Pipe()
+	pr := newPausableReader(src, false)
+	pr.Pause()
+
+	// The drain goroutine keeps reading while paused.
+	if _, err := w.Write([]byte("x")); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan string)
+
+	go func() {
+		b := make([]byte, 1)
+		n, _ := pr.Read(b)
+		done <- string(b[:n])
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Read returned while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	pr.Resume()
+
+	// Unblock the drain goroutine's pending read.
+	go w.Write([]byte("y"))
+
+	select {
+	case actual := <-done:
+		if actual != "x" {
+			t.Errorf("incorrect data after resume - expected: %q, actual: %q", "x", actual)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Read did not return after Resume")
+	}
+}
+
+func TestInvalidPauseMode(t *testing.T) {
+	t.Parallel()
+
+	rec := NewRecorder(Config{Application: "cat", PauseMode: "rewind"})
+	if err := rec.Start(); err == nil {
+		rec.Stop()
+		t.Error("expected an error for an invalid pause mode")
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// bytesPerSample is the size of a single 16-bit PCM sample.
+const bytesPerSample = 2
+
+// pcmReader is an io.Reader that reads 16-bit little endian PCM audio from
+// a wrapped reader and passes it through a processing function. Since a
+// single Read from the wrapped reader may end in the middle of a frame, any
+// partial frame is held back until the rest of it has been read. The
+// processed output does not need to be the same length as the input.
+type pcmReader struct {
+	r         io.Reader
+	frameSize int                 // number of bytes in a single frame
+	process   func([]byte) []byte // process complete frames
+
+	buf     []byte // read buffer
+	partial []byte // unprocessed bytes of an incomplete frame
+	out     []byte // processed bytes not yet returned to the caller
+	err     error  // error returned by the wrapped reader
+}
+
+func newPCMReader(r io.Reader, frameSize int, process func([]byte) []byte) *pcmReader {
+	return &pcmReader{
+		r:         r,
+		frameSize: frameSize,
+		process:   process,
+	}
+}
+
+// Read processed audio into p.
+func (pr *pcmReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	for len(pr.out) == 0 {
+		if pr.err != nil {
+			// Any incomplete frame left at this point can't be processed
+			// and is dropped.
+			return 0, pr.err
+		}
+
+		if size := len(p) + pr.frameSize; cap(pr.buf) < size {
+			pr.buf = make([]byte, size)
+		}
+
+		n, err := pr.r.Read(pr.buf[:cap(pr.buf)])
+		pr.partial = append(pr.partial, pr.buf[:n]...)
+		pr.err = err
+
+		complete := len(pr.partial) - len(pr.partial)%pr.frameSize
+		if complete > 0 {
+			pr.out = append(pr.out, pr.process(pr.partial[:complete])...)
+			pr.partial = append(pr.partial[:0], pr.partial[complete:]...)
+		}
+	}
+
+	n := copy(p, pr.out)
+	pr.out = pr.out[n:]
+
+	return n, nil
+}
+
+// sampleAt returns the i-th 16-bit little endian sample in b.
+func sampleAt(b []byte, i int) int16 {
+	return int16(binary.LittleEndian.Uint16(b[i*bytesPerSample:]))
+}
+
+// setSampleAt sets the i-th 16-bit little endian sample in b.
+func setSampleAt(b []byte, i int, s int16) {
+	binary.LittleEndian.PutUint16(b[i*bytesPerSample:], uint16(s))
+}
+
+// clampSample rounds the given value to the nearest 16-bit sample, clipping
+// it to the range of an int16.
+func clampSample(v float64) int16 {
+	switch {
+	case v >= math.MaxInt16:
+		return math.MaxInt16
+	case v <= math.MinInt16:
+		return math.MinInt16
+	default:
+		return int16(math.Round(v))
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"fmt"
+	"io"
+)
+
+// resampler converts 16-bit PCM audio between sample rates using linear
+// interpolation. Output sample k is taken from input position
+// k*inRate/outRate, computed with integers so that it doesn't drift.
+type resampler struct {
+	inRate  int64
+	outRate int64
+
+	inBase  int64 // index of the first input sample in the current chunk
+	outNext int64 // index of the next output sample
+	prev    int16 // last input sample of the previous chunk
+}
+
+// NewResampler returns an io.Reader that resamples the 16-bit little endian
+// mono PCM audio read from r from inRate to outRate (e.g., 44100 to 16000)
+// using linear interpolation. If the rates are equal, r is returned as is.
+// It may be used to wrap Recorder.Output() when the recording device does
+// not support the sample rate of the model. Both rates must be positive.
+func NewResampler(r io.Reader, inRate, outRate int) (io.Reader, error) {
+	if inRate <= 0 || outRate <= 0 {
+		return nil, fmt.Errorf("invalid sample rates %d Hz to %d Hz, must be positive", inRate, outRate)
+	}
+
+	if inRate == outRate {
+		return r, nil
+	}
+
+	rs := &resampler{
+		inRate:  int64(inRate),
+		outRate: int64(outRate),
+	}
+
+	return newPCMReader(r, bytesPerSample, rs.process), nil
+}
+
+func (rs *resampler) process(b []byte) []byte {
+	n := int64(len(b) / bytesPerSample)
+	last := rs.inBase + n - 1 // index of the last input sample available
+
+	// sample returns input sample j, which is either in b or the last
+	// sample of the previous chunk.
+	sample := func(j int64) float64 {
+		if j < rs.inBase {
+			return float64(rs.prev)
+		}
+
+		return float64(sampleAt(b, int(j-rs.inBase)))
+	}
+
+	var out []byte
+
+	for {
+		pos := rs.outNext * rs.inRate
+		i := pos / rs.outRate
+
+		if i+1 > last {
+			if i == last && pos%rs.outRate == 0 {
+				// No interpolation needed, only sample i is used.
+				out = appendSample(out, clampSample(sample(i)))
+				rs.outNext++
+
+				continue
+			}
+
+			break
+		}
+
+		frac := float64(pos%rs.outRate) / float64(rs.outRate)
+		out = appendSample(out, clampSample(sample(i)+frac*(sample(i+1)-sample(i))))
+		rs.outNext++
+	}
+
+	if n > 0 {
+		rs.prev = sampleAt(b, int(n-1))
+		rs.inBase += n
+	}
+
+	return out
+}
+
+// appendSample appends a 16-bit little endian sample to b.
+func appendSample(b []byte, s int16) []byte {
+	return append(b, byte(s), byte(uint16(s)>>8)) //nolint:gomnd // little endian byte order
+}
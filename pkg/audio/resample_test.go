@@ -0,0 +1,107 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"testing"
+	"testing/iotest"
+)
+
+func TestResamplerPassThrough(t *testing.T) {
+	t.Parallel()
+
+	r := bytes.NewReader(nil)
+
+	rs, err := NewResampler(r, 16000, 16000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rs != io.Reader(r) {
+		t.Error("expected the reader to be returned as is for equal rates")
+	}
+}
+
+func TestResamplerInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, rates := range [][2]int{{0, 16000}, {44100, 0}, {-1, 16000}} {
+		if _, err := NewResampler(bytes.NewReader(nil), rates[0], rates[1]); err == nil {
+			t.Errorf("expected an error for rates %d Hz to %d Hz", rates[0], rates[1])
+		}
+	}
+}
+
+func TestResampler(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		name            string
+		inRate, outRate int
+	}{
+		{"integer downsample", 48000, 16000},
+		{"non-integer downsample", 44100, 16000},
+		{"upsample", 8000, 16000},
+	}
+
+	// Linear interpolation of a ramp is exact, so each output sample k
+	// should be the input position k*inRate/outRate, rounded.
+	const n = 4410
+
+	in := make([]byte, n*bytesPerSample)
+	for i := 0; i < n; i++ {
+		setSampleAt(in, i, int16(i))
+	}
+
+	for _, test := range testList {
+		for name, r := range map[string]io.Reader{
+			"whole":    bytes.NewReader(in),
+			"one byte": iotest.OneByteReader(bytes.NewReader(in)),
+		} {
+			rs, err := NewResampler(r, test.inRate, test.outRate)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := io.ReadAll(rs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := samples(out)
+
+			// Output samples up to the position of the last input sample.
+			expectedLen := (n-1)*test.outRate/test.inRate + 1
+			if len(actual) != expectedLen {
+				t.Errorf("%s (%s): incorrect number of samples - expected: %d, actual: %d",
+					test.name, name, expectedLen, len(actual))
+
+				continue
+			}
+
+			for k, s := range actual {
+				expected := int16(math.Round(float64(k) * float64(test.inRate) / float64(test.outRate)))
+				if s != expected {
+					t.Errorf("%s (%s): sample %d - expected: %d, actual: %d", test.name, name, k, expected, s)
+
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AudioSource is a source of recorded audio, such as a recording
+// application or an audio file, so that examples can accept either.
+type AudioSource interface {
+	io.Reader
+
+	// Start begins recording, or opens the file.
+	Start() error
+
+	// Stop ends recording, or closes the file, and returns any error
+	// that made the audio end early.
+	Stop() error
+}
+
+// NewSourceFromConfig returns a FileSource if cfg.SourceFile is set, and a
+// source that runs cfg.Application otherwise. Capturing and pausing only
+// apply to recording applications.
+func NewSourceFromConfig(cfg Config) AudioSource {
+	if cfg.SourceFile != "" {
+		return NewFileSource(cfg.SourceFile)
+	}
+
+	rec := NewRecorder(cfg)
+
+	return &RecorderSource{Recorder: &rec}
+}
+
+// RecorderSource adapts a Recorder to the AudioSource interface.
+type RecorderSource struct {
+	*Recorder
+}
+
+// Stop the recorder and return the error that made the recording
+// application exit abnormally, or the error writing the capture file.
+func (rs *RecorderSource) Stop() error {
+	rs.Recorder.Stop()
+
+	// Once stopped, the channel has been closed, if there is one.
+	if errCh := rs.Recorder.Err(); errCh != nil {
+		if err := <-errCh; err != nil {
+			return err
+		}
+	}
+
+	return rs.Recorder.CaptureErr()
+}
+
+// FileSource is an AudioSource that reads audio from a file. Raw files are
+// read as is, while the header of .wav files is skipped.
+type FileSource struct {
+	path  string
+	file  *os.File
+	audio io.Reader
+}
+
+// NewFileSource returns a FileSource for the file at path.
+func NewFileSource(path string) *FileSource {
+	return &FileSource{path: path}
+}
+
+// Start opens the file, or does nothing if it is already open. Starting
+// again after Stop() reads the file from the start.
+func (fs *FileSource) Start() error {
+	if fs.file != nil {
+		return nil
+	}
+
+	f, err := os.Open(fs.path)
+	if err != nil {
+		return err
+	}
+
+	var audio io.Reader = f
+
+	if strings.EqualFold(filepath.Ext(fs.path), ".wav") {
+		if _, audio, err = ParseWAVHeader(f); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to read %s: %w", fs.path, err)
+		}
+	}
+
+	fs.file = f
+	fs.audio = audio
+
+	return nil
+}
+
+// Read audio from the file into p.
+func (fs *FileSource) Read(p []byte) (int, error) {
+	if fs.file == nil {
+		return 0, fmt.Errorf("audio file %s is not open", fs.path)
+	}
+
+	return fs.audio.Read(p)
+}
+
+// Stop closes the file.
+func (fs *FileSource) Stop() error {
+	if fs.file == nil {
+		return nil
+	}
+
+	err := fs.file.Close()
+	fs.file = nil
+	fs.audio = nil
+
+	return err
+}
+
+// Close closes the file, so that a FileSource can be used as an
+// io.ReadCloser.
+func (fs *FileSource) Close() error {
+	return fs.Stop()
+}
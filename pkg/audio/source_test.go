@@ -0,0 +1,107 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewSourceFromConfig(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := NewSourceFromConfig(Config{SourceFile: "audio.raw"}).(*FileSource); !ok {
+		t.Error("expected a FileSource when a source file is configured")
+	}
+
+	if _, ok := NewSourceFromConfig(Config{Application: "sox"}).(*RecorderSource); !ok {
+		t.Error("expected a RecorderSource when an application is configured")
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	t.Parallel()
+
+	samples := []byte{1, 2, 3, 4, 5, 6}
+	path := filepath.Join(t.TempDir(), "audio.wav")
+
+	if err := os.WriteFile(path, wavFile(fmtChunk(1, 16000, 16), wavChunk("data", samples)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var source AudioSource = NewFileSource(path)
+
+	// The file can be read again after stopping.
+	for i := 0; i < 2; i++ {
+		if err := source.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		if actual, err := io.ReadAll(source); err != nil || !bytes.Equal(actual, samples) {
+			t.Errorf("incorrect audio - expected: %v, actual: %v (err: %v)", samples, actual, err)
+		}
+
+		if err := source.Stop(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := source.Read(make([]byte, 1)); err == nil {
+		t.Error("expected an error reading a stopped source")
+	}
+}
+
+func TestRecorderSourceStop(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		name    string
+		cfg     Config
+		errText string // expected in the error, empty for no error
+	}{
+		{"failure", Config{Application: "ls", Args: "/nonexistent-recording-device"}, "nonexistent-recording-device"},
+		{"success", Config{Application: "true"}, ""},
+	}
+
+	for i := range testList {
+		test := testList[i]
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := NewSourceFromConfig(test.cfg)
+			if err := source.Start(); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := io.ReadAll(source); err != nil {
+				t.Fatal(err)
+			}
+
+			err := source.Stop()
+
+			switch {
+			case test.errText == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case test.errText != "" && (err == nil || !strings.Contains(err.Error(), test.errText)):
+				t.Errorf("expected an error containing %q, got: %v", test.errText, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+)
+
+// tailWriter is an io.Writer that keeps the last lines written to it, e.g.
+// to report the stderr output of a failed application without keeping all
+// of it in memory.
+type tailWriter struct {
+	mu       sync.Mutex
+	maxLines int
+	maxBytes int
+	buf      []byte
+}
+
+func newTailWriter(maxLines, maxBytes int) *tailWriter {
+	return &tailWriter{maxLines: maxLines, maxBytes: maxBytes}
+}
+
+// Write appends p, dropping the oldest lines beyond the limits.
+func (tw *tailWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	tw.buf = append(tw.buf, p...)
+
+	// Count the complete lines, ignoring a trailing newline so that the
+	// last line is kept.
+	body := bytes.TrimSuffix(tw.buf, []byte("\n"))
+	for bytes.Count(body, []byte("\n")) >= tw.maxLines {
+		i := bytes.IndexByte(tw.buf, '\n')
+		tw.buf = tw.buf[i+1:]
+		body = bytes.TrimSuffix(tw.buf, []byte("\n"))
+	}
+
+	if len(tw.buf) > tw.maxBytes {
+		tw.buf = tw.buf[len(tw.buf)-tw.maxBytes:]
+	}
+
+	return len(p), nil
+}
+
+// String returns the kept lines, without surrounding whitespace.
+func (tw *tailWriter) String() string {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	return strings.TrimSpace(string(tw.buf))
+}
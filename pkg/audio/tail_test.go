@@ -0,0 +1,51 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTailWriter(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		name     string
+		writes   []string
+		maxLines int
+		maxBytes int
+		expected string
+	}{
+		{"fewer lines", []string{"a\n", "b\n"}, 3, 100, "a\nb"},
+		{"more lines", []string{"a\nb\n", "c\nd\n"}, 2, 100, "c\nd"},
+		{"split line", []string{"a\nb", "c\nd"}, 2, 100, "bc\nd"},
+		{"byte limit", []string{strings.Repeat("x", 10) + "\n"}, 2, 4, "xxx"},
+	}
+
+	for _, test := range testList {
+		tw := newTailWriter(test.maxLines, test.maxBytes)
+
+		for _, w := range test.writes {
+			if n, err := tw.Write([]byte(w)); err != nil || n != len(w) {
+				t.Fatalf("%s: unexpected write result: %d, %v", test.name, n, err)
+			}
+		}
+
+		if actual := tw.String(); actual != test.expected {
+			t.Errorf("%s: expected: %q, actual: %q", test.name, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"io"
+	"math"
+	"time"
+)
+
+// vadSampleRate is the sample rate assumed by the VADReader.
+const vadSampleRate = 16000
+
+// VADConfig configures a VADReader. Zero values are replaced by defaults.
+type VADConfig struct {
+	// FrameSize is the number of samples over which the energy is
+	// measured. Defaults to 320 (20 ms).
+	FrameSize int
+
+	// EnergyThreshold is the RMS level in dBFS at or above which a frame
+	// is considered speech. Defaults to -40.
+	EnergyThreshold float64
+
+	// SilenceDuration is how long the audio must stay below the energy
+	// threshold after speech was detected for the reader to end.
+	// Defaults to 1s.
+	SilenceDuration time.Duration
+}
+
+func (cfg VADConfig) withDefaults() VADConfig {
+	if cfg.FrameSize <= 0 {
+		cfg.FrameSize = 320
+	}
+
+	if cfg.EnergyThreshold == 0 {
+		cfg.EnergyThreshold = -40
+	}
+
+	if cfg.SilenceDuration <= 0 {
+		cfg.SilenceDuration = time.Second
+	}
+
+	return cfg
+}
+
+// VADReader is an io.Reader that ends the audio read from a wrapped reader
+// once the speaker stops talking. It assumes 16 kHz mono 16-bit little endian
+// PCM audio.
+type VADReader struct {
+	r             io.Reader
+	frameBytes    int
+	thresholdRMS  float64
+	silenceFrames int // number of silent frames that end the audio
+
+	speech bool // speech has been detected
+	silent int  // consecutive silent frames since the last speech
+	done   bool // the end of speech was detected
+
+	buf     []byte
+	partial []byte
+	out     []byte
+	err     error
+}
+
+// NewVADReader returns a VADReader that reads audio from r and returns
+// io.EOF after cfg.SilenceDuration of audio below cfg.EnergyThreshold
+// following detected speech. Leading silence never ends the audio. It may
+// be used in place of Recorder.Output(), e.g. with diatheke.ReadASRAudio.
+func NewVADReader(r io.Reader, cfg VADConfig) *VADReader {
+	cfg = cfg.withDefaults()
+	frameDur := time.Duration(cfg.FrameSize) * time.Second / vadSampleRate
+
+	return &VADReader{
+		r:             r,
+		frameBytes:    cfg.FrameSize * bytesPerSample,
+		thresholdRMS:  dbfsToRMS(cfg.EnergyThreshold),
+		silenceFrames: int((cfg.SilenceDuration + frameDur - 1) / frameDur),
+	}
+}
+
+// Read audio into p. It returns io.EOF once the end of speech is detected.
+func (v *VADReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	for len(v.out) == 0 {
+		if v.done {
+			return 0, io.EOF
+		}
+
+		if v.err != nil {
+			return 0, v.err
+		}
+
+		if size := len(p) + v.frameBytes; cap(v.buf) < size {
+			v.buf = make([]byte, size)
+		}
+
+		n, err := v.r.Read(v.buf[:cap(v.buf)])
+		v.partial = append(v.partial, v.buf[:n]...)
+		v.err = err
+
+		v.processFrames()
+	}
+
+	n := copy(p, v.out)
+	v.out = v.out[n:]
+
+	return n, nil
+}
+
+// SpeechDetected returns true if speech has been detected so far.
+func (v *VADReader) SpeechDetected() bool {
+	return v.speech
+}
+
+// processFrames moves the complete frames from partial to out, stopping
+// after the frame that ends the speech.
+func (v *VADReader) processFrames() {
+	i := 0
+	for ; i+v.frameBytes <= len(v.partial) && !v.done; i += v.frameBytes {
+		frame := v.partial[i : i+v.frameBytes]
+		v.out = append(v.out, frame...)
+
+		if frameRMS(frame) >= v.thresholdRMS {
+			v.speech = true
+			v.silent = 0
+
+			continue
+		}
+
+		if v.speech {
+			v.silent++
+			v.done = v.silent >= v.silenceFrames
+		}
+	}
+
+	v.partial = append(v.partial[:0], v.partial[i:]...)
+}
+
+// frameRMS returns the RMS of the samples in the given frame.
+func frameRMS(frame []byte) float64 {
+	var sumSquares float64
+
+	n := len(frame) / bytesPerSample
+	for i := 0; i < n; i++ {
+		s := float64(sampleAt(frame, i))
+		sumSquares += s * s
+	}
+
+	return math.Sqrt(sumSquares / float64(n))
+}
@@ -0,0 +1,91 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+// vadTestAudio returns audio with the given number of samples of silence,
+// then speech (a full scale square wave), then silence.
+func vadTestAudio(lead, speech, trail int) []byte {
+	b := make([]byte, (lead+speech+trail)*bytesPerSample)
+
+	for i := lead; i < lead+speech; i++ {
+		s := int16(16384)
+		if i%2 == 0 {
+			s = -s
+		}
+
+		setSampleAt(b, i, s)
+	}
+
+	return b
+}
+
+func TestVADReader(t *testing.T) {
+	t.Parallel()
+
+	cfg := VADConfig{FrameSize: 160, SilenceDuration: 100 * time.Millisecond}
+
+	// 100 ms of silence is 1600 samples, i.e. 10 frames. The audio should
+	// end after those frames, before the remaining silence is read.
+	in := vadTestAudio(8000, 8000, 16000)
+	want := (8000 + 8000 + 1600) * bytesPerSample
+
+	v := NewVADReader(iotest.HalfReader(bytes.NewReader(in)), cfg)
+
+	out, err := io.ReadAll(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(out) != want {
+		t.Errorf("incorrect output length - expected: %d, actual: %d", want, len(out))
+	}
+
+	if !bytes.Equal(out, in[:len(out)]) {
+		t.Error("audio was modified by the VAD reader")
+	}
+
+	if !v.SpeechDetected() {
+		t.Error("speech was not detected")
+	}
+}
+
+func TestVADReaderNoSpeech(t *testing.T) {
+	t.Parallel()
+
+	// Leading silence must not end the audio.
+	in := vadTestAudio(32000, 0, 0)
+	v := NewVADReader(bytes.NewReader(in), VADConfig{SilenceDuration: 100 * time.Millisecond})
+
+	out, err := io.ReadAll(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(out) != len(in) {
+		t.Errorf("incorrect output length - expected: %d, actual: %d", len(in), len(out))
+	}
+
+	if v.SpeechDetected() {
+		t.Error("unexpected speech detected")
+	}
+}
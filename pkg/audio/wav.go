@@ -0,0 +1,194 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// WAVInfo describes the audio stored in a WAV file.
+type WAVInfo struct {
+	AudioFormat   uint16 // 1 for PCM, 3 for IEEE float, etc.
+	Channels      uint16
+	SampleRate    uint32
+	BitsPerSample uint16
+	DataSize      uint32 // size of the data chunk, as reported by the header
+}
+
+// ErrNotWAV is returned by ParseWAVHeader when the data does not start with
+// a RIFF/WAVE header.
+var ErrNotWAV = errors.New("not a WAV file: missing RIFF/WAVE header")
+
+const (
+	riffHeaderSize  = 12 // "RIFF", file size, "WAVE"
+	chunkHeaderSize = 8  // chunk ID, chunk size
+	fmtChunkMinSize = 16 // size of the PCM fmt chunk
+)
+
+// ParseWAVHeader reads and validates the header of a WAV file, and returns
+// the audio information with a reader positioned at the first audio sample.
+// Chunks other than "fmt " found before the "data" chunk (e.g. "LIST" or
+// "fact") are skipped. The returned reader is not limited to the size of the
+// data chunk, since streamed WAV data often has an incorrect size.
+func ParseWAVHeader(r io.Reader) (WAVInfo, io.Reader, error) {
+	var (
+		info   WAVInfo
+		header [riffHeaderSize]byte
+	)
+
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return info, nil, fmt.Errorf("failed to read WAV header: %w", err)
+	}
+
+	if !bytes.Equal(header[0:4], []byte("RIFF")) || !bytes.Equal(header[8:12], []byte("WAVE")) {
+		return info, nil, ErrNotWAV
+	}
+
+	foundFmt := false
+
+	for {
+		var chunk [chunkHeaderSize]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return info, nil, fmt.Errorf("failed to read WAV chunk header: %w", err)
+		}
+
+		id := string(chunk[0:4])
+		size := binary.LittleEndian.Uint32(chunk[4:8])
+
+		switch id {
+		case "fmt ":
+			if size < fmtChunkMinSize {
+				return info, nil, fmt.Errorf("WAV fmt chunk is too small (%d bytes)", size)
+			}
+
+			var fmtChunk [fmtChunkMinSize]byte
+			if _, err := io.ReadFull(r, fmtChunk[:]); err != nil {
+				return info, nil, fmt.Errorf("failed to read WAV fmt chunk: %w", err)
+			}
+
+			info.AudioFormat = binary.LittleEndian.Uint16(fmtChunk[0:2])
+			info.Channels = binary.LittleEndian.Uint16(fmtChunk[2:4])
+			info.SampleRate = binary.LittleEndian.Uint32(fmtChunk[4:8])
+			info.BitsPerSample = binary.LittleEndian.Uint16(fmtChunk[14:16])
+
+			if err := skipChunk(r, size-fmtChunkMinSize, size); err != nil {
+				return info, nil, err
+			}
+
+			foundFmt = true
+
+		case "data":
+			if !foundFmt {
+				return info, nil, fmt.Errorf("WAV data chunk found before the fmt chunk")
+			}
+
+			info.DataSize = size
+
+			return info, r, nil
+
+		default:
+			if err := skipChunk(r, size, size); err != nil {
+				return info, nil, err
+			}
+		}
+	}
+}
+
+// skipChunk discards n bytes of the current chunk, plus the padding byte
+// that follows chunks with an odd size.
+func skipChunk(r io.Reader, n, chunkSize uint32) error {
+	skip := int64(n) + int64(chunkSize%2) //nolint:gomnd // chunks are aligned to 2 bytes
+
+	if _, err := io.CopyN(io.Discard, r, skip); err != nil {
+		return fmt.Errorf("failed to skip WAV chunk: %w", err)
+	}
+
+	return nil
+}
+
+// wavHeaderSize is the size of the header written by wavWriter.
+const wavHeaderSize = riffHeaderSize + chunkHeaderSize + fmtChunkMinSize + chunkHeaderSize
+
+// wavWriter writes 16-bit PCM audio to a WAV file. Since the amount of
+// audio isn't known up front, the sizes in the header are filled in when
+// the writer is closed.
+type wavWriter struct {
+	f        *os.File
+	dataSize uint32
+}
+
+// createWAV creates a WAV file at path for 16-bit PCM audio with the given
+// sample rate and number of channels.
+func createWAV(path string, sampleRate, channels int) (*wavWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	blockAlign := channels * bytesPerSample
+
+	var h [wavHeaderSize]byte
+
+	copy(h[0:4], "RIFF")
+	copy(h[8:12], "WAVE")
+	copy(h[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(h[16:20], fmtChunkMinSize)
+	binary.LittleEndian.PutUint16(h[20:22], 1) // PCM
+	binary.LittleEndian.PutUint16(h[22:24], uint16(channels))
+	binary.LittleEndian.PutUint32(h[24:28], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(h[28:32], uint32(sampleRate*blockAlign))
+	binary.LittleEndian.PutUint16(h[32:34], uint16(blockAlign))
+	binary.LittleEndian.PutUint16(h[34:36], 8*bytesPerSample) //nolint:gomnd // bits per sample
+	copy(h[36:40], "data")
+
+	if _, err := f.Write(h[:]); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &wavWriter{f: f}, nil
+}
+
+// Write appends audio to the data chunk.
+func (w *wavWriter) Write(p []byte) (int, error) {
+	n, err := w.f.Write(p)
+	w.dataSize += uint32(n)
+
+	return n, err
+}
+
+// Close fills in the RIFF and data chunk sizes and closes the file.
+func (w *wavWriter) Close() error {
+	var size [4]byte
+
+	binary.LittleEndian.PutUint32(size[:], wavHeaderSize-chunkHeaderSize+w.dataSize)
+	if _, err := w.f.WriteAt(size[:], 4); err != nil { //nolint:gomnd // offset of the RIFF size
+		w.f.Close()
+		return err
+	}
+
+	binary.LittleEndian.PutUint32(size[:], w.dataSize)
+	if _, err := w.f.WriteAt(size[:], wavHeaderSize-4); err != nil { //nolint:gomnd // offset of the data size
+		w.f.Close()
+		return err
+	}
+
+	return w.f.Close()
+}
@@ -0,0 +1,125 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+// wavChunk returns a RIFF chunk with the given ID and data.
+func wavChunk(id string, data []byte) []byte {
+	b := make([]byte, 8, 8+len(data)+1)
+	copy(b, id)
+	binary.LittleEndian.PutUint32(b[4:], uint32(len(data)))
+
+	b = append(b, data...)
+	if len(data)%2 == 1 {
+		b = append(b, 0)
+	}
+
+	return b
+}
+
+// wavFile returns a WAV file made of the given chunks.
+func wavFile(chunks ...[]byte) []byte {
+	body := []byte("WAVE")
+	for _, c := range chunks {
+		body = append(body, c...)
+	}
+
+	return append(wavChunk("RIFF", body)[:8], body...)
+}
+
+func fmtChunk(channels uint16, sampleRate uint32, bits uint16) []byte {
+	b := make([]byte, 16)
+	binary.LittleEndian.PutUint16(b[0:], 1)
+	binary.LittleEndian.PutUint16(b[2:], channels)
+	binary.LittleEndian.PutUint32(b[4:], sampleRate)
+	binary.LittleEndian.PutUint32(b[8:], sampleRate*uint32(channels)*uint32(bits/8))
+	binary.LittleEndian.PutUint16(b[12:], channels*bits/8)
+	binary.LittleEndian.PutUint16(b[14:], bits)
+
+	return wavChunk("fmt ", b)
+}
+
+func TestParseWAVHeader(t *testing.T) {
+	t.Parallel()
+
+	samples := []byte{1, 2, 3, 4, 5, 6}
+
+	testList := []struct {
+		name string
+		file []byte
+	}{
+		{"plain", wavFile(fmtChunk(2, 8000, 16), wavChunk("data", samples))},
+		{"extra chunks", wavFile(
+			wavChunk("LIST", []byte("INFOISFT\x05\x00\x00\x00test\x00")),
+			fmtChunk(2, 8000, 16),
+			wavChunk("fact", []byte{3, 0, 0, 0}),
+			wavChunk("data", samples),
+		)},
+	}
+
+	for i := range testList {
+		test := testList[i]
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			info, r, err := ParseWAVHeader(bytes.NewReader(test.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := WAVInfo{AudioFormat: 1, Channels: 2, SampleRate: 8000, BitsPerSample: 16, DataSize: uint32(len(samples))}
+			if info != expected {
+				t.Errorf("incorrect info - expected: %+v, actual: %+v", expected, info)
+			}
+
+			data, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(data, samples) {
+				t.Errorf("incorrect samples - expected: %v, actual: %v", samples, data)
+			}
+		})
+	}
+}
+
+func TestParseWAVHeaderErrors(t *testing.T) {
+	t.Parallel()
+
+	raw := make([]byte, 64)
+
+	if _, _, err := ParseWAVHeader(bytes.NewReader(raw)); !errors.Is(err, ErrNotWAV) {
+		t.Errorf("incorrect error for raw audio - expected: %v, actual: %v", ErrNotWAV, err)
+	}
+
+	noFmt := wavFile(wavChunk("data", []byte{1, 2}))
+	if _, _, err := ParseWAVHeader(bytes.NewReader(noFmt)); err == nil {
+		t.Error("expected an error for a data chunk without fmt chunk")
+	}
+
+	truncated := wavFile(fmtChunk(1, 16000, 16))
+	if _, _, err := ParseWAVHeader(bytes.NewReader(truncated)); err == nil {
+		t.Error("expected an error for a file without data chunk")
+	}
+}
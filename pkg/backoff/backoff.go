@@ -0,0 +1,147 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backoff implements exponential backoff with jitter, to space out
+// retries and reconnection attempts consistently across the examples.
+package backoff
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMultiplier = 2.0
+	defaultJitter     = 0.2
+)
+
+// Backoff computes increasing delays between attempts. Each delay is the
+// initial delay multiplied by the multiplier for every previous attempt,
+// capped at the maximum delay, then reduced by a random amount of up to the
+// jitter fraction so that clients retrying at the same time spread out.
+// It is safe for concurrent use.
+type Backoff struct {
+	initial    time.Duration
+	max        time.Duration
+	multiplier float64
+	jitter     float64
+
+	mu      sync.Mutex
+	attempt int
+	rand    *rand.Rand
+}
+
+// Option configures a Backoff.
+type Option func(*Backoff) error
+
+// WithMultiplier returns an Option that sets the factor by which the delay
+// grows after each attempt. The default is 2. A value m>=1 is required.
+func WithMultiplier(m float64) Option {
+	return func(b *Backoff) error {
+		if m < 1 {
+			return fmt.Errorf("invalid backoff multiplier %v, must be at least 1", m)
+		}
+
+		b.multiplier = m
+
+		return nil
+	}
+}
+
+// WithJitter returns an Option that sets the maximum fraction by which each
+// delay is randomly reduced. The default is 0.2. Zero disables the jitter.
+// A value 0<=j<=1 is required.
+func WithJitter(j float64) Option {
+	return func(b *Backoff) error {
+		if j < 0 || j > 1 {
+			return fmt.Errorf("invalid backoff jitter %v, must be between 0 and 1", j)
+		}
+
+		b.jitter = j
+
+		return nil
+	}
+}
+
+// New returns a Backoff whose delays start at initial and never exceed maxDelay.
+func New(initial, maxDelay time.Duration, opts ...Option) (*Backoff, error) {
+	if initial <= 0 {
+		return nil, fmt.Errorf("invalid initial backoff %v, must be positive", initial)
+	}
+
+	if maxDelay < initial {
+		return nil, fmt.Errorf("invalid maximum backoff %v, must be at least the initial backoff %v", maxDelay, initial)
+	}
+
+	b := &Backoff{
+		initial:    initial,
+		max:        maxDelay,
+		multiplier: defaultMultiplier,
+		jitter:     defaultJitter,
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())), //nolint:gosec // jitter does not need a secure source
+	}
+
+	for _, opt := range opts {
+		if err := opt(b); err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// Next returns the delay to wait before the next attempt.
+func (b *Backoff) Next() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	d := float64(b.initial) * math.Pow(b.multiplier, float64(b.attempt))
+	if d > float64(b.max) {
+		d = float64(b.max)
+	} else {
+		// Only count attempts until the maximum is reached, so the
+		// exponent can't grow forever.
+		b.attempt++
+	}
+
+	d -= d * b.jitter * b.rand.Float64()
+
+	return time.Duration(d)
+}
+
+// Reset restarts the delays from the initial delay, e.g. once an attempt
+// succeeded.
+func (b *Backoff) Reset() {
+	b.mu.Lock()
+	b.attempt = 0
+	b.mu.Unlock()
+}
+
+// Wait sleeps for the next delay. It returns early with the context's error
+// if the context is done before the delay has passed.
+func (b *Backoff) Wait(ctx context.Context) error {
+	t := time.NewTimer(b.Next())
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackoffSequence(t *testing.T) {
+	t.Parallel()
+
+	b, err := New(100*time.Millisecond, time.Second, WithJitter(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+
+	for i, want := range expected {
+		if got := b.Next(); got != want {
+			t.Errorf("delay %d - expected: %v, actual: %v", i, want, got)
+		}
+	}
+
+	b.Reset()
+
+	if got := b.Next(); got != expected[0] {
+		t.Errorf("delay after reset - expected: %v, actual: %v", expected[0], got)
+	}
+}
+
+func TestBackoffJitterBounds(t *testing.T) {
+	t.Parallel()
+
+	const jitter = 0.5
+
+	b, err := New(10*time.Millisecond, 80*time.Millisecond, WithMultiplier(3), WithJitter(jitter))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	upper := 10 * time.Millisecond
+
+	for i := 0; i < 20; i++ {
+		lower := time.Duration(float64(upper) * (1 - jitter))
+
+		if got := b.Next(); got < lower || got > upper {
+			t.Errorf("delay %d out of bounds - expected: [%v, %v], actual: %v", i, lower, upper, got)
+		}
+
+		if upper *= 3; upper > 80*time.Millisecond {
+			upper = 80 * time.Millisecond
+		}
+	}
+}
+
+func TestBackoffInvalid(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		name    string
+		initial time.Duration
+		max     time.Duration
+		opts    []Option
+	}{
+		{"zero initial", 0, time.Second, nil},
+		{"max below initial", time.Second, time.Millisecond, nil},
+		{"multiplier below 1", time.Millisecond, time.Second, []Option{WithMultiplier(0.5)}},
+		{"jitter above 1", time.Millisecond, time.Second, []Option{WithJitter(1.5)}},
+	}
+
+	for _, test := range testList {
+		if _, err := New(test.initial, test.max, test.opts...); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
+
+func TestBackoffWaitCancel(t *testing.T) {
+	t.Parallel()
+
+	b, err := New(time.Minute, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+
+	if err := b.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("incorrect error - expected: %v, actual: %v", context.Canceled, err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("wait was not cancelled, took %v", elapsed)
+	}
+}
+
+func TestBackoffWait(t *testing.T) {
+	t.Parallel()
+
+	b, err := New(time.Millisecond, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Wait(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package confidence formats the confidence of transcriptions for display,
+// so that the example clients show it the same way.
+package confidence
+
+import "fmt"
+
+// Supported confidence formats.
+const (
+	None    = "none"    // the confidence is not shown
+	Raw     = "raw"     // e.g. (0.92)
+	Percent = "percent" // e.g. (92%)
+)
+
+// CheckFormat returns an error if format is not one of the supported
+// confidence formats.
+func CheckFormat(format string) error {
+	switch format {
+	case None, Raw, Percent:
+		return nil
+	default:
+		return fmt.Errorf("invalid confidence format %q, must be one of none, raw or percent", format)
+	}
+}
+
+// Format formats a confidence between 0 and 1 for display, e.g. "(0.92)" or
+// "(92%)". It returns an empty string for the none format.
+func Format(c float64, format string) string {
+	switch format {
+	case Raw:
+		return fmt.Sprintf("(%.2f)", c)
+	case Percent:
+		return fmt.Sprintf("(%.0f%%)", c*100) //nolint:gomnd // converting to a percentage
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confidence
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		confidence float64
+		format     string
+		expected   string
+	}{
+		{0.92, Raw, "(0.92)"},
+		{0.456, Raw, "(0.46)"},
+		{0.92, Percent, "(92%)"},
+		{0.456, Percent, "(46%)"},
+		{1, Percent, "(100%)"},
+		{0, Percent, "(0%)"},
+		{0.92, None, ""},
+	}
+
+	for _, test := range testList {
+		if actual := Format(test.confidence, test.format); actual != test.expected {
+			t.Errorf("Format(%v, %s) - expected: %q, actual: %q", test.confidence, test.format, test.expected, actual)
+		}
+	}
+}
+
+func TestCheckFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []string{None, Raw, Percent} {
+		if err := CheckFormat(format); err != nil {
+			t.Errorf("CheckFormat(%s) - unexpected error: %v", format, err)
+		}
+	}
+
+	for _, format := range []string{"", "fraction", "Raw"} {
+		if err := CheckFormat(format); err == nil {
+			t.Errorf("CheckFormat(%q) - expected an error", format)
+		}
+	}
+}
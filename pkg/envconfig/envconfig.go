@@ -0,0 +1,76 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package envconfig overrides the server settings of the examples with
+// environment variables, e.g., for containerized runs where editing the
+// config files is inconvenient. Settings from the environment take
+// precedence over the ones read from config files.
+package envconfig
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Environment variables overriding the server settings.
+const (
+	ServerAddress = "COBALT_SERVER_ADDRESS"
+	ModelID       = "COBALT_MODEL_ID"
+	Insecure      = "COBALT_INSECURE"
+)
+
+// Server points to the server settings that may be overridden. Nil
+// fields are left alone.
+type Server struct {
+	Address  *string
+	ModelID  *string
+	Insecure *bool
+}
+
+// Apply overrides the settings with the environment variables that are
+// set to a non-empty value. It returns an error if COBALT_INSECURE is
+// not a valid boolean (e.g., "true", "false", "1" or "0"), in which case
+// no setting is changed.
+func (s Server) Apply() error {
+	insecure, hasInsecure := os.LookupEnv(Insecure)
+	hasInsecure = hasInsecure && insecure != ""
+
+	var insecureVal bool
+
+	if hasInsecure && s.Insecure != nil {
+		var err error
+
+		if insecureVal, err = strconv.ParseBool(insecure); err != nil {
+			return fmt.Errorf("invalid %s value %q: %w", Insecure, insecure, err)
+		}
+	}
+
+	setString(s.Address, ServerAddress)
+	setString(s.ModelID, ModelID)
+
+	if hasInsecure && s.Insecure != nil {
+		*s.Insecure = insecureVal
+	}
+
+	return nil
+}
+
+// setString sets *dst to the value of the environment variable, if dst
+// is not nil and the variable is set to a non-empty value.
+func setString(dst *string, name string) {
+	if val := os.Getenv(name); dst != nil && val != "" {
+		*dst = val
+	}
+}
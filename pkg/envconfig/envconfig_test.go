@@ -0,0 +1,67 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envconfig
+
+import "testing"
+
+func TestApply(t *testing.T) {
+	t.Setenv(ServerAddress, "asr.example.com:443")
+	t.Setenv(ModelID, "")
+	t.Setenv(Insecure, "true")
+
+	address, modelID, insecure := "localhost:9000", "1", false
+
+	err := Server{Address: &address, ModelID: &modelID, Insecure: &insecure}.Apply()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An empty variable does not override the setting.
+	if address != "asr.example.com:443" || modelID != "1" || !insecure {
+		t.Errorf("incorrect settings: %q, %q, %t", address, modelID, insecure)
+	}
+}
+
+func TestApplyNil(t *testing.T) {
+	t.Setenv(ServerAddress, "asr.example.com:443")
+	t.Setenv(Insecure, "not a bool")
+
+	modelID := "1"
+
+	// Nil settings are left alone, so an invalid value for them is not
+	// an error.
+	if err := (Server{ModelID: &modelID}).Apply(); err != nil {
+		t.Fatal(err)
+	}
+
+	if modelID != "1" {
+		t.Errorf("incorrect model ID: %q", modelID)
+	}
+}
+
+func TestApplyInvalid(t *testing.T) {
+	t.Setenv(ServerAddress, "asr.example.com:443")
+	t.Setenv(Insecure, "yes")
+
+	address, insecure := "localhost:9000", false
+
+	if err := (Server{Address: &address, Insecure: &insecure}).Apply(); err == nil {
+		t.Fatal("expected an error for an invalid boolean")
+	}
+
+	if address != "localhost:9000" {
+		t.Errorf("setting changed despite the error: %q", address)
+	}
+}
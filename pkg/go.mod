@@ -0,0 +1,5 @@
+module github.com/cobaltspeech/examples-go/pkg
+
+go 1.19
+
+require github.com/BurntSushi/toml v0.3.1
@@ -0,0 +1,94 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package textenc writes text output with an optional UTF-8 byte order mark
+// and CRLF line endings, for tools (mostly on Windows) that expect them.
+package textenc
+
+import (
+	"bytes"
+	"io"
+)
+
+// BOM is the UTF-8 encoded byte order mark.
+const BOM = "\xEF\xBB\xBF"
+
+// Options selects the encoding of the text output.
+type Options struct {
+	// BOM writes a UTF-8 byte order mark before the output.
+	BOM bool
+
+	// CRLF ends each line with "\r\n" instead of "\n".
+	CRLF bool
+}
+
+// NewWriter returns a writer to w that applies the options: the byte order
+// mark is written before the first byte of output, and each "\n" is written
+// as "\r\n". It returns w itself if no option is set.
+func NewWriter(w io.Writer, opts Options) io.Writer {
+	if !opts.BOM && !opts.CRLF {
+		return w
+	}
+
+	return &writer{w: w, bom: opts.BOM, crlf: opts.CRLF}
+}
+
+type writer struct {
+	w    io.Writer
+	bom  bool // bom is cleared once the byte order mark is written.
+	crlf bool
+}
+
+// Write implements io.Writer. The returned count is of the bytes of p, not
+// including the byte order mark and added carriage returns.
+func (w *writer) Write(p []byte) (int, error) {
+	if w.bom {
+		if _, err := io.WriteString(w.w, BOM); err != nil {
+			return 0, err
+		}
+
+		w.bom = false
+	}
+
+	if !w.crlf {
+		return w.w.Write(p)
+	}
+
+	written := 0
+
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			n, err := w.w.Write(p)
+
+			return written + n, err
+		}
+
+		n, err := w.w.Write(p[:i])
+		written += n
+
+		if err != nil {
+			return written, err
+		}
+
+		if _, err := io.WriteString(w.w, "\r\n"); err != nil {
+			return written, err
+		}
+
+		written++
+		p = p[i+1:]
+	}
+
+	return written, nil
+}
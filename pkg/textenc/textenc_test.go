@@ -0,0 +1,103 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textenc
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		name     string
+		opts     Options
+		expected string
+	}{
+		{"default", Options{}, "hello\nwor\nld\n"},
+		{"bom", Options{BOM: true}, "\xEF\xBB\xBFhello\nwor\nld\n"},
+		{"crlf", Options{CRLF: true}, "hello\r\nwor\r\nld\r\n"},
+		{"bom+crlf", Options{BOM: true, CRLF: true}, "\xEF\xBB\xBFhello\r\nwor\r\nld\r\n"},
+	}
+
+	for i := range testList {
+		test := testList[i]
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			w := NewWriter(&buf, test.opts)
+
+			// Lines are split across writes, and the byte order mark must
+			// only be written once.
+			for _, s := range []string{"hello\nwor", "\nld", "\n"} {
+				n, err := w.Write([]byte(s))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if n != len(s) {
+					t.Errorf("incorrect count - expected: %d, actual: %d", len(s), n)
+				}
+			}
+
+			if actual := buf.String(); actual != test.expected {
+				t.Errorf("incorrect output - expected: %q, actual: %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+// limitWriter fails once more than n bytes are written.
+type limitWriter struct {
+	buf bytes.Buffer
+	n   int
+}
+
+var errLimit = errors.New("write limit reached")
+
+func (w *limitWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n, _ := w.buf.Write(p[:w.n])
+		w.n = 0
+
+		return n, errLimit
+	}
+
+	w.n -= len(p)
+
+	return w.buf.Write(p)
+}
+
+func TestWriterError(t *testing.T) {
+	t.Parallel()
+
+	// "ab\r\n" fits, and only "c" of "cd" is written.
+	lw := &limitWriter{n: 5}
+	w := NewWriter(lw, Options{CRLF: true})
+
+	n, err := w.Write([]byte("ab\ncd"))
+	if !errors.Is(err, errLimit) {
+		t.Errorf("incorrect error - expected: %v, actual: %v", errLimit, err)
+	}
+
+	if n != 4 {
+		t.Errorf("incorrect count - expected: 4, actual: %d", n)
+	}
+}
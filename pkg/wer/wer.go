@@ -0,0 +1,152 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wer aligns a hypothesis transcript against a reference, word by
+// word, and computes its word error rate.
+package wer
+
+// Op is the kind of a step in an alignment.
+type Op int
+
+// Alignment steps.
+const (
+	Equal      Op = iota // the words match
+	Substitute           // the reference word is replaced by the hypothesis word
+	Delete               // the reference word is missing from the hypothesis
+	Insert               // the hypothesis word is not in the reference
+)
+
+// Edit is a step of the alignment of a hypothesis against a reference. Ref is
+// empty for insertions and Hyp for deletions.
+type Edit struct {
+	Op  Op
+	Ref string
+	Hyp string
+}
+
+// Align aligns hyp against ref with the minimum number of word substitutions,
+// deletions and insertions (Levenshtein distance).
+func Align(ref, hyp []string) []Edit {
+	// dist[i][j] is the edit distance between ref[:i] and hyp[:j].
+	dist := make([][]int, len(ref)+1)
+	for i := range dist {
+		dist[i] = make([]int, len(hyp)+1)
+		dist[i][0] = i
+	}
+
+	for j := range dist[0] {
+		dist[0][j] = j
+	}
+
+	for i := 1; i <= len(ref); i++ {
+		for j := 1; j <= len(hyp); j++ {
+			cost := 1
+			if ref[i-1] == hyp[j-1] {
+				cost = 0
+			}
+
+			dist[i][j] = minInt(dist[i-1][j-1]+cost, minInt(dist[i-1][j]+1, dist[i][j-1]+1))
+		}
+	}
+
+	// Walk back from the end to recover the edits.
+	var edits []Edit
+
+	i, j := len(ref), len(hyp)
+	for i > 0 || j > 0 {
+		switch {
+		case i > 0 && j > 0 && ref[i-1] == hyp[j-1] && dist[i][j] == dist[i-1][j-1]:
+			edits = append(edits, Edit{Op: Equal, Ref: ref[i-1], Hyp: hyp[j-1]})
+			i, j = i-1, j-1
+		case i > 0 && j > 0 && dist[i][j] == dist[i-1][j-1]+1:
+			edits = append(edits, Edit{Op: Substitute, Ref: ref[i-1], Hyp: hyp[j-1]})
+			i, j = i-1, j-1
+		case i > 0 && dist[i][j] == dist[i-1][j]+1:
+			edits = append(edits, Edit{Op: Delete, Ref: ref[i-1]})
+			i--
+		default:
+			edits = append(edits, Edit{Op: Insert, Hyp: hyp[j-1]})
+			j--
+		}
+	}
+
+	for l, r := 0, len(edits)-1; l < r; l, r = l+1, r-1 {
+		edits[l], edits[r] = edits[r], edits[l]
+	}
+
+	return edits
+}
+
+// Counts are the numbers of each kind of error in an alignment.
+type Counts struct {
+	Substitutions int
+	Deletions     int
+	Insertions    int
+	RefWords      int // number of words in the reference
+}
+
+// Count returns the numbers of errors in the edits.
+func Count(edits []Edit) Counts {
+	var c Counts
+
+	for _, e := range edits {
+		switch e.Op {
+		case Equal:
+			c.RefWords++
+		case Substitute:
+			c.Substitutions++
+			c.RefWords++
+		case Delete:
+			c.Deletions++
+			c.RefWords++
+		case Insert:
+			c.Insertions++
+		}
+	}
+
+	return c
+}
+
+// Errors returns the total number of errors.
+func (c Counts) Errors() int {
+	return c.Substitutions + c.Deletions + c.Insertions
+}
+
+// Rate returns the word error rate, the number of errors divided by the
+// number of reference words. If the reference is empty, it is 0 when there
+// are no errors and 1 otherwise.
+func (c Counts) Rate() float64 {
+	if c.RefWords == 0 {
+		if c.Errors() == 0 {
+			return 0
+		}
+
+		return 1
+	}
+
+	return float64(c.Errors()) / float64(c.RefWords)
+}
+
+// Rate returns the word error rate of hyp against ref.
+func Rate(ref, hyp []string) float64 {
+	return Count(Align(ref, hyp)).Rate()
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,67 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wer
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestAlign(t *testing.T) {
+	t.Parallel()
+
+	edits := Align(strings.Fields("a b c d"), strings.Fields("a c d e"))
+
+	ops := make([]Op, len(edits))
+	for i, e := range edits {
+		ops[i] = e.Op
+	}
+
+	expected := []Op{Equal, Delete, Equal, Equal, Insert}
+	if fmt.Sprint(ops) != fmt.Sprint(expected) {
+		t.Errorf("incorrect alignment - expected: %v, actual: %v", expected, ops)
+	}
+
+	expectedCounts := Counts{Deletions: 1, Insertions: 1, RefWords: 4}
+	if actual := Count(edits); actual != expectedCounts {
+		t.Errorf("incorrect counts - expected: %+v, actual: %+v", expectedCounts, actual)
+	}
+}
+
+func TestRate(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		ref, hyp string
+		expected float64
+	}{
+		{"hello world", "hello world", 0},
+		{"hello world", "hello", 0.5},
+		{"hello world", "hello big world", 0.5},
+		{"hello world", "goodbye moon", 1},
+		{"the quick brown fox jumps", "the quick brown box jumps high", 0.4},
+		{"", "", 0},
+		{"", "hello", 1},
+	}
+
+	for _, test := range testList {
+		actual := Rate(strings.Fields(test.ref), strings.Fields(test.hyp))
+		if math.Abs(actual-test.expected) > 1e-9 {
+			t.Errorf("Rate(%q, %q) - expected: %v, actual: %v", test.ref, test.hyp, test.expected, actual)
+		}
+	}
+}
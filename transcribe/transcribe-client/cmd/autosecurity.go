@@ -0,0 +1,138 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cobaltspeech/examples-go/transcribe/transcribe-client/internal/client"
+
+	"github.com/spf13/cobra"
+)
+
+const autoSecurityTimeout = 5 * time.Second
+
+// autoSecurity enables retrying with the opposite security mode when the
+// configured one fails, see the --auto-security flag.
+var autoSecurity bool
+
+// errNotInteractive is returned when --auto-security is used outside of an
+// interactive terminal.
+var errNotInteractive = errors.New("--auto-security can only be used from an interactive terminal")
+
+// securityProbe tries to reach the server with or without TLS.
+type securityProbe func(ctx context.Context, insecure bool) error
+
+// applyAutoSecurity is run before every command. If --auto-security is set,
+// it probes the server and updates isInsecure to the mode that works.
+func applyAutoSecurity(cmd *cobra.Command, args []string) error {
+	if !autoSecurity {
+		return nil
+	}
+
+	if !isInteractive() {
+		return errNotInteractive
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), autoSecurityTimeout)
+	defer cancel()
+
+	insecure, err := resolveSecurity(ctx, cmd.ErrOrStderr(), isInsecure, probeServer)
+	if err != nil {
+		return err
+	}
+
+	isInsecure = insecure
+
+	return nil
+}
+
+// resolveSecurity probes the server with the configured security mode. If the
+// probe fails because of a TLS mismatch, it retries once with the opposite
+// mode and, if that works, prints a warning to w and returns that mode.
+func resolveSecurity(ctx context.Context, w io.Writer, insecure bool, probe securityProbe) (bool, error) {
+	err := probe(ctx, insecure)
+	if err == nil || !isSecurityMismatch(err) {
+		return insecure, err
+	}
+
+	if retryErr := probe(ctx, !insecure); retryErr != nil {
+		// Report the original error, since the retry was only a guess.
+		return insecure, err
+	}
+
+	if insecure {
+		fmt.Fprintf(w, "\nWARNING: the insecure connection failed, but a TLS connection succeeded.\n"+
+			"WARNING: continuing with TLS. Remove --insecure to fix your configuration.\n\n")
+	} else {
+		fmt.Fprintf(w, "\nWARNING: the TLS connection failed, but an INSECURE connection succeeded.\n"+
+			"WARNING: continuing WITHOUT TLS. Pass --insecure to fix your configuration, "+
+			"and do not use insecure connections in production.\n\n")
+	}
+
+	return !insecure, nil
+}
+
+// isSecurityMismatch reports whether the error looks like the client and
+// server disagree on the use of TLS.
+func isSecurityMismatch(err error) bool {
+	msg := err.Error()
+
+	for _, s := range []string{
+		// TLS client connecting to a server without TLS.
+		"authentication handshake failed",
+		// Insecure client connecting to a TLS server.
+		"server preface",
+		"all SubConns are in TransientFailure",
+	} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// probeServer connects to the server and fetches its version.
+func probeServer(ctx context.Context, insecure bool) error {
+	opts, err := securityOptions(insecure)
+	if err != nil {
+		return err
+	}
+
+	c, err := client.NewClient(serverAddress, append(opts, metadataOptions()...)...)
+	if err != nil {
+		return err
+	}
+
+	defer c.Close()
+
+	_, err = c.Versions(ctx)
+
+	return err
+}
+
+// isInteractive reports whether stdin is a terminal.
+func isInteractive() bool {
+	fi, err := os.Stdin.Stat()
+
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,111 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeProbe simulates a server that only accepts connections with or without
+// TLS, and returns the error a mismatched client would see.
+func fakeProbe(serverInsecure bool, calls *[]bool) securityProbe {
+	return func(ctx context.Context, insecure bool) error {
+		*calls = append(*calls, insecure)
+
+		switch {
+		case insecure == serverInsecure:
+			return nil
+		case serverInsecure:
+			return errors.New("connection error: desc = \"transport: authentication handshake failed: tls: first record does not look like a TLS handshake\"")
+		default:
+			return errors.New("connection error: desc = \"error reading server preface: EOF\"")
+		}
+	}
+}
+
+func TestResolveSecurity(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		name           string
+		clientInsecure bool
+		serverInsecure bool
+		expected       bool
+		expectedCalls  int
+		warning        string
+	}{
+		{"tls ok", false, false, false, 1, ""},
+		{"insecure ok", true, true, true, 1, ""},
+		{"tls to insecure server", false, true, true, 2, "INSECURE connection succeeded"},
+		{"insecure to tls server", true, false, false, 2, "TLS connection succeeded"},
+	}
+
+	for i := range testList {
+		test := testList[i]
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				calls []bool
+				buf   bytes.Buffer
+			)
+
+			actual, err := resolveSecurity(context.Background(), &buf, test.clientInsecure, fakeProbe(test.serverInsecure, &calls))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if actual != test.expected {
+				t.Errorf("incorrect insecure mode - expected: %t, actual: %t", test.expected, actual)
+			}
+
+			if len(calls) != test.expectedCalls {
+				t.Errorf("incorrect number of probes - expected: %d, actual: %d", test.expectedCalls, len(calls))
+			}
+
+			if test.warning == "" && buf.Len() > 0 {
+				t.Errorf("unexpected warning: %s", buf.String())
+			} else if !strings.Contains(buf.String(), test.warning) {
+				t.Errorf("missing warning %q in %q", test.warning, buf.String())
+			}
+		})
+	}
+}
+
+func TestResolveSecurityOtherError(t *testing.T) {
+	t.Parallel()
+
+	errUnreachable := errors.New("connection refused")
+	calls := 0
+
+	_, err := resolveSecurity(context.Background(), &bytes.Buffer{}, false, func(context.Context, bool) error {
+		calls++
+
+		return errUnreachable
+	})
+
+	if !errors.Is(err, errUnreachable) {
+		t.Errorf("incorrect error - expected: %v, actual: %v", errUnreachable, err)
+	}
+
+	if calls != 1 {
+		t.Errorf("expected no retry for a non TLS error, got %d probes", calls)
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cobaltspeech/log"
+)
+
+// outputExtensions maps the output formats to the extension of the output
+// files written next to each input when transcribing multiple files.
+var outputExtensions = map[string]string{
+	formatAuto: ".json",
+	formatJSON: ".json",
+	formatText: ".txt",
+	formatSRT:  ".srt",
+	formatVTT:  ".vtt",
+}
+
+// expandInputs returns the audio files to transcribe for the recognize
+// arguments, which can be files, directories or glob patterns. Directories
+// are not searched recursively, and the output files of previous runs in
+// them are skipped. batch is false if the arguments are a single file.
+func expandInputs(args []string) (files []string, batch bool, err error) {
+	batch = len(args) > 1
+
+	for _, arg := range args {
+		if strings.ContainsAny(arg, "*?[") {
+			matches, err := filepath.Glob(arg)
+			if err != nil {
+				return nil, false, fmt.Errorf("invalid pattern %q: %w", arg, err)
+			}
+
+			files = append(files, matches...)
+			batch = true
+
+			continue
+		}
+
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid input: %w", err)
+		}
+
+		if !info.IsDir() {
+			files = append(files, arg)
+
+			continue
+		}
+
+		entries, err := os.ReadDir(arg)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read input directory: %w", err)
+		}
+
+		for _, e := range entries {
+			if e.Type().IsRegular() && !isOutputFile(e.Name()) {
+				files = append(files, filepath.Join(arg, e.Name()))
+			}
+		}
+
+		batch = true
+	}
+
+	if len(files) == 0 {
+		return nil, false, fmt.Errorf("no audio files found in %s", strings.Join(args, ", "))
+	}
+
+	sort.Strings(files)
+
+	return files, batch, nil
+}
+
+// isOutputFile returns whether the file name has the extension of an output
+// file.
+func isOutputFile(name string) bool {
+	ext := filepath.Ext(name)
+
+	for _, e := range outputExtensions {
+		if ext == e {
+			return true
+		}
+	}
+
+	return false
+}
+
+// batchOutputPath returns the path of the output file for the audio file,
+// next to it with the extension of the output format instead of the audio
+// and encoding extensions.
+func batchOutputPath(audioPath, format string) string {
+	audioPath = trimInputSuffixes(audioPath)
+
+	return strings.TrimSuffix(audioPath, filepath.Ext(audioPath)) + outputExtensions[format]
+}
+
+// transcribeFunc transcribes one audio file to the output path.
+type transcribeFunc func(ctx context.Context, audioPath, outPath string) error
+
+// transcribeBatch transcribes the files with the given number of concurrent
+// workers, writing each output next to its input. Files that aren't started
+// yet are skipped once stop is closed. It returns an error if any file
+// failed, after all the workers are done.
+func transcribeBatch(ctx context.Context, logger log.Logger, files []string, format string, workers int,
+	stop <-chan struct{}, fn transcribeFunc) error {
+	if workers > len(files) {
+		workers = len(files)
+	}
+
+	logger.Info("msg", "processing files", "fileCount", len(files), "numWorkers", workers)
+
+	fileCh := make(chan string)
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed int
+	)
+
+	wg.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		go func(workerID int) {
+			defer wg.Done()
+
+			for audioPath := range fileCh {
+				select {
+				case <-stop:
+					logger.Debug("msg", "skipping file", "input", audioPath)
+
+					continue
+				default:
+				}
+
+				outPath := batchOutputPath(audioPath, format)
+				logger.Debug("msg", "transcribing file", "workerID", workerID, "input", audioPath, "output", outPath)
+
+				if err := fn(ctx, audioPath, outPath); err != nil {
+					logger.Error("msg", "failed to transcribe file", "input", audioPath, "err", err)
+
+					mu.Lock()
+					failed++
+					mu.Unlock()
+				}
+			}
+		}(i)
+	}
+
+feed:
+	for _, f := range files {
+		select {
+		case fileCh <- f:
+		case <-stop:
+			logger.Info("msg", "interrupted, skipping the remaining files")
+
+			break feed
+		case <-ctx.Done():
+			break feed
+		}
+	}
+
+	close(fileCh)
+	wg.Wait()
+
+	if failed > 0 {
+		return fmt.Errorf("failed to transcribe %d of %d files", failed, len(files))
+	}
+
+	return nil
+}
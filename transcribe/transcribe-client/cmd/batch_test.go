@@ -0,0 +1,152 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/cobaltspeech/log"
+)
+
+func TestExpandInputs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"a.wav", "b.wav", "b.json", "c.raw"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	testList := []struct {
+		name     string
+		args     []string
+		expected []string
+		batch    bool
+	}{
+		{"file", []string{path("a.wav")}, []string{path("a.wav")}, false},
+		{"files", []string{path("c.raw"), path("a.wav")}, []string{path("a.wav"), path("c.raw")}, true},
+		{"dir", []string{dir}, []string{path("a.wav"), path("b.wav"), path("c.raw")}, true},
+		{"glob", []string{path("*.wav")}, []string{path("a.wav"), path("b.wav")}, true},
+	}
+
+	for _, test := range testList {
+		files, batch, err := expandInputs(test.args)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		if !reflect.DeepEqual(files, test.expected) || batch != test.batch {
+			t.Errorf("%s - expected: %v (batch %v), actual: %v (batch %v)", test.name, test.expected, test.batch, files, batch)
+		}
+	}
+
+	for _, args := range [][]string{{path("missing.wav")}, {path("*.mp3")}, {path("sub")}} {
+		if _, _, err := expandInputs(args); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
+
+func TestBatchOutputPath(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		format   string
+		expected string
+	}{
+		{formatAuto, "dir/audio.json"},
+		{formatText, "dir/audio.txt"},
+		{formatVTT, "dir/audio.vtt"},
+	}
+
+	for _, test := range testList {
+		if actual := batchOutputPath("dir/audio.wav.gz.b64", test.format); actual != test.expected {
+			t.Errorf("encoded input, format %q - expected: %s, actual: %s", test.format, test.expected, actual)
+		}
+
+		if actual := batchOutputPath("dir/audio.wav", test.format); actual != test.expected {
+			t.Errorf("format %q - expected: %s, actual: %s", test.format, test.expected, actual)
+		}
+	}
+}
+
+func TestTranscribeBatch(t *testing.T) {
+	t.Parallel()
+
+	files := []string{"a.wav", "b.wav", "c.wav", "d.wav", "e.wav"}
+
+	var (
+		mu      sync.Mutex
+		outputs = map[string]string{}
+	)
+
+	fn := func(_ context.Context, audioPath, outPath string) error {
+		mu.Lock()
+		outputs[audioPath] = outPath
+		mu.Unlock()
+
+		if audioPath == "c.wav" {
+			return errors.New("failed")
+		}
+
+		return nil
+	}
+
+	err := transcribeBatch(context.Background(), log.NewDiscardLogger(), files, formatSRT, 3, nil, fn)
+	if err == nil || err.Error() != "failed to transcribe 1 of 5 files" {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	if len(outputs) != len(files) || outputs["b.wav"] != "b.srt" {
+		t.Errorf("incorrect outputs: %v", outputs)
+	}
+}
+
+func TestTranscribeBatchStop(t *testing.T) {
+	t.Parallel()
+
+	stop := make(chan struct{})
+	count := 0
+
+	// With one worker, stopping during the first file skips the others.
+	fn := func(context.Context, string, string) error {
+		count++
+		close(stop)
+
+		return nil
+	}
+
+	err := transcribeBatch(context.Background(), log.NewDiscardLogger(), []string{"a", "b", "c"}, formatJSON, 1, stop, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 1 {
+		t.Errorf("expected 1 file to be transcribed, got %d", count)
+	}
+}
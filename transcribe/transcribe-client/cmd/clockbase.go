@@ -0,0 +1,76 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cobaltspeech/examples-go/pkg/confidence"
+	transcribepb "github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5"
+)
+
+// clockBaseNow makes the clock base the time streaming starts.
+const clockBaseNow = "now"
+
+// clockTimeFormat is the format of the absolute word timestamps, RFC3339
+// with milliseconds.
+const clockTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// parseClockBase parses the --clock-base flag, which is empty (no clock
+// base), "now" or an RFC3339 time, e.g. 2026-10-16T09:30:00Z. A zero time
+// means no clock base.
+func parseClockBase(s string, now time.Time) (time.Time, error) {
+	switch s {
+	case "":
+		return time.Time{}, nil
+	case clockBaseNow:
+		return now, nil
+	}
+
+	base, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --clock-base %q, must be %q or an RFC3339 time: %w", s, clockBaseNow, err)
+	}
+
+	return base, nil
+}
+
+// formatWordTimes formats each word of the alternative on its own line, with
+// its absolute start and end times (the base plus its times relative to the
+// start of the stream) and, unless the confidence format is none, its
+// confidence:
+//
+//	2026-10-16T09:30:01.250Z	2026-10-16T09:30:01.600Z	hello (0.92)
+func formatWordTimes(alt *transcribepb.RecognitionAlternative, base time.Time, confFormat string) []string {
+	words := alt.GetWordDetails().GetFormatted()
+	lines := make([]string, 0, len(words))
+
+	for _, w := range words {
+		start := base.Add(time.Duration(w.StartTimeMs) * time.Millisecond)
+		end := start.Add(time.Duration(w.DurationMs) * time.Millisecond)
+
+		line := strings.Join([]string{start.Format(clockTimeFormat), end.Format(clockTimeFormat), w.Word}, "\t")
+
+		if c := confidence.Format(w.Confidence, confFormat); c != "" {
+			line += " " + c
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
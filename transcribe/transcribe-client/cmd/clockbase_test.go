@@ -0,0 +1,89 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cobaltspeech/examples-go/pkg/confidence"
+	transcribepb "github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5"
+)
+
+func TestParseClockBase(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+
+	testList := []struct {
+		flag     string
+		expected time.Time
+		valid    bool
+	}{
+		{"", time.Time{}, true},
+		{"now", now, true},
+		{"2026-10-16T11:00:00+02:00", time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC), true},
+		{"2026-10-16T09:00:00.5Z", time.Date(2026, 10, 16, 9, 0, 0, 5e8, time.UTC), true},
+		{"yesterday", time.Time{}, false},
+		{"2026-10-16 09:00:00", time.Time{}, false},
+	}
+
+	for _, test := range testList {
+		base, err := parseClockBase(test.flag, now)
+		if valid := err == nil; valid != test.valid {
+			t.Errorf("%q: expected valid=%t, got error: %v", test.flag, test.valid, err)
+		}
+
+		if !base.Equal(test.expected) {
+			t.Errorf("%q: incorrect base - expected: %v, actual: %v", test.flag, test.expected, base)
+		}
+	}
+}
+
+func TestFormatWordTimes(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	alt := &transcribepb.RecognitionAlternative{
+		TranscriptFormatted: "hello world",
+		WordDetails: &transcribepb.WordDetails{
+			Formatted: []*transcribepb.WordInfo{
+				{Word: "hello", Confidence: 0.92, StartTimeMs: 1250, DurationMs: 350},
+				{Word: "world", Confidence: 0.5, StartTimeMs: 61700, DurationMs: 400},
+			},
+		},
+	}
+
+	expected := []string{
+		"2026-10-16T09:30:01.250Z\t2026-10-16T09:30:01.600Z\thello",
+		"2026-10-16T09:31:01.700Z\t2026-10-16T09:31:02.100Z\tworld",
+	}
+	if actual := formatWordTimes(alt, base, confidence.None); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("incorrect word times - expected: %q, actual: %q", expected, actual)
+	}
+
+	// The times keep the time zone of the base.
+	local := base.In(time.FixedZone("", 2*60*60))
+	if actual := formatWordTimes(alt, local, confidence.Raw)[0]; actual !=
+		"2026-10-16T11:30:01.250+02:00\t2026-10-16T11:30:01.600+02:00\thello (0.92)" {
+		t.Errorf("incorrect word time: %q", actual)
+	}
+
+	// Without word details, there is nothing to list.
+	if actual := formatWordTimes(&transcribepb.RecognitionAlternative{}, base, confidence.None); len(actual) != 0 {
+		t.Errorf("expected no lines, got: %q", actual)
+	}
+}
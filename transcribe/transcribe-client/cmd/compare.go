@@ -0,0 +1,185 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/cobaltspeech/examples-go/pkg/wer"
+	"github.com/cobaltspeech/examples-go/transcribe/transcribe-client/internal/client"
+	transcribepb "github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5"
+	"github.com/cobaltspeech/log"
+
+	"github.com/spf13/cobra"
+)
+
+// recognizer is the part of the client used to compare models.
+type recognizer interface {
+	StreamingRecognize(ctx context.Context, cfg *transcribepb.RecognitionConfig,
+		audio io.Reader, handler client.RecognitionResponseHandler) error
+}
+
+func buildCompareModelsCmd() *cobra.Command {
+	var (
+		recCfgStr string
+		modelA    string
+		modelB    string
+		verbose   int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "compare-models --model-a <MODEL_ID> --model-b <MODEL_ID> <AUDIO_FILE>",
+		Short: "Transcribe an audio file with two models and compare the transcripts.",
+		Long: "Transcribe the same audio file with two models concurrently, then print a word-level " +
+			"diff of the transcripts and the word error rate of model B, using model A as the reference.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 1 || modelA == "" || modelB == "" {
+				cmd.PrintErr(cmd.UsageString())
+
+				return
+			}
+
+			logger := log.NewLeveledLogger(log.WithFilterLevel(getLogLevel(verbose)))
+
+			opts, err := connectionOptions()
+			if err != nil {
+				cmd.PrintErrf("error: %v\n", err)
+
+				return
+			}
+
+			opts = append(opts, client.WithLogger(logger))
+
+			c, err := client.NewClient(serverAddress, opts...)
+			if err != nil {
+				cmd.PrintErrf("error: failed to create a client: %v\n", err)
+
+				return
+			}
+
+			defer c.Close()
+
+			audio, err := os.ReadFile(args[0])
+			if err != nil {
+				cmd.PrintErrf("error: failed to read audio file: %v\n", err)
+
+				return
+			}
+
+			if err := compareModels(context.Background(), os.Stdout, c, recCfgStr, audio, modelA, modelB); err != nil {
+				cmd.PrintErrf("error: %v\n", err)
+
+				return
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&modelA, "model-a", "", "ID of the reference model.")
+	cmd.Flags().StringVar(&modelB, "model-b", "", "ID of the model compared against the reference.")
+	cmd.Flags().StringVarP(&recCfgStr, "recognition-config", "r", "{}",
+		"Json string to configure recognition. The model ID is replaced by --model-a and --model-b.")
+	cmd.Flags().IntVarP(&verbose, "verbose", "v", 0, "Logger verbose modes. 0=Info, 1=Debug, 2=Trace")
+
+	return cmd
+}
+
+// compareModels transcribes the audio with both models concurrently, and
+// writes the word-level diff and WER of the transcripts to w.
+func compareModels(ctx context.Context, w io.Writer, r recognizer,
+	recCfgStr string, audio []byte, modelA, modelB string) error {
+	models := []string{modelA, modelB}
+	transcripts := make([]string, len(models))
+	errs := make([]error, len(models))
+
+	// Each stream gets its own copy of the recognition config.
+	cfgs := make([]*transcribepb.RecognitionConfig, len(models))
+
+	for i, id := range models {
+		cfg, err := parseRecognitionConfig(recCfgStr)
+		if err != nil {
+			return fmt.Errorf("failed to parse recognition config: %w", err)
+		}
+
+		cfg.ModelId = id
+		cfgs[i] = cfg
+	}
+
+	var wg sync.WaitGroup
+
+	for i := range models {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			transcripts[i], errs[i] = finalTranscript(ctx, r, cfgs[i], bytes.NewReader(audio))
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("failed to transcribe with model %q: %w", models[i], err)
+		}
+	}
+
+	ref := strings.Fields(transcripts[0])
+	hyp := strings.Fields(transcripts[1])
+	edits := wer.Align(ref, hyp)
+
+	fmt.Fprintf(w, "A (%s): %s\n", modelA, transcripts[0])
+	fmt.Fprintf(w, "B (%s): %s\n\n", modelB, transcripts[1])
+
+	for _, e := range edits {
+		switch e.Op {
+		case wer.Equal:
+			fmt.Fprintf(w, "  %s\n", e.Ref)
+		case wer.Substitute:
+			fmt.Fprintf(w, "~ %s -> %s\n", e.Ref, e.Hyp)
+		case wer.Delete:
+			fmt.Fprintf(w, "- %s\n", e.Ref)
+		case wer.Insert:
+			fmt.Fprintf(w, "+ %s\n", e.Hyp)
+		}
+	}
+
+	c := wer.Count(edits)
+
+	fmt.Fprintf(w, "\nWER: %.2f%% (substitutions=%d deletions=%d insertions=%d reference words=%d)\n",
+		100*c.Rate(), c.Substitutions, c.Deletions, c.Insertions, c.RefWords) //nolint:gomnd // converting to a percentage
+
+	return nil
+}
+
+// finalTranscript returns the final (non-partial) transcripts of the audio
+// joined by spaces.
+func finalTranscript(ctx context.Context, r recognizer, cfg *transcribepb.RecognitionConfig, audio io.Reader) (string, error) {
+	var parts []string
+
+	err := r.StreamingRecognize(ctx, cfg, audio, func(resp *transcribepb.StreamingRecognizeResponse) {
+		if resp.Result != nil && !resp.Result.IsPartial && len(resp.Result.Alternatives) > 0 {
+			parts = append(parts, resp.Result.Alternatives[0].TranscriptFormatted)
+		}
+	})
+
+	return strings.Join(parts, " "), err
+}
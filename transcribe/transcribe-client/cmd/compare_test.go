@@ -0,0 +1,89 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cobaltspeech/examples-go/transcribe/transcribe-client/internal/client"
+	transcribepb "github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5"
+)
+
+// fakeRecognizer returns a canned transcript for each model, split into one
+// result per word.
+type fakeRecognizer struct {
+	transcripts map[string]string
+}
+
+func (f *fakeRecognizer) StreamingRecognize(ctx context.Context, cfg *transcribepb.RecognitionConfig,
+	audio io.Reader, handler client.RecognitionResponseHandler) error {
+	if _, err := io.Copy(io.Discard, audio); err != nil {
+		return err
+	}
+
+	transcript, ok := f.transcripts[cfg.ModelId]
+	if !ok {
+		return fmt.Errorf("invalid model requested: %s", cfg.ModelId)
+	}
+
+	for _, word := range strings.Fields(transcript) {
+		handler(&transcribepb.StreamingRecognizeResponse{
+			Result: &transcribepb.RecognitionResult{
+				Alternatives: []*transcribepb.RecognitionAlternative{{TranscriptFormatted: word}},
+			},
+		})
+	}
+
+	return nil
+}
+
+func TestCompareModels(t *testing.T) {
+	t.Parallel()
+
+	r := &fakeRecognizer{transcripts: map[string]string{
+		"a": "the quick brown fox jumps",
+		"b": "the quick brown box jumps high",
+	}}
+
+	var buf bytes.Buffer
+	if err := compareModels(context.Background(), &buf, r, "{}", []byte("audio"), "a", "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `A (a): the quick brown fox jumps
+B (b): the quick brown box jumps high
+
+  the
+  quick
+  brown
+~ fox -> box
+  jumps
++ high
+
+WER: 40.00% (substitutions=1 deletions=0 insertions=1 reference words=5)
+`
+	if actual := buf.String(); actual != expected {
+		t.Errorf("incorrect output - expected:\n%s\nactual:\n%s", expected, actual)
+	}
+
+	if err := compareModels(context.Background(), &buf, r, "{}", nil, "a", "missing"); err == nil {
+		t.Error("expected an error for an unknown model")
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// effectiveConfig holds the connection settings in effect after the global
+// flags have been parsed. Secrets are redacted.
+type effectiveConfig struct {
+	Server          string            `json:"server"`
+	Insecure        bool              `json:"insecure"`
+	TLSMinVersion   string            `json:"tls_min_version"`
+	TLSCipherSuites []string          `json:"tls_cipher_suites"`
+	TLSCACert       string            `json:"tls_ca_cert"`
+	TLSServerName   string            `json:"tls_server_name"`
+	TLSClientCert   string            `json:"tls_client_cert"`
+	TLSClientKey    string            `json:"tls_client_key"`
+	AuthToken       string            `json:"auth_token"`
+	Metadata        map[string]string `json:"metadata"`
+}
+
+// redacted replaces the value of secrets in the dumped configuration.
+const redacted = "<redacted>"
+
+// currentConfig returns the effective configuration, with the auth token and
+// the authorization metadata redacted.
+func currentConfig() effectiveConfig {
+	cfg := effectiveConfig{
+		Server:          serverAddress,
+		Insecure:        isInsecure,
+		TLSMinVersion:   tlsMinVersion,
+		TLSCipherSuites: tlsCipherSuites,
+		TLSCACert:       tlsCACert,
+		TLSServerName:   tlsServerName,
+		TLSClientCert:   tlsClientCert,
+		TLSClientKey:    tlsClientKey,
+		Metadata:        make(map[string]string, len(grpcMetadata)),
+	}
+
+	if authToken != "" {
+		cfg.AuthToken = redacted
+	}
+
+	for k, v := range grpcMetadata {
+		// gRPC metadata keys are case insensitive.
+		if strings.EqualFold(k, "authorization") {
+			v = redacted
+		}
+
+		cfg.Metadata[k] = v
+	}
+
+	return cfg
+}
+
+func buildConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the client configuration.",
+	}
+
+	var format string
+
+	dumpCmd := &cobra.Command{
+		Use:   "dump",
+		Short: "Print the effective configuration.",
+		Long: "Print the configuration in effect once all flags have been applied, " +
+			"e.g. to check which server the client will connect to.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := dumpConfig(cmd.OutOrStdout(), currentConfig(), format); err != nil {
+				cmd.PrintErrf("error: %v\n", err)
+
+				return
+			}
+		},
+	}
+
+	dumpCmd.Flags().StringVar(&format, "format", "toml", "Output format, toml or json.")
+	cmd.AddCommand(dumpCmd)
+
+	return cmd
+}
+
+// dumpConfig writes cfg to w in the given format.
+func dumpConfig(w io.Writer, cfg effectiveConfig, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(cfg)
+	case "toml":
+		quoted := make([]string, len(cfg.TLSCipherSuites))
+		for i, s := range cfg.TLSCipherSuites {
+			quoted[i] = strconv.Quote(s)
+		}
+
+		keys := make([]string, 0, len(cfg.Metadata))
+		for k := range cfg.Metadata {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		pairs := make([]string, len(keys))
+		for i, k := range keys {
+			pairs[i] = strconv.Quote(k) + " = " + strconv.Quote(cfg.Metadata[k])
+		}
+
+		var b strings.Builder
+
+		fmt.Fprintf(&b, "server = %s\ninsecure = %t\n", strconv.Quote(cfg.Server), cfg.Insecure)
+		fmt.Fprintf(&b, "tls_min_version = %s\ntls_cipher_suites = [%s]\n",
+			strconv.Quote(cfg.TLSMinVersion), strings.Join(quoted, ", "))
+		fmt.Fprintf(&b, "tls_ca_cert = %s\ntls_server_name = %s\n", strconv.Quote(cfg.TLSCACert), strconv.Quote(cfg.TLSServerName))
+		fmt.Fprintf(&b, "tls_client_cert = %s\ntls_client_key = %s\n",
+			strconv.Quote(cfg.TLSClientCert), strconv.Quote(cfg.TLSClientKey))
+		fmt.Fprintf(&b, "auth_token = %s\nmetadata = {%s}\n", strconv.Quote(cfg.AuthToken), strings.Join(pairs, ", "))
+
+		_, err := io.WriteString(w, b.String())
+
+		return err
+	default:
+		return fmt.Errorf("unsupported format %q, must be toml or json", format)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/cobaltspeech/examples-go/pkg/envconfig"
+	"github.com/spf13/cobra"
+)
+
+// TestDumpConfig changes the global flag variables, so it must not run in
+// parallel with tests that read them.
+func TestDumpConfig(t *testing.T) {
+	defer func(addr, minVersion, caCert, serverName, clientCert, clientKey, token string, md map[string]string) {
+		serverAddress, tlsMinVersion = addr, minVersion
+		tlsCACert, tlsServerName, tlsClientCert, tlsClientKey = caCert, serverName, clientCert, clientKey
+		authToken, grpcMetadata = token, md
+	}(serverAddress, tlsMinVersion, tlsCACert, tlsServerName, tlsClientCert, tlsClientKey, authToken, grpcMetadata)
+
+	serverAddress = "transcribe.example.com:443"
+	tlsMinVersion = "1.3"
+	tlsCACert = "ca.pem"
+	tlsServerName = "transcribe.internal"
+	tlsClientCert = "client.pem"
+	tlsClientKey = "client.key"
+	authToken = "secret"
+	grpcMetadata = map[string]string{"x-tenant": "acme", "Authorization": "Bearer secret"}
+
+	var buf bytes.Buffer
+	if err := dumpConfig(&buf, currentConfig(), "toml"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `server = "transcribe.example.com:443"
+insecure = false
+tls_min_version = "1.3"
+tls_cipher_suites = []
+tls_ca_cert = "ca.pem"
+tls_server_name = "transcribe.internal"
+tls_client_cert = "client.pem"
+tls_client_key = "client.key"
+auth_token = "<redacted>"
+metadata = {"Authorization" = "<redacted>", "x-tenant" = "acme"}
+`
+	if actual := buf.String(); actual != expected {
+		t.Errorf("incorrect toml output - expected: %q, actual: %q", expected, actual)
+	}
+
+	buf.Reset()
+
+	if err := dumpConfig(&buf, currentConfig(), "json"); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg effectiveConfig
+	if err := json.Unmarshal(buf.Bytes(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Server != serverAddress || cfg.TLSMinVersion != "1.3" || cfg.TLSClientCert != "client.pem" {
+		t.Errorf("json output does not reflect the overrides: %+v", cfg)
+	}
+
+	if bytes.Contains(buf.Bytes(), []byte("secret")) {
+		t.Errorf("json output has secrets: %s", buf.String())
+	}
+
+	if cfg.AuthToken != redacted || cfg.Metadata["Authorization"] != redacted || cfg.Metadata["x-tenant"] != "acme" {
+		t.Errorf("incorrect redaction: %+v", cfg)
+	}
+
+	// Unset secrets are not shown as redacted.
+	authToken = ""
+
+	if cfg := currentConfig(); cfg.AuthToken != "" {
+		t.Errorf("unexpected auth token: %q", cfg.AuthToken)
+	}
+
+	if err := dumpConfig(&buf, currentConfig(), "yaml"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+// TestApplyEnvOverrides changes the global flag variables, so it must not run
+// in parallel with tests that read them.
+func TestApplyEnvOverrides(t *testing.T) {
+	defer func(addr string, insecure bool) {
+		serverAddress, isInsecure = addr, insecure
+	}(serverAddress, isInsecure)
+
+	t.Setenv(envconfig.ServerAddress, "transcribe.example.com:443")
+	t.Setenv(envconfig.Insecure, "true")
+
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().StringVar(&serverAddress, "server", "127.0.0.1:2727", "")
+		cmd.Flags().BoolVar(&isInsecure, "insecure", false, "")
+
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatal(err)
+		}
+
+		return cmd
+	}
+
+	// The environment overrides the defaults.
+	if err := applyEnvOverrides(newCmd()); err != nil {
+		t.Fatal(err)
+	}
+
+	if serverAddress != "transcribe.example.com:443" || !isInsecure {
+		t.Errorf("defaults not overridden: %q, %t", serverAddress, isInsecure)
+	}
+
+	// The flags override the environment.
+	if err := applyEnvOverrides(newCmd("--server", "localhost:2727", "--insecure=false")); err != nil {
+		t.Fatal(err)
+	}
+
+	if serverAddress != "localhost:2727" || isInsecure {
+		t.Errorf("flags overridden: %q, %t", serverAddress, isInsecure)
+	}
+
+	t.Setenv(envconfig.Insecure, "maybe")
+
+	if err := applyEnvOverrides(newCmd()); err == nil {
+		t.Error("expected an error for an invalid COBALT_INSECURE value")
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	transcribepb "github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5"
+)
+
+// contextBoostSep separates a context phrase from its optional boost, e.g.
+// "Cobalt Speech|2.5".
+const contextBoostSep = "|"
+
+// contextCompiler is the part of the client used to compile recognition
+// context.
+type contextCompiler interface {
+	ListModels(ctx context.Context) ([]*transcribepb.Model, error)
+	CompileContext(ctx context.Context, modelID, token string,
+		phrases []*transcribepb.ContextPhrase) (*transcribepb.CompiledContext, error)
+}
+
+// errEmptyContext is returned when the server compiles the context phrases
+// to nothing.
+var errEmptyContext = errors.New("the server returned an empty compiled context")
+
+// parseContextPhrases parses the values of the --context-phrases flag. Each
+// value is either a phrase, or the path to a file with one phrase per line
+// where empty lines and lines starting with "#" are ignored. Phrases can be
+// followed by a boost, e.g. "Cobalt Speech|2.5".
+func parseContextPhrases(values []string) ([]*transcribepb.ContextPhrase, error) {
+	var phrases []*transcribepb.ContextPhrase
+
+	for _, v := range values {
+		if info, err := os.Stat(v); err != nil || !info.Mode().IsRegular() {
+			p, err := parseContextPhrase(v)
+			if err != nil {
+				return nil, err
+			}
+
+			phrases = append(phrases, p)
+
+			continue
+		}
+
+		filePhrases, err := readContextPhrases(v)
+		if err != nil {
+			return nil, err
+		}
+
+		phrases = append(phrases, filePhrases...)
+	}
+
+	return phrases, nil
+}
+
+// readContextPhrases reads the phrases of a context phrases file.
+func readContextPhrases(path string) ([]*transcribepb.ContextPhrase, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open context phrases file: %w", err)
+	}
+
+	defer f.Close()
+
+	var phrases []*transcribepb.ContextPhrase
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		s := strings.TrimSpace(scanner.Text())
+		if s == "" || strings.HasPrefix(s, "#") {
+			continue
+		}
+
+		p, err := parseContextPhrase(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+
+		phrases = append(phrases, p)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read context phrases file: %w", err)
+	}
+
+	return phrases, nil
+}
+
+// parseContextPhrase parses a phrase with an optional boost.
+func parseContextPhrase(s string) (*transcribepb.ContextPhrase, error) {
+	p := &transcribepb.ContextPhrase{Text: s}
+
+	if i := strings.LastIndex(s, contextBoostSep); i >= 0 {
+		boost, err := strconv.ParseFloat(strings.TrimSpace(s[i+1:]), 32) //nolint:gomnd // float32 boost
+		if err != nil || boost <= 0 {
+			return nil, fmt.Errorf("invalid boost in context phrase %q, must be a positive number", s)
+		}
+
+		p.Text = strings.TrimSpace(s[:i])
+		p.Boost = float32(boost)
+	}
+
+	if p.Text == "" {
+		return nil, fmt.Errorf("empty context phrase %q", s)
+	}
+
+	return p, nil
+}
+
+// addRecognitionContext compiles the phrases for the model of the recognition
+// config and adds the compiled context to it. The token must be one of the
+// context tokens allowed by the model; if empty, the first one is used.
+func addRecognitionContext(ctx context.Context, c contextCompiler, cfg *transcribepb.RecognitionConfig,
+	phrases []*transcribepb.ContextPhrase, token string) error {
+	models, err := c.ListModels(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list models: %w", err)
+	}
+
+	var info *transcribepb.ContextInfo
+
+	for _, m := range models {
+		if m.Id == cfg.ModelId {
+			info = m.GetAttributes().GetContextInfo()
+
+			break
+		}
+	}
+
+	if !info.GetSupportsContext() {
+		return fmt.Errorf("model %q does not support context compilation", cfg.ModelId)
+	}
+
+	allowed := info.GetAllowedContextTokens()
+
+	switch {
+	case token == "" && len(allowed) == 0:
+		return fmt.Errorf("model %q does not allow any context token", cfg.ModelId)
+	case token == "":
+		token = allowed[0]
+	case !containsString(allowed, token):
+		return fmt.Errorf("context token %q is not allowed by model %q, must be one of %s",
+			token, cfg.ModelId, strings.Join(allowed, ", "))
+	}
+
+	compiled, err := c.CompileContext(ctx, cfg.ModelId, token, phrases)
+	if err != nil {
+		return fmt.Errorf("failed to compile context: %w", err)
+	}
+
+	if len(compiled.GetData()) == 0 {
+		return errEmptyContext
+	}
+
+	if cfg.Context == nil {
+		cfg.Context = &transcribepb.RecognitionContext{}
+	}
+
+	cfg.Context.Compiled = append(cfg.Context.Compiled, compiled)
+
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,123 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	transcribepb "github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5"
+)
+
+func TestParseContextPhrases(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "phrases.txt")
+	if err := os.WriteFile(path, []byte("# names\nAda Lovelace|3\n\n  Grace Hopper  \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	phrases, err := parseContextPhrases([]string{"Cobalt Speech|2.5", path, "transcribe"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*transcribepb.ContextPhrase{
+		{Text: "Cobalt Speech", Boost: 2.5},
+		{Text: "Ada Lovelace", Boost: 3},
+		{Text: "Grace Hopper"},
+		{Text: "transcribe"},
+	}
+
+	if len(phrases) != len(expected) {
+		t.Fatalf("incorrect number of phrases - expected: %d, actual: %d", len(expected), len(phrases))
+	}
+
+	for i := range expected {
+		if phrases[i].Text != expected[i].Text || phrases[i].Boost != expected[i].Boost {
+			t.Errorf("phrase %d - expected: %v, actual: %v", i, expected[i], phrases[i])
+		}
+	}
+
+	for _, v := range []string{"bad boost|x", "negative|-1", "|2", ""} {
+		if _, err := parseContextPhrases([]string{v}); err == nil {
+			t.Errorf("expected an error for %q", v)
+		}
+	}
+}
+
+// fakeContextCompiler is a contextCompiler with one model.
+type fakeContextCompiler struct {
+	info     *transcribepb.ContextInfo
+	compiled *transcribepb.CompiledContext
+	token    string // token of the last CompileContext call
+}
+
+func (f *fakeContextCompiler) ListModels(context.Context) ([]*transcribepb.Model, error) {
+	return []*transcribepb.Model{{Id: "model", Attributes: &transcribepb.ModelAttributes{ContextInfo: f.info}}}, nil
+}
+
+func (f *fakeContextCompiler) CompileContext(_ context.Context, _, token string,
+	_ []*transcribepb.ContextPhrase) (*transcribepb.CompiledContext, error) {
+	f.token = token
+
+	return f.compiled, nil
+}
+
+func TestAddRecognitionContext(t *testing.T) {
+	t.Parallel()
+
+	supported := &transcribepb.ContextInfo{SupportsContext: true, AllowedContextTokens: []string{"names", "places"}}
+	compiled := &transcribepb.CompiledContext{Data: []byte{1, 2, 3}}
+	phrases := []*transcribepb.ContextPhrase{{Text: "Cobalt"}}
+
+	testList := []struct {
+		name          string
+		compiler      *fakeContextCompiler
+		token         string
+		expectedToken string
+		expectedErr   bool
+	}{
+		{"default token", &fakeContextCompiler{info: supported, compiled: compiled}, "", "names", false},
+		{"token", &fakeContextCompiler{info: supported, compiled: compiled}, "places", "places", false},
+		{"disallowed token", &fakeContextCompiler{info: supported, compiled: compiled}, "other", "", true},
+		{"unsupported", &fakeContextCompiler{compiled: compiled}, "", "", true},
+		{"empty context", &fakeContextCompiler{info: supported, compiled: &transcribepb.CompiledContext{}}, "", "names", true},
+	}
+
+	for _, test := range testList {
+		cfg := &transcribepb.RecognitionConfig{ModelId: "model"}
+
+		err := addRecognitionContext(context.Background(), test.compiler, cfg, phrases, test.token)
+		if test.expectedErr != (err != nil) {
+			t.Errorf("%s: unexpected error result: %v", test.name, err)
+		}
+
+		if test.compiler.token != test.expectedToken {
+			t.Errorf("%s: incorrect token - expected: %q, actual: %q", test.name, test.expectedToken, test.compiler.token)
+		}
+
+		if err == nil && (cfg.Context == nil || len(cfg.Context.Compiled) != 1 || cfg.Context.Compiled[0] != compiled) {
+			t.Errorf("%s: compiled context not added to the config: %v", test.name, cfg.Context)
+		}
+
+		if test.name == "empty context" && !errors.Is(err, errEmptyContext) {
+			t.Errorf("expected errEmptyContext, got: %v", err)
+		}
+	}
+}
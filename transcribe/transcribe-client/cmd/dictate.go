@@ -0,0 +1,268 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/cobaltspeech/examples-go/pkg/audio"
+	"github.com/cobaltspeech/examples-go/pkg/confidence"
+	"github.com/cobaltspeech/examples-go/transcribe/transcribe-client/internal/client"
+	transcribepb "github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5"
+	"github.com/cobaltspeech/log"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultRecorderApp  = "sox"
+	defaultRecorderArgs = "-q -d -c 1 -r 16000 -b 16 -L -e signed -t raw -"
+	defaultDictateRate  = 16000
+	dictateBitDepth     = 16
+
+	// dictateClipPercent is the percentage of clipped samples in the
+	// recorded audio above which a warning about the input level is logged.
+	dictateClipPercent = 1.0
+)
+
+// dictateOptions configures the processing of the recorded audio and the
+// printed hypotheses.
+type dictateOptions struct {
+	normalizeDBFS float64 // normalizeDBFS is the target level of the recorded audio, zero to disable it.
+	clockBase     string  // clockBase, if set, prints the words with their times offset from it.
+}
+
+func buildDictateCmd() *cobra.Command {
+	var (
+		recCfgStr  string
+		verbose    int
+		recApp     string
+		recArgs    string
+		sampleRate uint32
+		reqModel   bool
+		capture    string
+		dictOpts   dictateOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "dictate",
+		Short: "Transcribe audio from the microphone.",
+		Long: "Stream audio from a recording application to the server and print the hypotheses live, " +
+			"updating the current line with partial results. The recording application must write " +
+			"mono 16-bit signed little-endian raw audio to STDOUT at the given sample rate. " +
+			"Press Ctrl-C to stop recording and print the final hypothesis.",
+		Run: func(cmd *cobra.Command, args []string) {
+			logger := log.NewLeveledLogger(log.WithFilterLevel(getLogLevel(verbose)))
+
+			if _, err := parseClockBase(dictOpts.clockBase, time.Now()); err != nil {
+				cmd.PrintErrf("error: %v\n", err)
+
+				return
+			}
+
+			opts, err := connectionOptions()
+			if err != nil {
+				cmd.PrintErrf("error: %v\n", err)
+
+				return
+			}
+
+			opts = append(opts, client.WithLogger(logger))
+
+			c, err := client.NewClient(serverAddress, opts...)
+			if err != nil {
+				cmd.PrintErrf("error: failed to create a client: %v\n", err)
+
+				return
+			}
+
+			defer c.Close()
+
+			// Ctrl-C stops the recording, and the final results are still
+			// printed.
+			ctx, stopAudio, stopInterrupts := notifyInterrupts(context.Background(), logger)
+			defer stopInterrupts()
+
+			cfg, err := buildRecognitionConfig(ctx, logger, c, recCfgStr, reqModel, nil, "")
+			if err != nil {
+				cmd.PrintErrf("error: %v\n", err)
+
+				return
+			}
+
+			cfg.AudioFormat = &transcribepb.RecognitionConfig_AudioFormatRaw{
+				AudioFormatRaw: &transcribepb.AudioFormatRAW{
+					Encoding:   transcribepb.AudioEncoding_AUDIO_ENCODING_SIGNED,
+					BitDepth:   dictateBitDepth,
+					ByteOrder:  transcribepb.ByteOrder_BYTE_ORDER_LITTLE_ENDIAN,
+					SampleRate: sampleRate,
+					Channels:   1,
+				},
+			}
+
+			// The word times are computed from the word details.
+			if dictOpts.clockBase != "" {
+				cfg.EnableWordDetails = true
+			}
+
+			recCfg := audio.Config{Application: recApp, Args: recArgs, SampleRate: int(sampleRate), CapturePath: capture}
+
+			if err := dictate(ctx, logger, c, cfg, recCfg, dictOpts, stopAudio); err != nil {
+				cmd.PrintErrf("error: %v\n", err)
+
+				return
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&recCfgStr, "recognition-config", "r", "{}", "Json string to configure recognition. "+
+		"The audio format is set from the recording flags. "+
+		"See https://pkg.go.dev/github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5#RecognitionConfig for more details.")
+	cmd.Flags().IntVarP(&verbose, "verbose", "v", 0, "Logger verbose modes. 0=Info, 1=Debug, 2=Trace")
+	cmd.Flags().StringVar(&recApp, "recorder", defaultRecorderApp, "Recording application writing raw audio to STDOUT.")
+	cmd.Flags().StringVar(&recArgs, "recorder-args", defaultRecorderArgs, "Space separated arguments of the recording application.")
+	cmd.Flags().Uint32Var(&sampleRate, "sample-rate", defaultDictateRate, "Sample rate of the recorded audio in Hz.")
+	cmd.Flags().BoolVar(&reqModel, "require-model", false,
+		"If flag provided, the model must be set in the recognition config instead of defaulting to the first available model.")
+	cmd.Flags().Float64Var(&dictOpts.normalizeDBFS, "normalize", 0,
+		"Normalize the recorded audio toward the given level in dBFS (e.g. -20). 0 disables normalization.")
+	cmd.Flags().StringVar(&capture, "capture", "", "Save the recorded audio to this WAV file, e.g. to debug bad transcripts.")
+	cmd.Flags().StringVar(&dictOpts.clockBase, "clock-base", "",
+		"If provided, each final hypothesis is printed as its words with their absolute start and end times, "+
+			"offset from this base: \"now\" (when recording starts) or an RFC3339 time (e.g. 2026-10-16T09:30:00Z). "+
+			"Enables word details in the recognition config.")
+
+	return cmd
+}
+
+// dictate streams the audio of the recording application to the server and
+// prints the hypotheses to STDOUT. The recorded audio is checked for clipping
+// and optionally normalized, the same way as by the diatheke audio client.
+// Once stopAudio is closed, the recording stops, and the final results for the
+// audio already sent are still printed.
+func dictate(ctx context.Context, logger log.Logger, c *client.Client, cfg *transcribepb.RecognitionConfig,
+	recCfg audio.Config, opts dictateOptions, stopAudio <-chan struct{}) error {
+	if err := recCfg.Validate(); err != nil {
+		return fmt.Errorf("invalid recorder: %w", err)
+	}
+
+	rec := audio.NewSourceFromConfig(recCfg)
+	if err := rec.Start(); err != nil {
+		return fmt.Errorf("failed to start the recorder: %w", err)
+	}
+
+	logger.Info("msg", "recording, press Ctrl-C to stop")
+
+	// The clock base "now" is when recording starts.
+	base, err := parseClockBase(opts.clockBase, time.Now())
+	if err != nil {
+		return err
+	}
+
+	warnClipping := func(percent float64) {
+		logger.Info("msg", "warning: the recorded audio is clipped, lower the input level for better accuracy",
+			"clipped percent", percent)
+	}
+
+	p := &dictationPrinter{w: os.Stdout, clockBase: base}
+	r := dictationAudio(&stopReader{r: rec, stop: stopAudio}, opts.normalizeDBFS, warnClipping)
+
+	err = c.StreamingRecognize(ctx, cfg, r, p.print)
+
+	p.finish()
+
+	// The recorder keeps running after the audio is stopped. If it
+	// crashed, the audio ended because of that rather than Ctrl-C, and
+	// the error includes its stderr output.
+	if recErr := rec.Stop(); recErr != nil {
+		return recErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to transcribe: %w", err)
+	}
+
+	return nil
+}
+
+// dictationAudio wraps the recorded audio with a clip detector calling onClip
+// and, unless normalizeDBFS is zero, a normalizer.
+func dictationAudio(r io.Reader, normalizeDBFS float64, onClip func(percent float64)) io.Reader {
+	r = audio.NewClipDetector(r, dictateClipPercent, onClip)
+
+	if normalizeDBFS == 0 {
+		return r
+	}
+
+	return audio.NewNormalizer(r, normalizeDBFS)
+}
+
+// clearLine is the terminal escape sequence returning to the start of the line
+// and clearing it.
+const clearLine = "\r\033[K"
+
+// dictationPrinter prints hypotheses as they arrive: partial results replace
+// the current line, and final results end it. With a clock base, final
+// results are printed as their words with absolute times instead.
+type dictationPrinter struct {
+	w         io.Writer
+	partial   bool      // whether the current line has a partial result
+	clockBase time.Time // prints the words of final results offset from it, if not zero
+}
+
+func (p *dictationPrinter) print(resp *transcribepb.StreamingRecognizeResponse) {
+	if resp.Result == nil || len(resp.Result.Alternatives) == 0 {
+		return
+	}
+
+	alt := resp.Result.Alternatives[0]
+	text := alt.TranscriptFormatted
+
+	if p.partial {
+		fmt.Fprint(p.w, clearLine)
+	}
+
+	if resp.Result.IsPartial {
+		fmt.Fprint(p.w, text)
+		p.partial = true
+
+		return
+	}
+
+	p.partial = false
+
+	if !p.clockBase.IsZero() && len(alt.GetWordDetails().GetFormatted()) > 0 {
+		for _, line := range formatWordTimes(alt, p.clockBase, confidence.None) {
+			fmt.Fprintln(p.w, line)
+		}
+
+		return
+	}
+
+	fmt.Fprintln(p.w, text)
+}
+
+// finish ends the current line if it has a partial result that was never
+// finalized.
+func (p *dictationPrinter) finish() {
+	if p.partial {
+		fmt.Fprintln(p.w)
+		p.partial = false
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cobaltspeech/examples-go/pkg/audio"
+	"github.com/cobaltspeech/examples-go/transcribe/transcribe-client/internal/client"
+	transcribepb "github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5"
+	"github.com/cobaltspeech/log"
+)
+
+func TestDictationPrinter(t *testing.T) {
+	t.Parallel()
+
+	partial := func(s string) *transcribepb.StreamingRecognizeResponse {
+		resp := newTestResult(s, 0, 0)
+		resp.Result.IsPartial = true
+
+		return resp
+	}
+
+	var buf bytes.Buffer
+
+	p := &dictationPrinter{w: &buf}
+	for _, resp := range []*transcribepb.StreamingRecognizeResponse{
+		partial("hello"),
+		partial("hello wor"),
+		newTestResult("Hello world.", 0, 0),
+		newTestResult("Final only.", 0, 0),
+		partial("never final"),
+	} {
+		p.print(resp)
+	}
+
+	p.finish()
+
+	expected := "hello" + clearLine + "hello wor" + clearLine + "Hello world.\n" +
+		"Final only.\n" + "never final\n"
+
+	if actual := buf.String(); actual != expected {
+		t.Errorf("incorrect output - expected: %q, actual: %q", expected, actual)
+	}
+}
+
+func TestDictationPrinterClockBase(t *testing.T) {
+	t.Parallel()
+
+	final := newTestResult("Hello world.", 0, 0)
+	final.Result.Alternatives[0].WordDetails = &transcribepb.WordDetails{
+		Formatted: []*transcribepb.WordInfo{
+			{Word: "Hello", StartTimeMs: 1250, DurationMs: 350},
+			{Word: "world.", StartTimeMs: 1700, DurationMs: 400},
+		},
+	}
+
+	partial := newTestResult("hello", 0, 0)
+	partial.Result.IsPartial = true
+
+	var buf bytes.Buffer
+
+	p := &dictationPrinter{w: &buf, clockBase: time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)}
+	p.print(partial)
+	p.print(final)
+	p.print(newTestResult("No words.", 0, 0))
+
+	expected := "hello" + clearLine +
+		"2026-10-16T09:30:01.250Z\t2026-10-16T09:30:01.600Z\tHello\n" +
+		"2026-10-16T09:30:01.700Z\t2026-10-16T09:30:02.100Z\tworld.\n" +
+		"No words.\n"
+
+	if actual := buf.String(); actual != expected {
+		t.Errorf("incorrect output - expected: %q, actual: %q", expected, actual)
+	}
+}
+
+// testSamples returns n samples of 16-bit audio with the given value.
+func testSamples(n int, value int16) []byte {
+	b := make([]byte, 2*n)
+	for i := 0; i < n; i++ {
+		binary.LittleEndian.PutUint16(b[2*i:], uint16(value))
+	}
+
+	return b
+}
+
+func TestDictationAudio(t *testing.T) {
+	t.Parallel()
+
+	input := testSamples(16000, 32767)
+
+	var clipped float64
+
+	b, err := io.ReadAll(dictationAudio(bytes.NewReader(input), 0, func(percent float64) { clipped = percent }))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(b, input) {
+		t.Error("audio changed without normalization")
+	}
+
+	if clipped < dictateClipPercent {
+		t.Errorf("clipping not reported, percent: %v", clipped)
+	}
+
+	b, err = io.ReadAll(dictationAudio(bytes.NewReader(input), -20, func(float64) {}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(b) != len(input) || bytes.Equal(b, input) {
+		t.Error("audio not normalized")
+	}
+}
+
+func TestDictateCapture(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	input := testSamples(1600, 1000)
+	source := filepath.Join(dir, "input.raw")
+	capture := filepath.Join(dir, "capture.wav")
+
+	if err := os.WriteFile(source, input, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// cat plays the part of the recording application.
+	recCfg := audio.Config{Application: "cat", Args: source, SampleRate: 16000, CapturePath: capture}
+	svc := &client.FakeTranscribeService{}
+
+	err := dictate(context.Background(), log.NewDiscardLogger(), client.NewFakeClient(svc), &transcribepb.RecognitionConfig{},
+		recCfg, dictateOptions{normalizeDBFS: -20}, make(chan struct{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(svc.Streams) != 1 {
+		t.Fatalf("incorrect number of streams: %d", len(svc.Streams))
+	}
+
+	if sent := svc.Streams[0].Audio(); len(sent) != len(input) || sent == string(input) {
+		t.Errorf("audio sent without normalization, %d bytes", len(sent))
+	}
+
+	// The capture has the recorded audio, before normalization.
+	wav, err := os.ReadFile(capture)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.HasSuffix(wav, input) {
+		t.Errorf("incorrect capture, %d bytes", len(wav))
+	}
+}
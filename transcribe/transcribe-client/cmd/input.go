@@ -0,0 +1,101 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Suffixes of encoded input files, which can be stacked, e.g. "clip.wav.gz.b64"
+// is a base64 encoded, gzip compressed WAV file.
+const (
+	suffixGzip   = ".gz"
+	suffixBase64 = ".b64"
+)
+
+// decodeInput returns a reader decoding the input file according to the
+// encoding suffixes of its path, outermost (last) suffix first. Errors while
+// decoding the rest of the input are reported by the returned reader.
+func decodeInput(r io.Reader, path string) (io.Reader, error) {
+	for {
+		switch filepath.Ext(path) {
+		case suffixBase64:
+			r = &decodeReader{r: base64.NewDecoder(base64.StdEncoding, r), encoding: "base64"}
+		case suffixGzip:
+			zr, err := gzip.NewReader(r)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode gzip input: %w", err)
+			}
+
+			r = &decodeReader{r: zr, encoding: "gzip"}
+		default:
+			return r, nil
+		}
+
+		path = strings.TrimSuffix(path, filepath.Ext(path))
+	}
+}
+
+// trimInputSuffixes returns the path without its encoding suffixes.
+func trimInputSuffixes(path string) string {
+	for {
+		switch ext := filepath.Ext(path); ext {
+		case suffixBase64, suffixGzip:
+			path = strings.TrimSuffix(path, ext)
+		default:
+			return path
+		}
+	}
+}
+
+// decodeReader wraps the errors of a decoding reader to say which decoding
+// failed.
+type decodeReader struct {
+	r        io.Reader
+	encoding string
+}
+
+func (d *decodeReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		// Keep the error of the innermost decoding that failed.
+		var derr *decodeError
+		if !errors.As(err, &derr) {
+			err = &decodeError{encoding: d.encoding, err: err}
+		}
+	}
+
+	return n, err
+}
+
+// decodeError is an error decoding the input.
+type decodeError struct {
+	encoding string
+	err      error
+}
+
+func (e *decodeError) Error() string {
+	return fmt.Sprintf("failed to decode %s input: %v", e.encoding, e.err)
+}
+
+func (e *decodeError) Unwrap() error {
+	return e.err
+}
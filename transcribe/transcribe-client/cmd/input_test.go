@@ -0,0 +1,100 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// gzipped returns the gzip compressed data.
+func gzipped(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestDecodeInput(t *testing.T) {
+	t.Parallel()
+
+	audio := []byte("RIFF some audio samples")
+	b64 := func(data []byte) []byte { return []byte(base64.StdEncoding.EncodeToString(data)) }
+
+	testList := []struct {
+		path string
+		data []byte
+	}{
+		{"clip.wav", audio},
+		{"clip.wav.gz", gzipped(t, audio)},
+		{"clip.wav.b64", b64(audio)},
+		{"clip.wav.gz.b64", b64(gzipped(t, audio))},
+	}
+
+	for _, test := range testList {
+		r, err := decodeInput(bytes.NewReader(test.data), test.path)
+		if err != nil {
+			t.Fatalf("%s: %v", test.path, err)
+		}
+
+		actual, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("%s: %v", test.path, err)
+		}
+
+		if !bytes.Equal(actual, audio) {
+			t.Errorf("%s - expected: %q, actual: %q", test.path, audio, actual)
+		}
+	}
+}
+
+func TestDecodeInputErrors(t *testing.T) {
+	t.Parallel()
+
+	if _, err := decodeInput(strings.NewReader("not gzip"), "clip.wav.gz"); err == nil {
+		t.Error("expected an error for an invalid gzip header")
+	}
+
+	// The data is cut in the middle of the compressed stream.
+	data := gzipped(t, bytes.Repeat([]byte("audio"), 1000))
+	truncated := base64.StdEncoding.EncodeToString(data[:len(data)/2]) + "!!!!"
+
+	r, err := decodeInput(strings.NewReader(truncated), "clip.wav.gz.b64")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = io.ReadAll(r)
+
+	var derr *decodeError
+	if !errors.As(err, &derr) || derr.encoding != "base64" {
+		t.Errorf("expected a base64 decode error, got: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/cobaltspeech/log"
+)
+
+// stopReader is an io.Reader that returns io.EOF once stop is closed, so
+// that the audio stream ends cleanly and the server sends its final results.
+type stopReader struct {
+	r    io.Reader
+	stop <-chan struct{}
+}
+
+func (sr *stopReader) Read(p []byte) (int, error) {
+	select {
+	case <-sr.stop:
+		return 0, io.EOF
+	default:
+		return sr.r.Read(p)
+	}
+}
+
+// notifyInterrupts returns a context and a channel derived from the first
+// two interrupt signals (Ctrl-C). The first one closes the returned channel,
+// which should end the audio so that the final results are still received
+// and written, and the second one cancels the context to quit right away.
+// The returned function stops listening for the signals.
+func notifyInterrupts(ctx context.Context, logger log.Logger) (context.Context, <-chan struct{}, func()) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+
+	ctx, stopAudio, cancel := watchInterrupts(ctx, sigCh, logger)
+
+	return ctx, stopAudio, func() {
+		signal.Stop(sigCh)
+		cancel()
+	}
+}
+
+// watchInterrupts implements notifyInterrupts for the signals received on
+// sigCh.
+func watchInterrupts(ctx context.Context, sigCh <-chan os.Signal,
+	logger log.Logger) (context.Context, <-chan struct{}, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	stopAudio := make(chan struct{})
+
+	go func() {
+		select {
+		case <-sigCh:
+			logger.Info("msg", "interrupted, waiting for the final results (interrupt again to quit)")
+			close(stopAudio)
+		case <-ctx.Done():
+			return
+		}
+
+		select {
+		case <-sigCh:
+			logger.Info("msg", "interrupted again, quitting")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, stopAudio, cancel
+}
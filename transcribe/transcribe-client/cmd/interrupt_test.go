@@ -0,0 +1,141 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cobaltspeech/examples-go/transcribe/transcribe-client/internal/client"
+	transcribepb "github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5"
+	"github.com/cobaltspeech/log"
+)
+
+func TestStopReader(t *testing.T) {
+	t.Parallel()
+
+	stop := make(chan struct{})
+	r := &stopReader{r: strings.NewReader("abcdef"), stop: stop}
+
+	b := make([]byte, 3)
+	if n, err := r.Read(b); err != nil || string(b[:n]) != "abc" {
+		t.Fatalf("unexpected read before stopping: %q, %v", b[:n], err)
+	}
+
+	close(stop)
+
+	if n, err := r.Read(b); err != io.EOF || n != 0 {
+		t.Errorf("expected EOF after stopping, got: %d, %v", n, err)
+	}
+}
+
+func TestWatchInterrupts(t *testing.T) {
+	t.Parallel()
+
+	sigCh := make(chan os.Signal, 1)
+
+	ctx, stopAudio, cancel := watchInterrupts(context.Background(), sigCh, log.NewDiscardLogger())
+	defer cancel()
+
+	// The first interrupt stops the audio without cancelling the context.
+	sigCh <- os.Interrupt
+
+	select {
+	case <-stopAudio:
+	case <-time.After(time.Second):
+		t.Fatal("audio was not stopped after the first interrupt")
+	}
+
+	if ctx.Err() != nil {
+		t.Error("context was cancelled after the first interrupt")
+	}
+
+	// The second one cancels the context.
+	sigCh <- os.Interrupt
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context was not cancelled after the second interrupt")
+	}
+}
+
+// blockingRecognizer is a transcriber that sends one result, then blocks
+// until the context is cancelled.
+type blockingRecognizer struct {
+	sent chan struct{}
+}
+
+func (r *blockingRecognizer) StreamingRecognize(ctx context.Context, _ *transcribepb.RecognitionConfig,
+	_ io.Reader, handler client.RecognitionResponseHandler) error {
+	handler(newTestResult("Before the interrupt.", 0, 1000))
+	close(r.sent)
+
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+func (r *blockingRecognizer) ListModels(context.Context) ([]*transcribepb.Model, error) {
+	return nil, nil
+}
+
+func TestTranscribeCancelledOutput(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	audioPath := filepath.Join(dir, "audio.raw")
+	outPath := filepath.Join(dir, "out.json")
+
+	if err := os.WriteFile(audioPath, make([]byte, 100), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &blockingRecognizer{sent: make(chan struct{})}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Cancel the context like a second Ctrl-C, after a result was written.
+	go func() {
+		<-r.sent
+		cancel()
+	}()
+
+	err := transcribe(ctx, log.NewDiscardLogger(), r, &transcribepb.RecognitionConfig{ModelId: "1"},
+		audioPath, outPath, outputOptions{}, 0, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var results []json.RawMessage
+	if err := json.Unmarshal(data, &results); err != nil {
+		t.Fatalf("output is not valid JSON after cancellation: %v\n%s", err, data)
+	}
+
+	if len(results) != 1 {
+		t.Errorf("expected 1 result in the output, got %d", len(results))
+	}
+}
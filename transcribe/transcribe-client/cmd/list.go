@@ -28,10 +28,11 @@ var listModelsCmd = &cobra.Command{
 	Short: "List models available in Transcribe server.",
 	Long:  "List out the information about the models Transcribe server can access.",
 	Run: func(cmd *cobra.Command, args []string) {
-		var opts []client.Option
+		opts, err := connectionOptions()
+		if err != nil {
+			cmd.PrintErrf("error: %v\n", err)
 
-		if isInsecure {
-			opts = append(opts, client.WithInsecure())
+			return
 		}
 
 		c, err := client.NewClient(serverAddress, opts...)
@@ -0,0 +1,107 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+	"time"
+
+	transcribepb "github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5"
+)
+
+// defaultBytesPerSample is the sample size assumed when converting a duration
+// into a number of audio bytes, if the audio is not raw or its bit depth is
+// not set.
+const defaultBytesPerSample = 2
+
+// maxDurationReader reads from an underlying reader until the given duration
+// of audio has been read, then reports io.EOF.
+type maxDurationReader struct {
+	r         io.Reader
+	remaining int64
+	truncated bool
+}
+
+// newMaxDurationReader returns a reader that stops after duration worth of
+// audio with the given sample rate, number of channels and sample size in
+// bytes. The limit is computed from the raw sample size, so for encoded or
+// compressed audio it is only approximate.
+func newMaxDurationReader(r io.Reader, sampleRate, channels, bytesPerSample uint32,
+	duration time.Duration) *maxDurationReader {
+	if channels == 0 {
+		channels = 1
+	}
+
+	if bytesPerSample == 0 {
+		bytesPerSample = defaultBytesPerSample
+	}
+
+	bytesPerSecond := float64(sampleRate) * float64(channels) * float64(bytesPerSample)
+
+	return &maxDurationReader{
+		r:         r,
+		remaining: int64(duration.Seconds() * bytesPerSecond),
+	}
+}
+
+func (m *maxDurationReader) Read(p []byte) (int, error) {
+	if m.remaining <= 0 {
+		// Check whether there was more audio that is now being dropped.
+		if !m.truncated {
+			var b [1]byte
+			if n, _ := io.ReadFull(m.r, b[:]); n > 0 {
+				m.truncated = true
+			}
+		}
+
+		return 0, io.EOF
+	}
+
+	if int64(len(p)) > m.remaining {
+		p = p[:m.remaining]
+	}
+
+	n, err := m.r.Read(p)
+	m.remaining -= int64(n)
+
+	return n, err
+}
+
+// Truncated reports whether the underlying reader had more audio than the
+// configured maximum duration.
+func (m *maxDurationReader) Truncated() bool {
+	return m.truncated
+}
+
+// audioChannels returns the number of channels in the recognition config's
+// raw audio format, or 1 if the audio is not raw.
+func audioChannels(cfg *transcribepb.RecognitionConfig) uint32 {
+	if ch := cfg.GetAudioFormatRaw().GetChannels(); ch > 0 {
+		return ch
+	}
+
+	return 1
+}
+
+// audioBytesPerSample returns the sample size in bytes of the recognition
+// config's raw audio format, rounding up bit depths that are not a multiple of
+// 8, or defaultBytesPerSample if the audio is not raw or has no bit depth.
+func audioBytesPerSample(cfg *transcribepb.RecognitionConfig) uint32 {
+	if bits := cfg.GetAudioFormatRaw().GetBitDepth(); bits > 0 {
+		return (bits + 7) / 8 //nolint:gomnd // bits per byte
+	}
+
+	return defaultBytesPerSample
+}
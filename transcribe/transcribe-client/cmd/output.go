@@ -0,0 +1,262 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cobaltspeech/examples-go/pkg/confidence"
+	"github.com/cobaltspeech/log"
+
+	transcribepb "github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5"
+)
+
+// Supported output formats.
+const (
+	formatAuto = ""     // json when writing to a file, text otherwise (or with --words)
+	formatText = "text" // formatted hypothesis, one line per result
+	formatJSON = "json" // list of recognize responses
+	formatSRT  = "srt"  // SubRip subtitles
+	formatVTT  = "vtt"  // WebVTT subtitles
+)
+
+func checkOutputFormat(format string) error {
+	switch format {
+	case formatAuto, formatText, formatJSON, formatSRT, formatVTT:
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %q, must be one of text, json, srt or vtt", format)
+	}
+}
+
+// resolveOutputFormat returns the output format to use for the options when
+// writing to a file or to STDOUT.
+func resolveOutputFormat(opts outputOptions, toFile bool) string {
+	switch {
+	case opts.format != formatAuto:
+		return opts.format
+	case toFile && !opts.words:
+		return formatJSON
+	default:
+		return formatText
+	}
+}
+
+// errNoTiming is returned by the subtitle encoders for results without
+// timing information, which can't be placed.
+var errNoTiming = errors.New("result has no timing information")
+
+// respEncoder writes recognize responses in one of the output formats.
+type respEncoder interface {
+	// begin writes anything that comes before the first response.
+	begin() error
+
+	// encode writes a final response.
+	encode(resp *transcribepb.StreamingRecognizeResponse) error
+
+	// end writes anything that comes after the last response.
+	end() error
+}
+
+// newRespEncoder returns the encoder for the given format, writing to w.
+func newRespEncoder(l log.Logger, format string, w io.Writer, opts outputOptions) (respEncoder, error) {
+	if format == formatJSON {
+		return &jsonEncoder{w: w}, nil
+	}
+
+	tw := newTextWriter(w, opts)
+
+	switch format {
+	case formatSRT:
+		return &subtitleEncoder{text: tw, timeSep: ",", numbered: true}, nil
+	case formatVTT:
+		return &subtitleEncoder{text: tw, timeSep: ".", header: "WEBVTT"}, nil
+	default:
+		// The encoder is created right before streaming starts, which is
+		// the clock base "now".
+		base, err := parseClockBase(opts.clockBase, time.Now())
+		if err != nil {
+			return nil, err
+		}
+
+		return &textEncoder{logger: l, text: tw, confidence: opts.confidence, words: opts.words, clockBase: base}, nil
+	}
+}
+
+// textEncoder writes the formatted hypothesis of each result on a line, or
+// each of its words on a line with their start and end times in seconds,
+// separated by tabs, or each word on a line with its absolute times if there
+// is a clock base.
+type textEncoder struct {
+	logger     log.Logger
+	text       *textWriter
+	confidence string
+	words      bool
+	clockBase  time.Time
+}
+
+func (e *textEncoder) begin() error {
+	return nil
+}
+
+func (e *textEncoder) encode(resp *transcribepb.StreamingRecognizeResponse) error {
+	alt := resp.Result.Alternatives[0]
+
+	if !e.clockBase.IsZero() {
+		for _, line := range formatWordTimes(alt, e.clockBase, e.confidence) {
+			if err := e.text.writeLine(line); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if e.words {
+		return e.encodeWords(alt)
+	}
+
+	line := alt.TranscriptFormatted
+
+	if c := confidence.Format(alt.Confidence, e.confidence); c != "" {
+		line += " " + c
+	}
+
+	return e.text.writeLine(line)
+}
+
+func (e *textEncoder) encodeWords(alt *transcribepb.RecognitionAlternative) error {
+	words := alt.GetWordDetails().GetFormatted()
+	if len(words) == 0 {
+		e.logger.Debug("msg", "no word timing in result, writing the whole transcript", "transcript", alt.TranscriptFormatted)
+
+		return e.text.writeLine(alt.TranscriptFormatted + "\t\t")
+	}
+
+	for _, w := range words {
+		line := w.Word + "\t" + formatSeconds(w.StartTimeMs) + "\t" + formatSeconds(w.StartTimeMs+w.DurationMs)
+		if err := e.text.writeLine(line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// formatSeconds formats a time offset in milliseconds as seconds, e.g. "1.250".
+func formatSeconds(ms uint64) string {
+	return fmt.Sprintf("%d.%03d", ms/1000, ms%1000) //nolint:gomnd // time units
+}
+
+func (e *textEncoder) end() error {
+	return nil
+}
+
+// jsonEncoder writes the responses as an indented JSON list.
+type jsonEncoder struct {
+	w io.Writer
+}
+
+const jsonIndent = "  "
+
+func (e *jsonEncoder) begin() error {
+	_, err := io.WriteString(e.w, "[\n")
+
+	return err
+}
+
+func (e *jsonEncoder) encode(resp *transcribepb.StreamingRecognizeResponse) error {
+	enc := json.NewEncoder(e.w)
+	enc.SetIndent(jsonIndent, jsonIndent)
+
+	if _, err := io.WriteString(e.w, jsonIndent); err != nil {
+		return err
+	}
+
+	return enc.Encode(resp)
+}
+
+func (e *jsonEncoder) end() error {
+	_, err := io.WriteString(e.w, "]\n")
+
+	return err
+}
+
+// subtitleEncoder writes each result as a subtitle cue, in the SRT or
+// WebVTT format.
+type subtitleEncoder struct {
+	text     *textWriter
+	header   string // written before the cues, if set
+	timeSep  string // separator between the seconds and milliseconds
+	numbered bool   // whether cues are numbered
+	cues     int    // number of cues written
+}
+
+func (e *subtitleEncoder) begin() error {
+	if e.header == "" {
+		return nil
+	}
+
+	if err := e.text.writeLine(e.header); err != nil {
+		return err
+	}
+
+	return e.text.writeLine("")
+}
+
+func (e *subtitleEncoder) encode(resp *transcribepb.StreamingRecognizeResponse) error {
+	alt := resp.Result.Alternatives[0]
+	if alt.DurationMs == 0 {
+		return errNoTiming
+	}
+
+	e.cues++
+
+	var lines []string
+	if e.numbered {
+		lines = append(lines, fmt.Sprint(e.cues))
+	}
+
+	lines = append(lines,
+		e.formatTime(alt.StartTimeMs)+" --> "+e.formatTime(alt.StartTimeMs+alt.DurationMs),
+		alt.TranscriptFormatted,
+		"",
+	)
+
+	for _, line := range lines {
+		if err := e.text.writeLine(line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *subtitleEncoder) end() error {
+	return nil
+}
+
+// formatTime formats a time offset in milliseconds as a subtitle timestamp,
+// e.g. "01:02:03,456" for SRT or "01:02:03.456" for WebVTT.
+func (e *subtitleEncoder) formatTime(ms uint64) string {
+	d := time.Duration(ms) * time.Millisecond
+
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d",
+		int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60, e.timeSep, ms%1000) //nolint:gomnd // time units
+}
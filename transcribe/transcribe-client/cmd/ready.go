@@ -0,0 +1,68 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cobaltspeech/examples-go/transcribe/transcribe-client/internal/client"
+
+	"github.com/spf13/cobra"
+)
+
+const defaultReadyTimeout = 5 * time.Second
+
+func buildReadyCmd() *cobra.Command {
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "ready",
+		Short: "Check that Transcribe server is reachable and has models loaded.",
+		Long: "Check that Transcribe server responds to requests and has at least one model loaded. " +
+			"The command exits with a non-zero status if the server is not ready, which makes it " +
+			"suitable as a readiness probe or as a precondition in a pipeline.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := connectionOptions()
+			if err != nil {
+				return err
+			}
+
+			c, err := client.NewClient(serverAddress, opts...)
+			if err != nil {
+				return fmt.Errorf("failed to create a client: %w", err)
+			}
+
+			defer c.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			if err := c.Ready(ctx); err != nil {
+				return err
+			}
+
+			fmt.Println("ok")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", defaultReadyTimeout, "Maximum time to wait for the server to respond.")
+
+	return cmd
+}
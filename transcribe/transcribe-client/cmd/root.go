@@ -15,9 +15,13 @@
 package cmd
 
 import (
+	"crypto/tls"
 	"fmt"
 	"os"
 
+	"github.com/cobaltspeech/examples-go/pkg/envconfig"
+	"github.com/cobaltspeech/examples-go/transcribe/transcribe-client/internal/client"
+
 	"github.com/spf13/cobra"
 )
 
@@ -25,6 +29,16 @@ import (
 var (
 	serverAddress string // address is the GRPC address of Transcribe server.
 	isInsecure    bool   // isInsecure is a flag specify insecure connection to the server.
+
+	tlsMinVersion   string   // tlsMinVersion is the minimum TLS version, "1.2" or "1.3".
+	tlsCipherSuites []string // tlsCipherSuites are the names of the allowed TLS 1.2 cipher suites.
+	tlsCACert       string   // tlsCACert is the path to the CA certificates to verify the server with.
+	tlsServerName   string   // tlsServerName overrides the server name to verify.
+	tlsClientCert   string   // tlsClientCert is the path to the client certificate for mutual TLS.
+	tlsClientKey    string   // tlsClientKey is the path to the key of the client certificate.
+
+	authToken    string            // authToken is sent as a bearer token with each request.
+	grpcMetadata map[string]string // grpcMetadata is sent with each request.
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -32,6 +46,14 @@ var rootCmd = &cobra.Command{
 	Use:   "transcribe-client",
 	Short: "transcribe-client is a command line interface for interacting with a running instance of transcribe-server.",
 	Long:  `transcribe-client is a command line interface for interacting with a running instance of transcribe-server.`,
+
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := applyEnvOverrides(cmd); err != nil {
+			return err
+		}
+
+		return applyAutoSecurity(cmd, args)
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -47,9 +69,143 @@ func init() {
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(buildTransribeCmd())
 	rootCmd.AddCommand(listModelsCmd)
+	rootCmd.AddCommand(buildWarmupCmd())
+	rootCmd.AddCommand(buildReadyCmd())
+	rootCmd.AddCommand(buildConfigCmd())
+	rootCmd.AddCommand(buildCompareModelsCmd())
+	rootCmd.AddCommand(buildDictateCmd())
 
 	// Add the global flags.
-	rootCmd.PersistentFlags().StringVarP(&serverAddress, "server", "s", "127.0.0.1:2727", "Transcribe-server GRPC address.")
+	rootCmd.PersistentFlags().StringVarP(&serverAddress, "server", "s", "127.0.0.1:2727",
+		"Transcribe-server GRPC address. Defaults to $COBALT_SERVER_ADDRESS, if set.")
 	rootCmd.PersistentFlags().BoolVar(&isInsecure, "insecure", false,
-		"If flag provided, TLS will not be used when establishing a connection to the server")
+		"If flag provided, TLS will not be used when establishing a connection to the server. "+
+			"Defaults to $COBALT_INSECURE, if set.")
+	rootCmd.PersistentFlags().BoolVar(&autoSecurity, "auto-security", false,
+		"If flag provided and the connection fails because of a TLS mismatch, retry once with the opposite "+
+			"of --insecure and print a warning. Only allowed in interactive sessions; use it to fix your configuration.")
+	rootCmd.PersistentFlags().StringVar(&tlsMinVersion, "tls-min-version", "1.2", "Minimum TLS version to use, 1.2 or 1.3.")
+	rootCmd.PersistentFlags().StringSliceVar(&tlsCipherSuites, "tls-cipher-suites", nil,
+		"Comma separated list of TLS 1.2 cipher suites to allow, e.g. TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384. "+
+			"If not provided, Go's default cipher suites are used.")
+	rootCmd.PersistentFlags().StringVar(&tlsCACert, "tls-ca-cert", "",
+		"Path to a PEM file with the CA certificates to verify the server with, instead of the system's CA certificates.")
+	rootCmd.PersistentFlags().StringVar(&tlsServerName, "tls-server-name", "",
+		"Server name to verify the server's certificate against, instead of the host of the server address.")
+	rootCmd.PersistentFlags().StringVar(&tlsClientCert, "tls-client-cert", "",
+		"Path to a PEM client certificate, for servers that require mutual TLS. Requires --tls-client-key.")
+	rootCmd.PersistentFlags().StringVar(&tlsClientKey, "tls-client-key", "",
+		"Path to the PEM key of the --tls-client-cert.")
+	rootCmd.PersistentFlags().StringVar(&authToken, "auth-token", "",
+		"Token sent as a bearer token in the authorization metadata of each request.")
+	rootCmd.PersistentFlags().StringToStringVar(&grpcMetadata, "metadata", nil,
+		"Comma separated list of key=value gRPC metadata sent with each request.")
+}
+
+// applyEnvOverrides sets the server address and security mode from the
+// COBALT_SERVER_ADDRESS and COBALT_INSECURE environment variables, unless the
+// --server or --insecure flags are given. The precedence is flag > environment
+// > default.
+func applyEnvOverrides(cmd *cobra.Command) error {
+	var env envconfig.Server
+
+	if !cmd.Flags().Changed("server") {
+		env.Address = &serverAddress
+	}
+
+	if !cmd.Flags().Changed("insecure") {
+		env.Insecure = &isInsecure
+	}
+
+	return env.Apply()
+}
+
+// connectionOptions returns the client options for connecting to the server,
+// as configured by the global flags.
+func connectionOptions() ([]client.Option, error) {
+	opts, err := securityOptions(isInsecure)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(opts, metadataOptions()...), nil
+}
+
+// metadataOptions returns the client options for the metadata sent with each
+// request, as configured by the global flags.
+func metadataOptions() []client.Option {
+	var opts []client.Option
+
+	if len(grpcMetadata) > 0 {
+		opts = append(opts, client.WithMetadata(grpcMetadata))
+	}
+
+	if authToken != "" {
+		opts = append(opts, client.WithAuthToken(authToken))
+	}
+
+	return opts
+}
+
+// securityOptions returns the client options for connecting to the server
+// with or without TLS, using the configured TLS settings.
+func securityOptions(insecure bool) ([]client.Option, error) {
+	if insecure {
+		return []client.Option{client.WithInsecure()}, nil
+	}
+
+	var opts []client.Option
+
+	switch tlsMinVersion {
+	case "1.2":
+		opts = append(opts, client.WithTLSMinVersion(tls.VersionTLS12))
+	case "1.3":
+		opts = append(opts, client.WithTLSMinVersion(tls.VersionTLS13))
+	default:
+		return nil, fmt.Errorf("invalid --tls-min-version %q, must be 1.2 or 1.3", tlsMinVersion)
+	}
+
+	if len(tlsCipherSuites) > 0 {
+		ids, err := cipherSuiteIDs(tlsCipherSuites)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, client.WithTLSCipherSuites(ids...))
+	}
+
+	if tlsCACert != "" {
+		opts = append(opts, client.WithCACert(tlsCACert))
+	}
+
+	if tlsServerName != "" {
+		opts = append(opts, client.WithServerNameOverride(tlsServerName))
+	}
+
+	if tlsClientCert != "" || tlsClientKey != "" {
+		opts = append(opts, client.WithClientCert(tlsClientCert, tlsClientKey))
+	}
+
+	return opts, nil
+}
+
+// cipherSuiteIDs converts cipher suite names into their IDs.
+func cipherSuiteIDs(names []string) ([]uint16, error) {
+	known := make(map[string]uint16)
+	for _, s := range tls.CipherSuites() {
+		known[s.Name] = s.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+
+	for _, name := range names {
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown TLS cipher suite %q", name)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
 }
@@ -17,10 +17,15 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/cobaltspeech/examples-go/pkg/confidence"
+	"github.com/cobaltspeech/examples-go/pkg/textenc"
 	"github.com/cobaltspeech/examples-go/transcribe/transcribe-client/internal/client"
 	transcribepb "github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5"
 	"github.com/cobaltspeech/log"
@@ -33,11 +38,21 @@ func buildTransribeCmd() *cobra.Command {
 		recCfgStr string
 		outPath   string
 		verbose   int
+		outOpts   outputOptions
+		maxDur    time.Duration
+		reqModel  bool
+		workers   int
+		ctxValues []string
+		ctxToken  string
+		clockBase string
 	)
 
 	cmd := &cobra.Command{
-		Use:   "recognize <AUDIO_FILE>",
-		Short: "Transcribe an audio file.",
+		Use:   "recognize <AUDIO_FILE|DIR|GLOB>...",
+		Short: "Transcribe audio files.",
+		Long: "Transcribe an audio file, or multiple files given as several arguments, directories or glob " +
+			"patterns. When transcribing multiple files, the output of each one is written next to it, " +
+			"with the extension of the output format.",
 		Run: func(cmd *cobra.Command, args []string) {
 			if len(args) < 1 {
 				cmd.PrintErr(cmd.UsageString())
@@ -45,13 +60,74 @@ func buildTransribeCmd() *cobra.Command {
 				return
 			}
 
+			if err := confidence.CheckFormat(outOpts.confidence); err != nil {
+				cmd.PrintErrf("error: %v\n", err)
+
+				return
+			}
+
+			if err := checkOutputFormat(outOpts.format); err != nil {
+				cmd.PrintErrf("error: %v\n", err)
+
+				return
+			}
+
+			if _, err := parseClockBase(clockBase, time.Now()); err != nil {
+				cmd.PrintErrf("error: %v\n", err)
+
+				return
+			}
+
+			outOpts.clockBase = clockBase
+
+			if outOpts.minConfidence < 0 || outOpts.minConfidence > 1 {
+				cmd.PrintErrf("error: invalid --min-confidence %v, must be between 0 and 1\n", outOpts.minConfidence)
+
+				return
+			}
+
+			if outOpts.words && resolveOutputFormat(outOpts, outPath != "") != formatText {
+				cmd.PrintErrf("error: --words requires the text output format\n")
+
+				return
+			}
+
+			if workers < 1 {
+				cmd.PrintErrf("error: invalid number of workers %d, must be at least 1\n", workers)
+
+				return
+			}
+
+			phrases, err := parseContextPhrases(ctxValues)
+			if err != nil {
+				cmd.PrintErrf("error: %v\n", err)
+
+				return
+			}
+
+			files, batch, err := expandInputs(args)
+			if err != nil {
+				cmd.PrintErrf("error: %v\n", err)
+
+				return
+			}
+
+			if batch && outPath != "" {
+				cmd.PrintErrf("error: --output-json can't be used with multiple input files\n")
+
+				return
+			}
+
 			logger := log.NewLeveledLogger(log.WithFilterLevel(getLogLevel(verbose)))
-			opts := []client.Option{client.WithLogger(logger)}
+			opts, err := connectionOptions()
+			if err != nil {
+				cmd.PrintErrf("error: %v\n", err)
 
-			if isInsecure {
-				opts = append(opts, client.WithInsecure())
+				return
 			}
 
+			opts = append(opts, client.WithLogger(logger))
+
 			c, err := client.NewClient(serverAddress, opts...)
 			if err != nil {
 				cmd.PrintErrf("error: failed to create a client: %v\n", err)
@@ -61,8 +137,34 @@ func buildTransribeCmd() *cobra.Command {
 
 			defer c.Close()
 
-			// args[0] is the audio file
-			if err := transcribe(context.Background(), logger, c, recCfgStr, args[0], outPath); err != nil {
+			// Ctrl-C ends the audio early, and the results received for it
+			// are still written.
+			ctx, stopAudio, stopInterrupts := notifyInterrupts(context.Background(), logger)
+			defer stopInterrupts()
+
+			cfg, err := buildRecognitionConfig(ctx, logger, c, recCfgStr, reqModel, phrases, ctxToken)
+			if err != nil {
+				cmd.PrintErrf("error: %v\n", err)
+
+				return
+			}
+
+			// The word times are computed from the word details.
+			if outOpts.words || clockBase != "" {
+				cfg.EnableWordDetails = true
+			}
+
+			transcribeFile := func(ctx context.Context, audioPath, outPath string) error {
+				return transcribe(ctx, logger, c, cfg, audioPath, outPath, outOpts, maxDur, stopAudio)
+			}
+
+			if batch {
+				err = transcribeBatch(ctx, logger, files, resolveOutputFormat(outOpts, true), workers, stopAudio, transcribeFile)
+			} else {
+				err = transcribeFile(ctx, files[0], outPath)
+			}
+
+			if err != nil {
 				cmd.PrintErrf("error: %v\n", err)
 
 				return
@@ -71,31 +173,83 @@ func buildTransribeCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&outPath, "output-json", "o", "",
-		"Path to output json file. If not specified, writes formatted hypothesis to STDOUT.")
+		"Path to output file. If not specified, writes to STDOUT. Unless --output-format is set, "+
+			"the file contains JSON and STDOUT gets the formatted hypothesis.")
+	cmd.Flags().StringVar(&outOpts.format, "output-format", formatAuto,
+		"Output format: text (formatted hypothesis), json, srt or vtt (subtitles using the result timestamps).")
 	cmd.Flags().StringVarP(&recCfgStr, "recognition-config", "r", "{}", "Json string to configure recognition. "+
 		"See https://pkg.go.dev/github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5#RecognitionConfig for more details.")
 	cmd.Flags().IntVarP(&verbose, "verbose", "v", 0, "Logger verbose modes. 0=Info, 1=Debug, 2=Trace")
+	cmd.Flags().BoolVar(&outOpts.bom, "bom", false, "If flag provided, the formatted hypothesis output starts with a UTF-8 byte order mark.")
+	cmd.Flags().BoolVar(&reqModel, "require-model", false,
+		"If flag provided, the model must be set in the recognition config instead of defaulting to the first available model.")
+	cmd.Flags().DurationVar(&maxDur, "max-audio-duration", 0,
+		"Maximum duration of audio to send to the server (e.g. 10m). Audio past this point is dropped. 0 means no limit. "+
+			"The limit is computed from the raw audio format, so it is only approximate for WAV, FLAC and other encoded input.")
+	cmd.Flags().StringVar(&outOpts.confidence, "confidence-format", confidence.None,
+		"How the formatted hypothesis output shows the confidence of each result: none, raw (e.g. (0.92)) or percent (e.g. (92%)).")
+	cmd.Flags().StringArrayVar(&ctxValues, "context-phrases", nil,
+		"Phrase to bias recognition towards, with an optional boost (e.g. \"Cobalt Speech|2.5\"), or the path to a "+
+			"file with one such phrase per line. Can be repeated. The phrases are compiled into a recognition context "+
+			"for the model, which must support it.")
+	cmd.Flags().StringVar(&ctxToken, "context-token", "",
+		"Context token to compile the --context-phrases for. Defaults to the first token allowed by the model.")
+	cmd.Flags().IntVar(&workers, "workers", 1,
+		"Number of files transcribed concurrently when transcribing multiple files.")
+	cmd.Flags().Float64Var(&outOpts.minConfidence, "min-confidence", 0,
+		"Minimum confidence, between 0 and 1, of the alternatives written to the output. "+
+			"Results without any alternative at or above it are skipped.")
+	cmd.Flags().BoolVar(&outOpts.words, "words", false,
+		"If flag provided, the text output has a line per word with its start and end times in seconds, "+
+			"separated by tabs (word\\tstart\\tend). Word details are enabled in the recognition config.")
+	cmd.Flags().BoolVar(&outOpts.crlf, "crlf", false, "If flag provided, the formatted hypothesis output uses CRLF line endings instead of LF.")
+	cmd.Flags().StringVar(&clockBase, "clock-base", "",
+		"If provided, the formatted hypothesis output lists each word with its absolute start and end times, "+
+			"offset from this base: \"now\" (when streaming starts, e.g. for live audio from /dev/stdin) "+
+			"or an RFC3339 time (e.g. 2026-10-16T09:30:00Z). Enables word details in the recognition config.")
 
 	return cmd
 }
 
-func transcribe(ctx context.Context, logger log.Logger, c *client.Client,
-	recCfgStr, audioPath, outPath string) error {
-	// read the recognition config from the config string
+// buildRecognitionConfig parses the recognition config string, resolves its
+// model ID and adds the recognition context compiled from the phrases, if any.
+func buildRecognitionConfig(ctx context.Context, logger log.Logger, c *client.Client,
+	recCfgStr string, requireModel bool, phrases []*transcribepb.ContextPhrase,
+	contextToken string) (*transcribepb.RecognitionConfig, error) {
 	cfg, err := parseRecognitionConfig(recCfgStr)
 	if err != nil {
-		return fmt.Errorf("failed to parse recognition config: %w", err)
+		return nil, fmt.Errorf("failed to parse recognition config: %w", err)
 	}
 
-	// Check model ID. Use default model if not specify .
-	if cfg.ModelId == "" {
-		logger.Debug("msg", "model is not specified, use the default (first available) model")
+	if err := resolveModelID(ctx, logger, c, cfg, requireModel); err != nil {
+		return nil, err
+	}
 
-		if cfg.ModelId, err = getDefaultModelID(ctx, c); err != nil {
-			return fmt.Errorf("failed to get default model ID: %w", err)
+	if len(phrases) > 0 {
+		if err := addRecognitionContext(ctx, c, cfg, phrases, contextToken); err != nil {
+			return nil, err
 		}
+
+		logger.Debug("msg", "compiled recognition context", "phrases", len(phrases))
 	}
 
+	return cfg, nil
+}
+
+// transcriber is the part of the client used to transcribe files.
+type transcriber interface {
+	recognizer
+	ListModels(ctx context.Context) ([]*transcribepb.Model, error)
+}
+
+// transcribe streams the audio file to the server and writes the results.
+// Once stopAudio is closed, no more audio is sent, but the results for the
+// audio already sent are still written before the output is closed. The
+// output is also closed properly when ctx is cancelled, e.g. by a second
+// Ctrl-C, so that a JSON output is still valid.
+func transcribe(ctx context.Context, logger log.Logger, c transcriber,
+	cfg *transcribepb.RecognitionConfig, audioPath, outPath string, outOpts outputOptions, maxDur time.Duration,
+	stopAudio <-chan struct{}) error {
 	// open audio file
 	audio, err := os.Open(audioPath)
 	if err != nil {
@@ -104,8 +258,28 @@ func transcribe(ctx context.Context, logger log.Logger, c *client.Client,
 
 	defer audio.Close()
 
+	decoded, err := decodeInput(audio, audioPath)
+	if err != nil {
+		return err
+	}
+
+	var (
+		audioReader io.Reader = &stopReader{r: decoded, stop: stopAudio}
+		limited     *maxDurationReader
+	)
+
+	if maxDur > 0 {
+		sampleRate, err := getModelSampleRate(ctx, c, cfg.ModelId)
+		if err != nil {
+			return fmt.Errorf("failed to get sample rate for --max-audio-duration: %w", err)
+		}
+
+		limited = newMaxDurationReader(audioReader, sampleRate, audioChannels(cfg), audioBytesPerSample(cfg), maxDur)
+		audioReader = limited
+	}
+
 	// create output writer
-	wr, err := newRespWriter(logger, outPath)
+	wr, err := newRespWriter(logger, outPath, outOpts)
 	if err != nil {
 		return fmt.Errorf("failed to create output writer: %w", err)
 	}
@@ -122,10 +296,21 @@ func transcribe(ctx context.Context, logger log.Logger, c *client.Client,
 			logger.Error("msg", "recognition error", "error", resp.Error)
 		}
 
-		if !resp.Result.IsPartial && len(resp.Result.Alternatives) > 0 {
-			logger.Trace("chan", resp.Result.AudioChannel, "transcript", resp.Result.Alternatives[0].TranscriptFormatted)
-			wr.write(resp)
+		if resp.Result.IsPartial || len(resp.Result.Alternatives) == 0 {
+			return
+		}
+
+		filtered := filterConfidence(resp, outOpts.minConfidence)
+		if filtered == nil {
+			logger.Debug("msg", "skipping result below the minimum confidence",
+				"transcript", resp.Result.Alternatives[0].TranscriptFormatted,
+				"confidence", resp.Result.Alternatives[0].Confidence)
+
+			return
 		}
+
+		logger.Trace("chan", filtered.Result.AudioChannel, "transcript", filtered.Result.Alternatives[0].TranscriptFormatted)
+		wr.write(filtered)
 	}
 
 	// log basic info
@@ -137,10 +322,14 @@ func transcribe(ctx context.Context, logger log.Logger, c *client.Client,
 		"recognition config", cfg,
 	)
 
-	if err = c.StreamingRecognize(ctx, cfg, audio, callBackFunc); err != nil {
+	if err = c.StreamingRecognize(ctx, cfg, audioReader, callBackFunc); err != nil {
 		return fmt.Errorf("failed to transcribe: %w", err)
 	}
 
+	if limited != nil && limited.Truncated() {
+		logger.Info("msg", "warning: input audio was truncated", "max audio duration", maxDur)
+	}
+
 	logger.Info("msg", "streaming recognize done")
 
 	return nil
@@ -159,6 +348,35 @@ func parseRecognitionConfig(s string) (*transcribepb.RecognitionConfig, error) {
 	return &cfg, nil
 }
 
+// errModelRequired is returned when no model is specified and falling back to
+// the default model is disabled.
+var errModelRequired = errors.New("no model ID specified in the recognition config and --require-model is set")
+
+// resolveModelID checks the model ID of the recognition config. If it is not
+// specified, it is set to the default (first available) model, unless
+// requireModel is true, in which case an error is returned.
+func resolveModelID(ctx context.Context, logger log.Logger, c *client.Client,
+	cfg *transcribepb.RecognitionConfig, requireModel bool) error {
+	if cfg.ModelId != "" {
+		return nil
+	}
+
+	if requireModel {
+		return errModelRequired
+	}
+
+	logger.Debug("msg", "model is not specified, use the default (first available) model")
+
+	id, err := getDefaultModelID(ctx, c)
+	if err != nil {
+		return fmt.Errorf("failed to get default model ID: %w", err)
+	}
+
+	cfg.ModelId = id
+
+	return nil
+}
+
 func getDefaultModelID(ctx context.Context, c *client.Client) (string, error) {
 	v, err := c.ListModels(ctx)
 	if err != nil {
@@ -168,19 +386,104 @@ func getDefaultModelID(ctx context.Context, c *client.Client) (string, error) {
 	return v[0].Id, nil
 }
 
-// respWriter encodes and writes list of recognize response JSON to output file, if output
-// file is specify. Otherwise, writes formatted hypothesis to STDOUT.
+// getModelSampleRate returns the sample rate of the model with the given ID.
+func getModelSampleRate(ctx context.Context, c transcriber, modelID string) (uint32, error) {
+	models, err := c.ListModels(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list models: %w", err)
+	}
+
+	for _, m := range models {
+		if m.Id != modelID {
+			continue
+		}
+
+		if sr := m.GetAttributes().GetSampleRate(); sr > 0 {
+			return sr, nil
+		}
+
+		return 0, fmt.Errorf("model %q does not report a sample rate", modelID)
+	}
+
+	return 0, fmt.Errorf("model %q not found", modelID)
+}
+
+// outputOptions configures the encoding of the formatted hypothesis output.
+type outputOptions struct {
+	bom        bool   // bom writes a UTF-8 byte order mark before the first line.
+	crlf       bool   // crlf ends each line with "\r\n" instead of "\n".
+	confidence string // confidence is the format of the confidence shown after each line.
+	format     string // format is one of the supported output formats.
+	words      bool   // words writes each word of the text output on a line, with its times.
+	clockBase  string // clockBase, if set, lists the words with their times offset from it.
+
+	minConfidence float64 // minConfidence is the confidence below which alternatives are not written.
+}
+
+// filterConfidence returns the response with only the alternatives whose
+// confidence is at least minConfidence, or nil if there are none. The
+// response is returned as is if all its alternatives are kept.
+func filterConfidence(resp *transcribepb.StreamingRecognizeResponse,
+	minConfidence float64) *transcribepb.StreamingRecognizeResponse {
+	var kept []*transcribepb.RecognitionAlternative
+
+	for _, alt := range resp.Result.Alternatives {
+		if alt.Confidence >= minConfidence {
+			kept = append(kept, alt)
+		}
+	}
+
+	switch len(kept) {
+	case 0:
+		return nil
+	case len(resp.Result.Alternatives):
+		return resp
+	}
+
+	return &transcribepb.StreamingRecognizeResponse{
+		Result: &transcribepb.RecognitionResult{
+			Alternatives: kept,
+			IsPartial:    resp.Result.IsPartial,
+			Cnet:         resp.Result.Cnet,
+			AudioChannel: resp.Result.AudioChannel,
+		},
+		Error: resp.Error,
+	}
+}
+
+// textWriter writes lines of text, applying the configured byte order mark
+// and line endings.
+type textWriter struct {
+	w io.Writer
+}
+
+func newTextWriter(w io.Writer, opts outputOptions) *textWriter {
+	return &textWriter{w: textenc.NewWriter(w, textenc.Options{BOM: opts.bom, CRLF: opts.crlf})}
+}
+
+func (tw *textWriter) writeLine(s string) error {
+	_, err := io.WriteString(tw.w, s+"\n")
+
+	return err
+}
+
+// respWriter writes the final recognize responses to STDOUT or an output
+// file using the encoder of the selected format.
 type respWriter struct {
 	logger log.Logger
-	outF   *os.File
+	outF   *os.File // output file, nil when writing to STDOUT
+	enc    respEncoder
 }
 
-func newRespWriter(l log.Logger, path string) (*respWriter, error) {
+func newRespWriter(l log.Logger, path string, opts outputOptions) (*respWriter, error) {
 	if l == nil {
 		l = log.NewDiscardLogger()
 	}
 
+	format := resolveOutputFormat(opts, path != "")
+
 	var (
+		out  io.Writer = os.Stdout
 		outF *os.File
 		err  error
 	)
@@ -191,47 +494,48 @@ func newRespWriter(l log.Logger, path string) (*respWriter, error) {
 			return nil, fmt.Errorf("failed to create output file (path=%s): %w", path, err)
 		}
 
-		if _, err := outF.Write([]byte("[\n")); err != nil {
-			return nil, fmt.Errorf("unable to start writing list of recognize response: %w", err)
+		out = outF
+	}
+
+	enc, err := newRespEncoder(l, format, out, opts)
+	if err == nil {
+		err = enc.begin()
+	}
+
+	if err != nil {
+		if outF != nil {
+			outF.Close()
 		}
+
+		return nil, fmt.Errorf("unable to start writing %s output: %w", format, err)
 	}
 
 	return &respWriter{
 		logger: l,
 		outF:   outF,
+		enc:    enc,
 	}, nil
 }
 
 func (w *respWriter) write(resp *transcribepb.StreamingRecognizeResponse) {
-	if w.outF == nil {
-		// no output file specified, print formatted hypothesis to STDOUT
-		fmt.Println(resp.Result.Alternatives[0].TranscriptFormatted)
-
-		return
-	}
-
-	const indent = "  "
-
-	// write JSON encoded response to output file.
-	enc := json.NewEncoder(w.outF)
-	enc.SetIndent(indent, indent)
-
-	if _, err := w.outF.Write([]byte(indent)); err != nil {
-		w.logger.Error("error", "unable to write to output file", "err", err)
-	}
-
-	if err := enc.Encode(resp); err != nil {
-		w.logger.Error("error", "failed to encode response JSON", "response", resp, "err", err)
+	err := w.enc.encode(resp)
+
+	switch {
+	case errors.Is(err, errNoTiming):
+		w.logger.Info("msg", "warning: skipping result without timing information",
+			"transcript", resp.Result.Alternatives[0].TranscriptFormatted)
+	case err != nil:
+		w.logger.Error("error", "unable to write response", "response", resp, "err", err)
 	}
 }
 
 func (w *respWriter) close() {
-	if w.outF == nil {
-		return
+	if err := w.enc.end(); err != nil {
+		w.logger.Error("error", "unable to finish writing output", "err", err)
 	}
 
-	if _, err := w.outF.Write([]byte("]\n")); err != nil {
-		w.logger.Error("error", "unable to close list of recognize response", "err", err)
+	if w.outF == nil {
+		return
 	}
 
 	if err := w.outF.Close(); err != nil {
@@ -0,0 +1,305 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	transcribepb "github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5"
+	"github.com/cobaltspeech/log"
+)
+
+func TestTextWriter(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		name     string
+		opts     outputOptions
+		expected string
+	}{
+		{"default", outputOptions{}, "hello\nworld\n"},
+		{"bom", outputOptions{bom: true}, "\xEF\xBB\xBFhello\nworld\n"},
+		{"crlf", outputOptions{crlf: true}, "hello\r\nworld\r\n"},
+		{"bom+crlf", outputOptions{bom: true, crlf: true}, "\xEF\xBB\xBFhello\r\nworld\r\n"},
+	}
+
+	for i := range testList {
+		test := testList[i]
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			tw := newTextWriter(&buf, test.opts)
+
+			for _, line := range []string{"hello", "world"} {
+				if err := tw.writeLine(line); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if actual := buf.String(); actual != test.expected {
+				t.Errorf("incorrect output - expected: %q, actual: %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMaxDurationReader(t *testing.T) {
+	t.Parallel()
+
+	const sampleRate = 8000
+
+	testList := []struct {
+		name           string
+		inputLen       int
+		channels       uint32
+		bytesPerSample uint32
+		duration       time.Duration
+		expected       int
+		truncated      bool
+	}{
+		{"shorter than limit", 8000, 1, 2, time.Second, 8000, false},
+		{"exactly the limit", 16000, 1, 2, time.Second, 16000, false},
+		{"longer than limit", 48000, 1, 2, time.Second, 16000, true},
+		{"stereo", 48000, 2, 2, time.Second, 32000, true},
+		{"8-bit", 48000, 1, 1, time.Second, 8000, true},
+		{"32-bit", 48000, 1, 4, time.Second, 32000, true},
+		{"default sample size", 48000, 1, 0, time.Second, 16000, true},
+	}
+
+	for i := range testList {
+		test := testList[i]
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := newMaxDurationReader(bytes.NewReader(make([]byte, test.inputLen)), sampleRate, test.channels,
+				test.bytesPerSample, test.duration)
+
+			b, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(b) != test.expected {
+				t.Errorf("incorrect number of bytes read - expected: %d, actual: %d", test.expected, len(b))
+			}
+
+			if r.Truncated() != test.truncated {
+				t.Errorf("incorrect truncated - expected: %t, actual: %t", test.truncated, r.Truncated())
+			}
+		})
+	}
+}
+
+func TestAudioBytesPerSample(t *testing.T) {
+	t.Parallel()
+
+	raw := func(bitDepth uint32) *transcribepb.RecognitionConfig {
+		return &transcribepb.RecognitionConfig{
+			AudioFormat: &transcribepb.RecognitionConfig_AudioFormatRaw{
+				AudioFormatRaw: &transcribepb.AudioFormatRAW{BitDepth: bitDepth},
+			},
+		}
+	}
+
+	testList := []struct {
+		name     string
+		cfg      *transcribepb.RecognitionConfig
+		expected uint32
+	}{
+		{"8-bit", raw(8), 1},
+		{"16-bit", raw(16), 2},
+		{"24-bit", raw(24), 3},
+		{"32-bit", raw(32), 4},
+		{"12-bit rounds up", raw(12), 2},
+		{"no bit depth", raw(0), defaultBytesPerSample},
+		{"not raw", &transcribepb.RecognitionConfig{}, defaultBytesPerSample},
+	}
+
+	for _, test := range testList {
+		if actual := audioBytesPerSample(test.cfg); actual != test.expected {
+			t.Errorf("%s: incorrect bytes per sample - expected: %d, actual: %d", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestResolveModelIDRequired(t *testing.T) {
+	t.Parallel()
+
+	// No client is needed since the default model must not be looked up.
+	cfg := &transcribepb.RecognitionConfig{}
+
+	err := resolveModelID(context.Background(), log.NewDiscardLogger(), nil, cfg, true)
+	if !errors.Is(err, errModelRequired) {
+		t.Errorf("incorrect error - expected: %v, actual: %v", errModelRequired, err)
+	}
+
+	cfg.ModelId = "1"
+
+	if err := resolveModelID(context.Background(), log.NewDiscardLogger(), nil, cfg, true); err != nil {
+		t.Errorf("unexpected error with an explicit model: %v", err)
+	}
+}
+
+// newTestResult returns a final recognize response with the given
+// transcript and timing.
+func newTestResult(transcript string, startMs, durationMs uint64) *transcribepb.StreamingRecognizeResponse {
+	return &transcribepb.StreamingRecognizeResponse{
+		Result: &transcribepb.RecognitionResult{
+			Alternatives: []*transcribepb.RecognitionAlternative{{
+				TranscriptFormatted: transcript,
+				StartTimeMs:         startMs,
+				DurationMs:          durationMs,
+			}},
+		},
+	}
+}
+
+// writeTestOutput writes the responses with a respWriter and returns the
+// contents of the output file.
+func writeTestOutput(t *testing.T, opts outputOptions, responses ...*transcribepb.StreamingRecognizeResponse) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "out")
+
+	w, err := newRespWriter(nil, path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, resp := range responses {
+		w.write(resp)
+	}
+
+	w.close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestSRTOutput(t *testing.T) {
+	t.Parallel()
+
+	actual := writeTestOutput(t, outputOptions{format: formatSRT},
+		newTestResult("Hello world.", 500, 1250),
+		newTestResult("No timing.", 0, 0),
+		newTestResult("Goodbye.", 3723004, 996),
+	)
+
+	expected := "1\n00:00:00,500 --> 00:00:01,750\nHello world.\n\n" +
+		"2\n01:02:03,004 --> 01:02:04,000\nGoodbye.\n\n"
+
+	if actual != expected {
+		t.Errorf("incorrect SRT output - expected: %q, actual: %q", expected, actual)
+	}
+}
+
+func TestVTTOutput(t *testing.T) {
+	t.Parallel()
+
+	actual := writeTestOutput(t, outputOptions{format: formatVTT},
+		newTestResult("Hello world.", 500, 1250),
+		newTestResult("Goodbye.", 3723004, 996),
+	)
+
+	expected := "WEBVTT\n\n" +
+		"00:00:00.500 --> 00:00:01.750\nHello world.\n\n" +
+		"01:02:03.004 --> 01:02:04.000\nGoodbye.\n\n"
+
+	if actual != expected {
+		t.Errorf("incorrect VTT output - expected: %q, actual: %q", expected, actual)
+	}
+}
+
+func TestWordsOutput(t *testing.T) {
+	t.Parallel()
+
+	withWords := newTestResult("Hello world.", 500, 1250)
+	withWords.Result.Alternatives[0].WordDetails = &transcribepb.WordDetails{
+		Formatted: []*transcribepb.WordInfo{
+			{Word: "Hello", StartTimeMs: 500, DurationMs: 400},
+			{Word: "world.", StartTimeMs: 1000, DurationMs: 750},
+		},
+	}
+
+	// The format defaults to text with --words, even when writing to a file.
+	actual := writeTestOutput(t, outputOptions{words: true},
+		withWords,
+		newTestResult("No words.", 2000, 500),
+	)
+
+	expected := "Hello\t0.500\t0.900\nworld.\t1.000\t1.750\nNo words.\t\t\n"
+
+	if actual != expected {
+		t.Errorf("incorrect words output - expected: %q, actual: %q", expected, actual)
+	}
+}
+
+func TestFilterConfidence(t *testing.T) {
+	t.Parallel()
+
+	resp := &transcribepb.StreamingRecognizeResponse{
+		Result: &transcribepb.RecognitionResult{
+			Alternatives: []*transcribepb.RecognitionAlternative{
+				{TranscriptFormatted: "low", Confidence: 0.49},
+				{TranscriptFormatted: "threshold", Confidence: 0.5},
+				{TranscriptFormatted: "high", Confidence: 0.9},
+			},
+			AudioChannel: 1,
+		},
+	}
+
+	transcripts := func(r *transcribepb.StreamingRecognizeResponse) []string {
+		var s []string
+		for _, alt := range r.Result.Alternatives {
+			s = append(s, alt.TranscriptFormatted)
+		}
+
+		return s
+	}
+
+	// An alternative exactly at the threshold is kept.
+	filtered := filterConfidence(resp, 0.5)
+	if actual := transcripts(filtered); strings.Join(actual, ",") != "threshold,high" {
+		t.Errorf("incorrect alternatives at 0.5: %v", actual)
+	}
+
+	if filtered.Result.AudioChannel != 1 || len(resp.Result.Alternatives) != 3 {
+		t.Error("the filtered response should keep the result fields without changing the original")
+	}
+
+	if filterConfidence(resp, 0) != resp {
+		t.Error("expected the response to be returned as is when all alternatives are kept")
+	}
+
+	if filterConfidence(resp, 0.91) != nil {
+		t.Error("expected nil when all alternatives are below the threshold")
+	}
+}
@@ -27,10 +27,11 @@ var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Fetch version of Transcribe server.",
 	Run: func(cmd *cobra.Command, args []string) {
-		var opts []client.Option
+		opts, err := connectionOptions()
+		if err != nil {
+			cmd.PrintErrf("error: %v\n", err)
 
-		if isInsecure {
-			opts = append(opts, client.WithInsecure())
+			return
 		}
 
 		c, err := client.NewClient(serverAddress, opts...)
@@ -56,7 +57,7 @@ func version(ctx context.Context, c *client.Client) error {
 		return fmt.Errorf("failed to fetch version: %w", err)
 	}
 
-	fmt.Printf("Transcribe server %s\n", v)
+	fmt.Printf("Transcribe server %s\n", v.String())
 
 	return nil
 }
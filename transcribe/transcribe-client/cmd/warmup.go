@@ -0,0 +1,175 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cobaltspeech/examples-go/transcribe/transcribe-client/internal/client"
+	transcribepb "github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5"
+	"github.com/cobaltspeech/log"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultWarmupDuration = time.Second
+	warmupBitDepth        = 16
+	warmupBytesPerSample  = warmupBitDepth / 8
+)
+
+func buildWarmupCmd() *cobra.Command {
+	var (
+		modelID  string
+		all      bool
+		duration time.Duration
+		verbose  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "warmup",
+		Short: "Load models on Transcribe server by sending a short silence clip.",
+		Long: "Some servers only load a model when it is first requested, which makes the first " +
+			"transcription slow. This command streams a short clip of silence to the requested " +
+			"model(s) so they are loaded before real traffic arrives, and reports how long it took.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if modelID == "" && !all {
+				cmd.PrintErrln("error: either --model or --all must be provided")
+
+				return
+			}
+
+			logger := log.NewLeveledLogger(log.WithFilterLevel(getLogLevel(verbose)))
+			opts, err := connectionOptions()
+			if err != nil {
+				cmd.PrintErrf("error: %v\n", err)
+
+				return
+			}
+
+			opts = append(opts, client.WithLogger(logger))
+
+			c, err := client.NewClient(serverAddress, opts...)
+			if err != nil {
+				cmd.PrintErrf("error: failed to create a client: %v\n", err)
+
+				return
+			}
+
+			defer c.Close()
+
+			if err := warmup(context.Background(), logger, c, modelID, all, duration); err != nil {
+				cmd.PrintErrf("error: %v\n", err)
+
+				return
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&modelID, "model", "", "ID of the model to warm up.")
+	cmd.Flags().BoolVar(&all, "all", false, "If flag provided, warm up every model listed by the server.")
+	cmd.Flags().DurationVar(&duration, "duration", defaultWarmupDuration, "Duration of the silence clip sent to each model.")
+	cmd.Flags().IntVarP(&verbose, "verbose", "v", 0, "Logger verbose modes. 0=Info, 1=Debug, 2=Trace")
+
+	return cmd
+}
+
+func warmup(ctx context.Context, logger log.Logger, c *client.Client,
+	modelID string, all bool, duration time.Duration) error {
+	models, err := c.ListModels(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list models: %w", err)
+	}
+
+	for _, mdl := range models {
+		if !all && mdl.Id != modelID {
+			continue
+		}
+
+		elapsed, err := warmupModel(ctx, logger, c, mdl, duration)
+		if err != nil {
+			return fmt.Errorf("failed to warm up model %q: %w", mdl.Id, err)
+		}
+
+		fmt.Printf("Model %q warmed up in %v\n", mdl.Id, elapsed)
+
+		if !all {
+			return nil
+		}
+	}
+
+	if !all {
+		return fmt.Errorf("model %q not found", modelID)
+	}
+
+	return nil
+}
+
+// warmupModel streams the given duration of silence to the model and returns
+// the time taken for the server to finish the recognition.
+func warmupModel(ctx context.Context, logger log.Logger, c *client.Client,
+	mdl *transcribepb.Model, duration time.Duration) (time.Duration, error) {
+	sampleRate := mdl.GetAttributes().GetSampleRate()
+	if sampleRate == 0 {
+		return 0, fmt.Errorf("model does not report a sample rate")
+	}
+
+	cfg := &transcribepb.RecognitionConfig{
+		ModelId: mdl.Id,
+		AudioFormat: &transcribepb.RecognitionConfig_AudioFormatRaw{
+			AudioFormatRaw: &transcribepb.AudioFormatRAW{
+				Encoding:   transcribepb.AudioEncoding_AUDIO_ENCODING_SIGNED,
+				BitDepth:   warmupBitDepth,
+				ByteOrder:  transcribepb.ByteOrder_BYTE_ORDER_LITTLE_ENDIAN,
+				SampleRate: sampleRate,
+				Channels:   1,
+			},
+		},
+	}
+
+	logger.Debug("msg", "start warming up model", "model ID", mdl.Id, "duration", duration)
+
+	start := time.Now()
+
+	err := c.StreamingRecognize(ctx, cfg, newSilenceReader(sampleRate, duration),
+		func(*transcribepb.StreamingRecognizeResponse) {})
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Since(start), nil
+}
+
+// newSilenceReader returns a reader with the given duration of 16-bit mono
+// silence at the given sample rate.
+func newSilenceReader(sampleRate uint32, duration time.Duration) io.Reader {
+	n := int64(duration.Seconds() * float64(sampleRate) * warmupBytesPerSample)
+
+	return io.LimitReader(zeroReader{}, n)
+}
+
+// zeroReader is an io.Reader that never ends and only returns zeros.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+
+	return len(p), nil
+}
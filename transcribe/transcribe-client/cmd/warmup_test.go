@@ -0,0 +1,96 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cobaltspeech/examples-go/transcribe/transcribe-client/internal/client"
+	transcribepb "github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5"
+	"github.com/cobaltspeech/log"
+)
+
+func TestWarmup(t *testing.T) {
+	t.Parallel()
+
+	model := func(id string, sampleRate uint32) *transcribepb.Model {
+		return &transcribepb.Model{Id: id, Attributes: &transcribepb.ModelAttributes{SampleRate: sampleRate}}
+	}
+
+	en16, en8 := model("en-16", 16000), model("en-8", 8000)
+	errList := errors.New("connection refused")
+
+	const duration = 100 * time.Millisecond
+
+	testList := []struct {
+		name     string
+		modelID  string
+		all      bool
+		listErr  error
+		expected []*transcribepb.Model // models warmed up, in order
+		wantErr  bool
+	}{
+		{"single model", "en-8", false, nil, []*transcribepb.Model{en8}, false},
+		{"all models", "", true, nil, []*transcribepb.Model{en16, en8}, false},
+		{"unknown model", "fr-8", false, nil, nil, true},
+		{"list models error", "en-8", false, errList, nil, true},
+	}
+
+	for i := range testList {
+		test := testList[i]
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := &client.FakeTranscribeService{Models: []*transcribepb.Model{en16, en8}, Err: test.listErr}
+
+			err := warmup(context.Background(), log.NewDiscardLogger(), client.NewFakeClient(svc), test.modelID, test.all, duration)
+			if test.wantErr && err == nil {
+				t.Fatal("expected an error")
+			} else if !test.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if test.listErr != nil && !errors.Is(err, test.listErr) {
+				t.Errorf("incorrect error - expected: %v, actual: %v", test.listErr, err)
+			}
+
+			if len(svc.Streams) != len(test.expected) {
+				t.Fatalf("incorrect number of streams - expected: %d, actual: %d", len(test.expected), len(svc.Streams))
+			}
+
+			for i, stream := range svc.Streams {
+				mdl, cfg := test.expected[i], stream.Config()
+
+				if cfg.GetModelId() != mdl.Id {
+					t.Errorf("incorrect model ID - expected: %s, actual: %s", mdl.Id, cfg.GetModelId())
+				}
+
+				sampleRate := mdl.GetAttributes().GetSampleRate()
+				if actual := cfg.GetAudioFormatRaw().GetSampleRate(); actual != sampleRate {
+					t.Errorf("incorrect sample rate - expected: %d, actual: %d", sampleRate, actual)
+				}
+
+				expectedLen := int(duration.Seconds() * float64(sampleRate) * warmupBytesPerSample)
+				if actual := len(stream.Audio()); actual != expectedLen {
+					t.Errorf("incorrect audio length - expected: %d, actual: %d", expectedLen, actual)
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ module github.com/cobaltspeech/examples-go/transcribe/transcribe-client
 go 1.19
 
 require (
+	github.com/cobaltspeech/examples-go/pkg v0.0.0
 	github.com/cobaltspeech/go-genproto v0.0.0-20230314065520-94cfa3ab0ae8
 	github.com/cobaltspeech/log v0.1.12
 	github.com/spf13/cobra v1.6.1
@@ -19,3 +20,5 @@ require (
 	google.golang.org/genproto v0.0.0-20230306155012-7f2fa6fef1f4 // indirect
 	google.golang.org/protobuf v1.31.0 // indirect
 )
+
+replace github.com/cobaltspeech/examples-go/pkg => ../../pkg
@@ -20,68 +20,217 @@ package client
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/cobaltspeech/examples-go/pkg/backoff"
 	"github.com/cobaltspeech/log"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	transcribepb "github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5"
 )
 
 const defaultStreamingBufsize uint32 = 1024
 
+// maxRetryDelayFactor caps the delay between retries at this many times the
+// base delay.
+const maxRetryDelayFactor = 32
+
 type Client struct {
-	tclient          transcribepb.TranscribeServiceClient
-	conn             *grpc.ClientConn
+	mu      sync.Mutex // protects tclient, conn and gen, which change on re-dial
+	tclient transcribepb.TranscribeServiceClient
+	conn    *grpc.ClientConn
+	gen     int // number of re-dials, so concurrent retries re-dial once
+
+	dial             dialFunc
 	log              log.Logger
 	streamingBufSize uint32
+	retryAttempts    int
+	retryBaseDelay   time.Duration
+	progress         ProgressFunc
+	metadata         []string // key-value pairs sent with every request
 }
 
+// dialFunc connects to the server.
+type dialFunc func(ctx context.Context) (transcribepb.TranscribeServiceClient, *grpc.ClientConn, error)
+
 func NewClient(addr string, opts ...Option) (*Client, error) {
-	args := clientArgs{
-		streamingBufSize: defaultStreamingBufsize,
-		log:              log.NewDiscardLogger(),
-		ctx:              context.Background(),
-		creds:            credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12}),
+	args, err := newClientArgs(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a client: %w", err)
 	}
 
-	for _, opt := range opts {
-		err := opt(&args)
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(args.transportCredentials()),
+	}
+
+	dial := func(ctx context.Context) (transcribepb.TranscribeServiceClient, *grpc.ClientConn, error) {
+		conn, err := grpc.DialContext(ctx, addr, dialOpts...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create a client: %w", err)
+			return nil, nil, fmt.Errorf("failed to create a client connection: %w\n", err)
 		}
-	}
 
-	dialOpts := []grpc.DialOption{
-		grpc.WithTransportCredentials(args.creds),
+		return transcribepb.NewTranscribeServiceClient(conn), conn, nil
 	}
 
-	conn, err := grpc.DialContext(args.ctx, addr, dialOpts...)
+	tclient, conn, err := dial(args.ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create a client connection: %w\n", err)
+		return nil, err
 	}
 
 	return &Client{
-		tclient:          transcribepb.NewTranscribeServiceClient(conn),
+		tclient:          tclient,
 		conn:             conn,
+		dial:             dial,
 		streamingBufSize: args.streamingBufSize,
 		log:              args.log,
+		retryAttempts:    args.retryAttempts,
+		retryBaseDelay:   args.retryBaseDelay,
+		progress:         args.progress,
+		metadata:         args.metadataPairs(),
 	}, nil
 }
 
+// service returns the current transcribe service client.
+func (c *Client) service() transcribepb.TranscribeServiceClient {
+	svc, _ := c.serviceGen()
+
+	return svc
+}
+
+// serviceGen returns the current transcribe service client and the
+// generation of its connection.
+func (c *Client) serviceGen() (transcribepb.TranscribeServiceClient, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.tclient, c.gen
+}
+
+// outgoingContext returns the context with the configured metadata attached.
+func (c *Client) outgoingContext(ctx context.Context) context.Context {
+	if len(c.metadata) == 0 {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, c.metadata...)
+}
+
+// redial replaces the connection to the server with a new one, if it is still
+// the connection of generation gen, used by the failed stream, and it is
+// unusable. Otherwise the connection is kept, as it may still carry the
+// streams of other goroutines sharing the client, and gRPC reconnects it on
+// its own; closing it would cancel them.
+func (c *Client) redial(ctx context.Context, gen int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.gen != gen || !connUnusable(c.conn) {
+		return nil
+	}
+
+	tclient, conn, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	old := c.conn
+	c.tclient, c.conn = tclient, conn
+	c.gen++
+
+	if old != nil {
+		old.Close()
+	}
+
+	return nil
+}
+
+// connUnusable returns whether the connection failed and must be replaced. A
+// nil connection, which only test clients have, is always replaced.
+func connUnusable(conn *grpc.ClientConn) bool {
+	if conn == nil {
+		return true
+	}
+
+	switch conn.GetState() {
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		return true
+	default:
+		return false
+	}
+}
+
 type clientArgs struct {
 	log              log.Logger
 	streamingBufSize uint32
-	creds            credentials.TransportCredentials
+	retryAttempts    int
+	retryBaseDelay   time.Duration
+	progress         ProgressFunc
+	metadata         map[string]string
+	insecure         bool
+	tlsConfig        *tls.Config
 	ctx              context.Context
 }
 
+// newClientArgs returns the default clientArgs with the given options applied.
+func newClientArgs(opts ...Option) (*clientArgs, error) {
+	args := &clientArgs{
+		streamingBufSize: defaultStreamingBufsize,
+		log:              log.NewDiscardLogger(),
+		retryAttempts:    1,
+		ctx:              context.Background(),
+		tlsConfig:        &tls.Config{MinVersion: tls.VersionTLS12},
+	}
+
+	for _, opt := range opts {
+		if err := opt(args); err != nil {
+			return nil, err
+		}
+	}
+
+	return args, nil
+}
+
+// metadataPairs returns the metadata as key-value pairs, sorted by key.
+func (c *clientArgs) metadataPairs() []string {
+	keys := make([]string, 0, len(c.metadata))
+	for k := range c.metadata {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, 2*len(keys)) //nolint:gomnd // a key and a value per entry
+	for _, k := range keys {
+		pairs = append(pairs, k, c.metadata[k])
+	}
+
+	return pairs
+}
+
+// transportCredentials returns the credentials to use for the connection.
+func (c *clientArgs) transportCredentials() credentials.TransportCredentials {
+	if c.insecure {
+		return insecure.NewCredentials()
+	}
+
+	return credentials.NewTLS(c.tlsConfig)
+}
+
 // Option configures how we setup the connection with a server.
 type Option func(*clientArgs) error
 
@@ -115,15 +264,201 @@ func WithLogger(logger log.Logger) Option {
 }
 
 // WithInsecure returns an Option that sets up Client without
-// using TLS enable.
+// using TLS enable. The TLS options have no effect on an insecure Client.
 func WithInsecure() Option {
 	return func(c *clientArgs) error {
-		c.creds = insecure.NewCredentials()
+		c.insecure = true
+
+		return nil
+	}
+}
+
+// WithTLSMinVersion returns an Option that sets the minimum TLS version
+// accepted by the Client, e.g. tls.VersionTLS13. The default is TLS 1.2.
+func WithTLSMinVersion(v uint16) Option {
+	return func(c *clientArgs) error {
+		switch v {
+		case tls.VersionTLS12, tls.VersionTLS13:
+		default:
+			return fmt.Errorf("unsupported TLS minimum version 0x%04x", v)
+		}
+
+		c.tlsConfig.MinVersion = v
 
 		return nil
 	}
 }
 
+// WithTLSCipherSuites returns an Option that restricts the cipher suites the
+// Client may use for TLS 1.2 connections to the given IDs, e.g.
+// tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384. Only the suites listed by
+// tls.CipherSuites are accepted. TLS 1.3 cipher suites are not configurable.
+func WithTLSCipherSuites(ids ...uint16) Option {
+	return func(c *clientArgs) error {
+		if len(ids) == 0 {
+			return fmt.Errorf("no TLS cipher suites given")
+		}
+
+		supported := make(map[uint16]bool)
+		for _, s := range tls.CipherSuites() {
+			supported[s.ID] = true
+		}
+
+		for _, id := range ids {
+			if !supported[id] {
+				return fmt.Errorf("unsupported TLS cipher suite 0x%04x", id)
+			}
+		}
+
+		c.tlsConfig.CipherSuites = ids
+
+		return nil
+	}
+}
+
+// WithRetry returns an Option that makes StreamingRecognize retry a stream
+// that failed because the server was unavailable or out of resources. The
+// client re-dials the server and restarts the stream from the beginning,
+// making up to maxAttempts attempts in total with exponentially increasing
+// delays starting at baseDelay.
+//
+// A Client may be shared by concurrent streams. A retry only replaces the
+// connection if it is in a failed state, and concurrent retries of streams
+// that failed on the same connection replace it once; otherwise the stream
+// is restarted on the current connection, which gRPC reconnects as needed.
+// The other streams on the connection are never cancelled by a retry.
+//
+// Restarting the stream loses the position in the audio, so retries only
+// happen if the audio io.Reader is also an io.Seeker (e.g. an *os.File); it
+// is rewound to offset 0 before each retry. The response handler receives
+// the results of the failed attempts too, so it may see the start of the
+// audio more than once. A value maxAttempts>=1 and a positive baseDelay are
+// required.
+func WithRetry(maxAttempts int, baseDelay time.Duration) Option {
+	return func(c *clientArgs) error {
+		if maxAttempts < 1 {
+			return fmt.Errorf("invalid number of attempts %d, must be at least 1", maxAttempts)
+		}
+
+		if baseDelay <= 0 {
+			return fmt.Errorf("invalid retry delay %v, must be positive", baseDelay)
+		}
+
+		c.retryAttempts = maxAttempts
+		c.retryBaseDelay = baseDelay
+
+		return nil
+	}
+}
+
+// WithProgress returns an Option that makes StreamingRecognize report its
+// progress to fn, e.g. to render a progress bar for long files. The callback
+// runs on a separate goroutine and must not block for long: while it runs,
+// updates are dropped except for the latest one. StreamingRecognize waits for
+// the last update to be delivered before returning.
+func WithProgress(fn ProgressFunc) Option {
+	return func(c *clientArgs) error {
+		c.progress = fn
+
+		return nil
+	}
+}
+
+// WithCACert returns an Option that makes the Client verify the server's
+// certificate against the CA certificates in the PEM file at path, instead of
+// the system's CA certificates, e.g. for self-signed deployments.
+func WithCACert(path string) Option {
+	return func(c *clientArgs) error {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in CA certificate file %s", path)
+		}
+
+		c.tlsConfig.RootCAs = pool
+
+		return nil
+	}
+}
+
+// WithClientCert returns an Option that makes the Client present the
+// certificate in the PEM files certPath and keyPath to the server, for
+// servers that require mutual TLS. Both paths are required. It can be
+// combined with WithCACert.
+func WithClientCert(certPath, keyPath string) Option {
+	return func(c *clientArgs) error {
+		if certPath == "" || keyPath == "" {
+			return fmt.Errorf("both a client certificate and key are required for mutual TLS")
+		}
+
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return fmt.Errorf("failed to load client certificate: %w", err)
+		}
+
+		c.tlsConfig.Certificates = []tls.Certificate{cert}
+
+		return nil
+	}
+}
+
+// WithServerNameOverride returns an Option that sets the name the server's
+// certificate is verified against, instead of the host of the address, e.g.
+// when connecting with an IP address to a server whose certificate only has
+// its host name. It is also sent to the server for SNI.
+func WithServerNameOverride(name string) Option {
+	return func(c *clientArgs) error {
+		if name == "" {
+			return fmt.Errorf("empty server name override")
+		}
+
+		c.tlsConfig.ServerName = name
+
+		return nil
+	}
+}
+
+// authorizationKey is the metadata key of the authorization token.
+const authorizationKey = "authorization"
+
+// WithMetadata returns an Option that sends the given gRPC metadata with every
+// request, e.g. for a gateway in front of the server. Keys are converted to
+// lower case, and later options override the values of earlier ones. The
+// metadata is never logged.
+func WithMetadata(md map[string]string) Option {
+	return func(c *clientArgs) error {
+		if c.metadata == nil {
+			c.metadata = make(map[string]string, len(md))
+		}
+
+		for k, v := range md {
+			if k == "" {
+				return fmt.Errorf("empty metadata key")
+			}
+
+			c.metadata[strings.ToLower(k)] = v
+		}
+
+		return nil
+	}
+}
+
+// WithAuthToken returns an Option that sends the token as a bearer token in
+// the authorization metadata of every request. The token is never logged.
+func WithAuthToken(token string) Option {
+	return func(c *clientArgs) error {
+		if token == "" {
+			return fmt.Errorf("empty auth token")
+		}
+
+		return WithMetadata(map[string]string{authorizationKey: "Bearer " + token})(c)
+	}
+}
+
 // WithContext returns an Option that sets up context.Context to
 // use for GRPC client connection.
 func WithContext(ctx context.Context) Option {
@@ -138,19 +473,36 @@ func WithContext(ctx context.Context) Option {
 	}
 }
 
-// Versions queries the version of the server.
-func (c *Client) Versions(ctx context.Context) (string, error) {
-	v, err := c.tclient.Version(ctx, &transcribepb.VersionRequest{})
+// Versions contains the versions of the server components. The v5
+// VersionResponse only reports the server version; fields for other
+// components can be added here as the API exposes them.
+type Versions struct {
+	Server string // Server is the version of Transcribe server.
+}
+
+// String returns the versions for display.
+func (v Versions) String() string {
+	return v.Server
+}
+
+// newVersions returns the Versions reported by the VersionResponse.
+func newVersions(resp *transcribepb.VersionResponse) Versions {
+	return Versions{Server: resp.GetVersion()}
+}
+
+// Versions queries the versions of the server.
+func (c *Client) Versions(ctx context.Context) (Versions, error) {
+	v, err := c.service().Version(c.outgoingContext(ctx), &transcribepb.VersionRequest{})
 	if err != nil {
-		return "", err
+		return Versions{}, err
 	}
 
-	return v.Version, nil
+	return newVersions(v), nil
 }
 
 // ListModels retrieves a list of available speech recognition models.
 func (c *Client) ListModels(ctx context.Context) ([]*transcribepb.Model, error) {
-	resp, err := c.tclient.ListModels(ctx, &transcribepb.ListModelsRequest{})
+	resp, err := c.service().ListModels(c.outgoingContext(ctx), &transcribepb.ListModelsRequest{})
 	if err != nil {
 		return nil, err
 	}
@@ -158,6 +510,36 @@ func (c *Client) ListModels(ctx context.Context) ([]*transcribepb.Model, error)
 	return resp.Models, nil
 }
 
+var (
+	// ErrUnreachable is returned by Ready when the server does not respond.
+	ErrUnreachable = errors.New("server is unreachable")
+
+	// ErrNoModels is returned by Ready when the server responds but has no
+	// models loaded.
+	ErrNoModels = errors.New("server has no models loaded")
+)
+
+// Ready checks that the server is reachable and ready to transcribe audio, by
+// querying its version and list of models. It returns an error wrapping
+// ErrUnreachable if the server does not respond, ErrNoModels if the server has
+// no models, and nil otherwise.
+func (c *Client) Ready(ctx context.Context) error {
+	if _, err := c.Versions(ctx); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnreachable, err)
+	}
+
+	models, err := c.ListModels(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnreachable, err)
+	}
+
+	if len(models) == 0 {
+		return ErrNoModels
+	}
+
+	return nil
+}
+
 // RecognitionResponseHandler is a type of callback function that will be called
 // when the `StreamingRecognize` method is running.  For each response received
 // from transcribe server, this method will be called once.  The provided
@@ -173,10 +555,74 @@ type RecognitionResponseHandler func(*transcribepb.StreamingRecognizeResponse)
 // sent to the provided handlerFunc. If any error occurs while reading the audio
 // or sending it to the server, this method will immediately exit, returning that
 // error. This function returns only after all results have been passed to the
-// resultHandler.
+// resultHandler. See WithRetry for retrying streams that fail because the
+// server is briefly unavailable.
 func (c *Client) StreamingRecognize(ctx context.Context,
 	cfg *transcribepb.RecognitionConfig,
 	audio io.Reader, handler RecognitionResponseHandler) error {
+	gen, err := c.streamingRecognize(ctx, cfg, audio, handler)
+	if err == nil || c.retryAttempts <= 1 {
+		return err
+	}
+
+	seeker, ok := audio.(io.Seeker)
+	if !ok {
+		if isRetriable(err) {
+			c.log.Debug("msg", "not retrying streaming recognition, the audio is not seekable", "err", err)
+		}
+
+		return err
+	}
+
+	b, berr := backoff.New(c.retryBaseDelay, c.retryBaseDelay*maxRetryDelayFactor)
+	if berr != nil {
+		return berr
+	}
+
+	for attempt := 2; attempt <= c.retryAttempts && isRetriable(err); attempt++ {
+		c.log.Info("msg", "retrying streaming recognition", "attempt", attempt, "err", err)
+
+		if werr := b.Wait(ctx); werr != nil {
+			return err
+		}
+
+		if _, serr := seeker.Seek(0, io.SeekStart); serr != nil {
+			return fmt.Errorf("failed to rewind the audio: %w", serr)
+		}
+
+		if derr := c.redial(ctx, gen); derr != nil {
+			err = derr
+
+			continue
+		}
+
+		gen, err = c.streamingRecognize(ctx, cfg, audio, handler)
+	}
+
+	return err
+}
+
+// isRetriable returns whether the error is a gRPC status for which it is
+// worth restarting the stream.
+func isRetriable(err error) bool {
+	var s interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &s) {
+		return false
+	}
+
+	switch s.GRPCStatus().Code() {
+	case codes.Unavailable, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// streamingRecognize makes a single StreamingRecognize attempt, and returns
+// the generation of the connection it used.
+func (c *Client) streamingRecognize(ctx context.Context,
+	cfg *transcribepb.RecognitionConfig,
+	audio io.Reader, handler RecognitionResponseHandler) (int, error) {
 	var handlerErr error
 
 	handlerpb := func(resp *transcribepb.StreamingRecognizeResponse) {
@@ -188,9 +634,11 @@ func (c *Client) StreamingRecognize(ctx context.Context,
 	}
 
 	// Creating stream.
-	stream, err := c.tclient.StreamingRecognize(ctx)
+	svc, gen := c.serviceGen()
+
+	stream, err := svc.StreamingRecognize(c.outgoingContext(ctx))
 	if err != nil {
-		return err
+		return gen, err
 	}
 
 	// There are two concurrent processes going on.  We will create a new
@@ -202,13 +650,16 @@ func (c *Client) StreamingRecognize(ctx context.Context,
 	// capacity of two.
 	errch := make(chan error, 2) //nolint:gomnd // 2 is not magic number as explained above.
 
+	progress := newProgressReporter(c.progress)
+	defer progress.stop()
+
 	var wg sync.WaitGroup
 
 	wg.Add(1)
 
 	// start streaming audio in a separate goroutine
 	go func() {
-		if err := sendaudio(stream, cfg, audio, c.streamingBufSize); err != nil && !errors.Is(err, io.EOF) {
+		if err := sendaudio(stream, cfg, audio, c.streamingBufSize, progress); err != nil && !errors.Is(err, io.EOF) {
 			// if sendaudio encountered io.EOF, it's only a
 			// notification that the stream has closed.  The actual
 			// status will be obtained in a subsequent Recv call, in
@@ -231,6 +682,7 @@ func (c *Client) StreamingRecognize(ctx context.Context,
 			break
 		}
 
+		progress.addResult(in)
 		handlerpb(in)
 	}
 
@@ -242,21 +694,53 @@ func (c *Client) StreamingRecognize(ctx context.Context,
 		// very likely they are related (e.g. connection reset causing
 		// both the send and recv to fail) and we therefore return the
 		// first error and discard the other.
-		return fmt.Errorf("streaming recognition failed: %w", err)
+		return gen, fmt.Errorf("streaming recognition failed: %w", err)
 	default:
 	}
 
 	if handlerErr != nil {
-		return handlerErr
+		return gen, handlerErr
 	}
 
-	return nil
+	return gen, nil
+}
+
+// StreamingRecognizeChan is a variant of StreamingRecognize that delivers
+// the responses received from Transcribe server on a channel instead of
+// through a callback. The response channel is closed once the stream is
+// done, after which the error channel delivers the error that ended the
+// stream (if any) and is closed. The caller must keep receiving from the
+// response channel until it is closed, or cancel ctx to stop the stream.
+func (c *Client) StreamingRecognizeChan(ctx context.Context,
+	cfg *transcribepb.RecognitionConfig,
+	audio io.Reader) (<-chan *transcribepb.StreamingRecognizeResponse, <-chan error) {
+	respCh := make(chan *transcribepb.StreamingRecognizeResponse)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(errCh)
+
+		err := c.StreamingRecognize(ctx, cfg, audio, func(resp *transcribepb.StreamingRecognizeResponse) {
+			select {
+			case respCh <- resp:
+			case <-ctx.Done():
+			}
+		})
+
+		close(respCh)
+
+		if err != nil {
+			errCh <- err
+		}
+	}()
+
+	return respCh, errCh
 }
 
 // sendaudio sends audio to a stream.
 func sendaudio(stream transcribepb.TranscribeService_StreamingRecognizeClient,
 	cfg *transcribepb.RecognitionConfig, audio io.Reader,
-	bufsize uint32) error {
+	bufsize uint32, progress *progressReporter) error {
 	// The first message needs to be a config message, and all subsequent
 	// messages must be audio messages.
 	// Send the recognition config
@@ -283,6 +767,8 @@ func sendaudio(stream transcribepb.TranscribeService_StreamingRecognizeClient,
 				// CloseSend.
 				return err2
 			}
+
+			progress.addSent(n)
 		}
 
 		if err != nil {
@@ -319,7 +805,7 @@ func (c *Client) CompileContext(ctx context.Context,
 		Phrases: phrases,
 	}
 
-	compiled, err := c.tclient.CompileContext(ctx, req)
+	compiled, err := c.service().CompileContext(c.outgoingContext(ctx), req)
 	if err != nil {
 		return nil, err
 	}
@@ -328,5 +814,8 @@ func (c *Client) CompileContext(ctx context.Context,
 }
 
 func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	return c.conn.Close()
 }
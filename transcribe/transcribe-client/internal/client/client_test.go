@@ -0,0 +1,490 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	transcribepb "github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func newTestResponse(transcript string) *transcribepb.StreamingRecognizeResponse {
+	return &transcribepb.StreamingRecognizeResponse{
+		Result: &transcribepb.RecognitionResult{
+			Alternatives: []*transcribepb.RecognitionAlternative{{TranscriptFormatted: transcript}},
+		},
+	}
+}
+
+func TestStreamingRecognizeChan(t *testing.T) {
+	t.Parallel()
+
+	expected := []string{"one", "two", "three"}
+
+	svc := &FakeTranscribeService{}
+	for _, s := range expected {
+		svc.Responses = append(svc.Responses, newTestResponse(s))
+	}
+
+	c := NewFakeClient(svc)
+
+	respCh, errCh := c.StreamingRecognizeChan(context.Background(),
+		&transcribepb.RecognitionConfig{}, strings.NewReader("some audio"))
+
+	var actual []string
+	for resp := range respCh {
+		actual = append(actual, resp.Result.Alternatives[0].TranscriptFormatted)
+	}
+
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Errorf("incorrect responses - expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestReady(t *testing.T) {
+	t.Parallel()
+
+	testList := []struct {
+		name     string
+		svc      *FakeTranscribeService
+		expected error
+	}{
+		{"ok", &FakeTranscribeService{Models: []*transcribepb.Model{{Id: "1"}}}, nil},
+		{"unreachable", &FakeTranscribeService{Err: errors.New("connection refused")}, ErrUnreachable},
+		{"no models", &FakeTranscribeService{}, ErrNoModels},
+	}
+
+	for i := range testList {
+		test := testList[i]
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := NewFakeClient(test.svc).Ready(context.Background())
+
+			if test.expected == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !errors.Is(err, test.expected) {
+				t.Errorf("incorrect error - expected: %v, actual: %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestTLSOptions(t *testing.T) {
+	t.Parallel()
+
+	args, err := newClientArgs(
+		WithTLSMinVersion(tls.VersionTLS13),
+		WithTLSCipherSuites(tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if args.tlsConfig.MinVersion != tls.VersionTLS13 {
+		t.Errorf("incorrect min version - expected: %x, actual: %x", tls.VersionTLS13, args.tlsConfig.MinVersion)
+	}
+
+	expected := []uint16{tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}
+	if len(args.tlsConfig.CipherSuites) != 1 || args.tlsConfig.CipherSuites[0] != expected[0] {
+		t.Errorf("incorrect cipher suites - expected: %v, actual: %v", expected, args.tlsConfig.CipherSuites)
+	}
+
+	if p := args.transportCredentials().Info().SecurityProtocol; p != "tls" {
+		t.Errorf("incorrect security protocol - expected: tls, actual: %s", p)
+	}
+}
+
+func TestTLSOptionsInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, opt := range []Option{
+		WithTLSMinVersion(tls.VersionTLS10),
+		WithTLSMinVersion(0),
+		WithTLSCipherSuites(),
+		WithTLSCipherSuites(0xffff),
+	} {
+		if _, err := newClientArgs(opt); err == nil {
+			t.Error("expected an error for an invalid TLS option")
+		}
+	}
+}
+
+func TestStreamingRecognizeRetry(t *testing.T) {
+	t.Parallel()
+
+	const audio = "some audio"
+
+	unavailable := status.Error(codes.Unavailable, "connection reset")
+
+	testList := []struct {
+		name       string
+		recvErr    error     // error of the first stream
+		audio      io.Reader // audio to send
+		attempts   int
+		retried    bool // whether the stream should be retried
+		expectedOK bool
+	}{
+		{"retried", unavailable, strings.NewReader(audio), 3, true, true},
+		{"exhausted", unavailable, strings.NewReader(audio), 1, false, false},
+		{"not seekable", unavailable, struct{ io.Reader }{strings.NewReader(audio)}, 3, false, false},
+		{"not retriable", status.Error(codes.InvalidArgument, "bad config"), strings.NewReader(audio), 3, false, false},
+	}
+
+	for i := range testList {
+		test := testList[i]
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			failing := &FakeTranscribeService{RecvErr: test.recvErr}
+			working := &FakeTranscribeService{Responses: []*transcribepb.StreamingRecognizeResponse{newTestResponse("ok")}}
+
+			dials := 0
+			c := NewFakeClient(failing)
+			c.retryAttempts = test.attempts
+			c.retryBaseDelay = time.Millisecond
+			c.dial = func(context.Context) (transcribepb.TranscribeServiceClient, *grpc.ClientConn, error) {
+				dials++
+
+				return working, nil, nil
+			}
+
+			var results []string
+
+			err := c.StreamingRecognize(context.Background(), &transcribepb.RecognitionConfig{}, test.audio,
+				func(resp *transcribepb.StreamingRecognizeResponse) {
+					results = append(results, resp.Result.Alternatives[0].TranscriptFormatted)
+				})
+
+			if test.expectedOK && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			} else if !test.expectedOK && err == nil {
+				t.Fatal("expected an error")
+			}
+
+			if retried := dials > 0; retried != test.retried {
+				t.Fatalf("incorrect retry - expected: %v, actual: %v", test.retried, retried)
+			}
+
+			if !test.retried {
+				return
+			}
+
+			if len(results) != 1 || results[0] != "ok" {
+				t.Errorf("incorrect results: %v", results)
+			}
+
+			// The retried stream sends the audio from the start.
+			if actual := working.Streams[0].Audio(); actual != audio {
+				t.Errorf("incorrect retried audio - expected: %q, actual: %q", audio, actual)
+			}
+		})
+	}
+}
+
+func TestRedial(t *testing.T) {
+	t.Parallel()
+
+	dials := 0
+	c := NewFakeClient(&FakeTranscribeService{})
+	c.dial = func(context.Context) (transcribepb.TranscribeServiceClient, *grpc.ClientConn, error) {
+		dials++
+
+		return &FakeTranscribeService{}, nil, nil
+	}
+
+	// Retries of streams that failed on the same connection re-dial once.
+	for i := 0; i < 2; i++ {
+		if err := c.redial(context.Background(), 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if dials != 1 {
+		t.Errorf("incorrect number of dials - expected: 1, actual: %d", dials)
+	}
+
+	// A connection that hasn't failed is kept for the other streams using
+	// it.
+	conn, err := grpc.Dial("localhost:1", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	c.conn = conn
+
+	if err := c.redial(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if dials != 1 || c.conn != conn {
+		t.Errorf("a usable connection was replaced, dials: %d", dials)
+	}
+}
+
+func TestWithRetryInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, opt := range []Option{
+		WithRetry(0, time.Second),
+		WithRetry(3, 0),
+	} {
+		if _, err := newClientArgs(opt); err == nil {
+			t.Error("expected an error for an invalid retry option")
+		}
+	}
+}
+
+func TestStreamingRecognizeProgress(t *testing.T) {
+	t.Parallel()
+
+	resp := newTestResponse("ok")
+	resp.Result.Alternatives[0].StartTimeMs = 1000
+	resp.Result.Alternatives[0].DurationMs = 500
+
+	svc := &FakeTranscribeService{Responses: []*transcribepb.StreamingRecognizeResponse{resp}}
+	c := NewFakeClient(svc)
+	c.streamingBufSize = 4
+
+	var (
+		calls     int
+		bytesSent int64
+		resultEnd time.Duration
+	)
+
+	// The first update blocks until a result is handled, which can only
+	// happen once all the audio has been sent.
+	handled := make(chan struct{})
+
+	c.progress = func(n int64, end time.Duration) {
+		if calls == 0 {
+			<-handled
+		}
+
+		calls++
+		bytesSent, resultEnd = n, end
+	}
+
+	const audio = "some audio to send in chunks"
+
+	err := c.StreamingRecognize(context.Background(), &transcribepb.RecognitionConfig{}, strings.NewReader(audio),
+		func(*transcribepb.StreamingRecognizeResponse) { close(handled) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytesSent != int64(len(audio)) || resultEnd != 1500*time.Millisecond {
+		t.Errorf("incorrect last update - expected: %d, %v, actual: %d, %v",
+			len(audio), 1500*time.Millisecond, bytesSent, resultEnd)
+	}
+
+	// Updates made while the callback was blocked were merged.
+	if maxCalls := len(audio)/4 + 1; calls >= maxCalls {
+		t.Errorf("expected fewer than %d progress updates, got %d", maxCalls, calls)
+	}
+}
+
+// writeTestCACert writes a self-signed CA certificate to a PEM file and
+// returns its path.
+func writeTestCACert(t *testing.T) string {
+	t.Helper()
+
+	certPath, _ := writeTestCert(t)
+
+	return certPath
+}
+
+// writeTestCert writes a self-signed certificate and its key to PEM files and
+// returns their paths.
+func writeTestCert(t *testing.T) (certPath, keyPath string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certPath = filepath.Join(dir, "cert.pem")
+	keyPath = filepath.Join(dir, "key.pem")
+
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return certPath, keyPath
+}
+
+func TestCACertOptions(t *testing.T) {
+	t.Parallel()
+
+	args, err := newClientArgs(WithCACert(writeTestCACert(t)), WithServerNameOverride("transcribe.example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if args.tlsConfig.RootCAs == nil {
+		t.Error("expected the CA certificate pool to be set")
+	}
+
+	if args.tlsConfig.ServerName != "transcribe.example.com" {
+		t.Errorf("incorrect server name - expected: transcribe.example.com, actual: %s", args.tlsConfig.ServerName)
+	}
+
+	notPEM := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, opt := range []Option{
+		WithCACert(notPEM),
+		WithCACert(filepath.Join(t.TempDir(), "missing.pem")),
+		WithServerNameOverride(""),
+	} {
+		if _, err := newClientArgs(opt); err == nil {
+			t.Error("expected an error for an invalid CA certificate or server name option")
+		}
+	}
+}
+
+func TestClientCertOption(t *testing.T) {
+	t.Parallel()
+
+	certPath, keyPath := writeTestCert(t)
+
+	args, err := newClientArgs(WithClientCert(certPath, keyPath), WithCACert(certPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(args.tlsConfig.Certificates) != 1 || args.tlsConfig.RootCAs == nil {
+		t.Errorf("expected a client certificate and CA pool, got %d certificates and pool %v",
+			len(args.tlsConfig.Certificates), args.tlsConfig.RootCAs)
+	}
+
+	for _, opt := range []Option{
+		WithClientCert(certPath, ""),
+		WithClientCert("", keyPath),
+		WithClientCert(keyPath, certPath),
+	} {
+		if _, err := newClientArgs(opt); err == nil {
+			t.Error("expected an error for an invalid client certificate option")
+		}
+	}
+}
+
+func TestMetadataOptions(t *testing.T) {
+	t.Parallel()
+
+	args, err := newClientArgs(
+		WithMetadata(map[string]string{"X-Tenant": "acme", "authorization": "overridden"}),
+		WithAuthToken("secret"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := &FakeTranscribeService{VersionString: "1.0"}
+	c := NewFakeClient(svc)
+	c.metadata = args.metadataPairs()
+
+	if _, err := c.Versions(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := svc.MD.Get("authorization"); len(v) != 1 || v[0] != "Bearer secret" {
+		t.Errorf("incorrect authorization metadata: %v", v)
+	}
+
+	if v := svc.MD.Get("x-tenant"); len(v) != 1 || v[0] != "acme" {
+		t.Errorf("incorrect x-tenant metadata: %v", v)
+	}
+
+	for _, opt := range []Option{WithAuthToken(""), WithMetadata(map[string]string{"": "value"})} {
+		if _, err := newClientArgs(opt); err == nil {
+			t.Error("expected an error for an invalid metadata option")
+		}
+	}
+}
+
+func TestVersions(t *testing.T) {
+	t.Parallel()
+
+	v, err := NewFakeClient(&FakeTranscribeService{VersionString: "v5.2.0"}).Versions(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (Versions{Server: "v5.2.0"}); v != expected {
+		t.Errorf("incorrect versions - expected: %+v, actual: %+v", expected, v)
+	}
+
+	if v.String() != "v5.2.0" {
+		t.Errorf("incorrect string - expected: v5.2.0, actual: %s", v.String())
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright (2023 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+
+	transcribepb "github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5"
+	"github.com/cobaltspeech/log"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// FakeTranscribeService is a fake transcribepb.TranscribeServiceClient for
+// tests that returns canned responses. The zero value is ready to use.
+type FakeTranscribeService struct {
+	transcribepb.TranscribeServiceClient
+
+	// VersionString and Models are returned by Version and ListModels.
+	VersionString string
+	Models        []*transcribepb.Model
+
+	// Responses are returned by each stream once all its audio is sent.
+	Responses []*transcribepb.StreamingRecognizeResponse
+
+	// Err, if set, is returned by every method.
+	Err error
+
+	// RecvErr, if set, is returned by the streams once all the audio is
+	// sent, instead of the Responses.
+	RecvErr error
+
+	// Streams are the streams created by StreamingRecognize, in order.
+	Streams []*FakeRecognizeStream
+
+	// MD is the outgoing metadata of the last Version call.
+	MD metadata.MD
+}
+
+// NewFakeClient returns a Client that sends its requests to svc instead of
+// a server.
+func NewFakeClient(svc *FakeTranscribeService) *Client {
+	return &Client{
+		tclient:          svc,
+		log:              log.NewDiscardLogger(),
+		streamingBufSize: defaultStreamingBufsize,
+	}
+}
+
+// Version returns the fake's VersionString.
+func (f *FakeTranscribeService) Version(ctx context.Context, _ *transcribepb.VersionRequest,
+	_ ...grpc.CallOption) (*transcribepb.VersionResponse, error) {
+	f.MD, _ = metadata.FromOutgoingContext(ctx)
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	return &transcribepb.VersionResponse{Version: f.VersionString}, nil
+}
+
+// ListModels returns the fake's Models.
+func (f *FakeTranscribeService) ListModels(context.Context, *transcribepb.ListModelsRequest,
+	...grpc.CallOption) (*transcribepb.ListModelsResponse, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	return &transcribepb.ListModelsResponse{Models: f.Models}, nil
+}
+
+// StreamingRecognize returns a new stream with the fake's Responses.
+func (f *FakeTranscribeService) StreamingRecognize(context.Context,
+	...grpc.CallOption) (transcribepb.TranscribeService_StreamingRecognizeClient, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	stream := &FakeRecognizeStream{responses: f.Responses, err: f.RecvErr, done: make(chan struct{})}
+	f.Streams = append(f.Streams, stream)
+
+	return stream, nil
+}
+
+// FakeRecognizeStream is a fake streaming recognize client that returns its
+// responses once all the audio has been sent.
+type FakeRecognizeStream struct {
+	grpc.ClientStream
+
+	mu        sync.Mutex
+	requests  []*transcribepb.StreamingRecognizeRequest
+	responses []*transcribepb.StreamingRecognizeResponse
+	err       error
+	done      chan struct{}
+}
+
+// Send records the request.
+func (s *FakeRecognizeStream) Send(req *transcribepb.StreamingRecognizeRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, req)
+
+	return nil
+}
+
+// CloseSend lets Recv return the responses.
+func (s *FakeRecognizeStream) CloseSend() error {
+	close(s.done)
+
+	return nil
+}
+
+// Recv returns the next response once all the audio has been sent.
+func (s *FakeRecognizeStream) Recv() (*transcribepb.StreamingRecognizeResponse, error) {
+	<-s.done
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if len(s.responses) == 0 {
+		return nil, io.EOF
+	}
+
+	resp := s.responses[0]
+	s.responses = s.responses[1:]
+
+	return resp, nil
+}
+
+// Config returns the recognition config sent on the stream, if any.
+func (s *FakeRecognizeStream) Config() *transcribepb.RecognitionConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, req := range s.requests {
+		if cfg := req.GetConfig(); cfg != nil {
+			return cfg
+		}
+	}
+
+	return nil
+}
+
+// Audio returns the audio sent on the stream.
+func (s *FakeRecognizeStream) Audio() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var b strings.Builder
+	for _, req := range s.requests {
+		b.Write(req.GetAudio().GetData())
+	}
+
+	return b.String()
+}
@@ -0,0 +1,145 @@
+// Copyright (2026 -- present) Cobalt Speech and Language, Inc.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"sync"
+	"time"
+
+	transcribepb "github.com/cobaltspeech/go-genproto/cobaltspeech/transcribe/v5"
+)
+
+// ProgressFunc is a callback reporting the progress of StreamingRecognize:
+// the number of audio bytes sent to the server so far, and the end time of
+// the latest result received (zero until a result with timing information
+// arrives).
+type ProgressFunc func(bytesSent int64, lastResultEnd time.Duration)
+
+// progressReporter calls a ProgressFunc from its own goroutine so that a slow
+// callback never blocks streaming. Updates made while the callback is busy
+// are merged, so the callback only sees the latest progress.
+type progressReporter struct {
+	fn ProgressFunc
+
+	mu        sync.Mutex
+	bytesSent int64
+	resultEnd time.Duration
+
+	notify chan struct{}
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// newProgressReporter starts reporting progress to fn. It returns nil if fn
+// is nil; all the methods of a nil reporter do nothing.
+func newProgressReporter(fn ProgressFunc) *progressReporter {
+	if fn == nil {
+		return nil
+	}
+
+	p := &progressReporter{
+		fn:     fn,
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+
+	p.wg.Add(1)
+
+	go p.run()
+
+	return p
+}
+
+func (p *progressReporter) run() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-p.notify:
+			p.report()
+		case <-p.done:
+			// Deliver the last update, if it hasn't been yet.
+			select {
+			case <-p.notify:
+				p.report()
+			default:
+			}
+
+			return
+		}
+	}
+}
+
+func (p *progressReporter) report() {
+	p.mu.Lock()
+	bytesSent, resultEnd := p.bytesSent, p.resultEnd
+	p.mu.Unlock()
+
+	p.fn(bytesSent, resultEnd)
+}
+
+// update signals the goroutine without waiting, dropping the signal if one
+// is already pending.
+func (p *progressReporter) update() {
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+}
+
+// addSent records that n more bytes of audio were sent.
+func (p *progressReporter) addSent(n int) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	p.bytesSent += int64(n)
+	p.mu.Unlock()
+
+	p.update()
+}
+
+// addResult records the end time of the response's result, if it has one.
+func (p *progressReporter) addResult(resp *transcribepb.StreamingRecognizeResponse) {
+	if p == nil || resp == nil || resp.Result == nil || len(resp.Result.Alternatives) == 0 {
+		return
+	}
+
+	alt := resp.Result.Alternatives[0]
+	if alt.DurationMs == 0 {
+		return
+	}
+
+	end := time.Duration(alt.StartTimeMs+alt.DurationMs) * time.Millisecond
+
+	p.mu.Lock()
+	if end > p.resultEnd {
+		p.resultEnd = end
+	}
+	p.mu.Unlock()
+
+	p.update()
+}
+
+// stop delivers the last update and waits for the callback to return.
+func (p *progressReporter) stop() {
+	if p == nil {
+		return
+	}
+
+	close(p.done)
+	p.wg.Wait()
+}